@@ -134,6 +134,8 @@ func initDriverSvc(ctx context.Context, driverName, kubeConfigPath string) (*grp
 	cosispec.RegisterIdentityServer(grpcServer, identityServer)
 	cosispec.RegisterProvisionerServer(grpcServer, provisionerServer)
 
+	provider.RunBackgroundTasks(ctx, provisionerServer)
+
 	return grpcServer, nil
 }
 
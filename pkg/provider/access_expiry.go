@@ -0,0 +1,213 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	accessExpiryInterval = flag.Duration("access-expiry-interval", defaultAccessExpiryInterval,
+		"the interval of deleting the access keys of expired BucketAccesses, 0 means disabled")
+)
+
+const (
+	defaultAccessExpiryInterval = time.Minute
+
+	// accessDurationAnnotation of BucketAccess shortens the accessDuration of its bucketAccessClass,
+	// it never extends the duration limited by the administrator
+	accessDurationAnnotation = "cosi.huawei.com/access-duration"
+
+	// accessExpiredAnnotation of BucketAccess records when its access keys are deleted by the expirer
+	accessExpiredAnnotation = "cosi.huawei.com/access-expired-at"
+)
+
+func checkAccessDurationParameter(parameters map[string]string) error {
+	value, exist := parameters[accessDuration]
+	if !exist {
+		return nil
+	}
+
+	_, err := parseAccessDuration(value)
+	return err
+}
+
+func parseAccessDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s value [%s], it must be a positive duration likes '720h'",
+			accessDuration, value)
+	}
+
+	return duration, nil
+}
+
+// accessExpiry returns the time when the access of BucketAccess ends, which counts from its creation.
+// The duration is the shorter one of the bucketAccessClass and the annotation of BucketAccess,
+// the annotation takes no effect if the bucketAccessClass has no duration.
+// It returns zero time if the access never expires.
+func accessExpiry(ba *v1alpha1.BucketAccess, parameters map[string]string) (time.Time, error) {
+	value, exist := parameters[accessDuration]
+	if !exist {
+		return time.Time{}, nil
+	}
+
+	duration, err := parseAccessDuration(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if value, exist = ba.Annotations[accessDurationAnnotation]; exist {
+		shortened, err := parseAccessDuration(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid annotation [%s] value [%s]", accessDurationAnnotation, value)
+		}
+		duration = min(duration, shortened)
+	}
+
+	return ba.CreationTimestamp.Add(duration).UTC(), nil
+}
+
+// applyAccessExpiry limits the statement to the access duration of BucketAccess by a 'DateLessThan' condition.
+// The expiry counts from the creation of BucketAccess, so the statement is stable for regranting and reconciling.
+func (s *provisionerServer) applyAccessExpiry(ctx context.Context, accountName string, ba *v1alpha1.BucketAccess,
+	parameters map[string]string, statement *policy.Statement) error {
+	if _, exist := parameters[accessDuration]; !exist {
+		return nil
+	}
+
+	ba, err := s.bucketAccessOfGrant(ctx, accountName, ba)
+	if err != nil {
+		return err
+	}
+
+	// the condition is limited to the same expiry as the access keys are deleted at
+	expiry, err := accessExpiry(ba, parameters)
+	if err != nil {
+		return err
+	}
+
+	statement.WithCondition(policy.ConditionDateLessThan, policy.ConditionKeyCurrentTime,
+		expiry.Format(time.RFC3339))
+	return nil
+}
+
+// expireBucketAccesses deletes the access keys of BucketAccesses whose access duration is over,
+// so the access ends even if nobody deletes the BucketAccess
+func (s *provisionerServer) expireBucketAccesses(ctx context.Context) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		log.AddContext(ctx).Errorf("list bucketAccesses failed, error is [%v]", err)
+		return
+	}
+
+	classes := make(map[string]*v1alpha1.BucketAccessClass)
+	for i := range list.Items {
+		ba := &list.Items[i]
+		if !ba.Status.AccessGranted || ba.Status.AccountID == "" || ba.DeletionTimestamp != nil {
+			continue
+		}
+
+		if _, expired := ba.Annotations[accessExpiredAnnotation]; expired {
+			continue
+		}
+
+		bac, exist := classes[ba.Spec.BucketAccessClassName]
+		if !exist {
+			bac, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccessClasses().
+				Get(ctx, ba.Spec.BucketAccessClassName, metaV1.GetOptions{})
+			if err != nil {
+				log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], get bucketAccessClass [%s] failed, "+
+					"error is [%v]", ba.Namespace, ba.Name, ba.Spec.BucketAccessClassName, err)
+				continue
+			}
+			classes[ba.Spec.BucketAccessClassName] = bac
+		}
+
+		if bac.DriverName != s.Provisioner {
+			continue
+		}
+
+		expiry, err := accessExpiry(ba, bac.Parameters)
+		if err != nil {
+			log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], error is [%v]", ba.Namespace, ba.Name, err)
+			continue
+		}
+
+		if expiry.IsZero() || time.Now().Before(expiry) {
+			continue
+		}
+
+		err = s.expireBucketAccess(ctx, ba)
+		if err != nil {
+			log.AddContext(ctx).Errorf("expire bucketAccess [%s/%s] failed, error is [%v]",
+				ba.Namespace, ba.Name, err)
+		}
+	}
+}
+
+// expireBucketAccess deletes the access keys of the user of BucketAccess, and marks the BucketAccess expired
+func (s *provisionerServer) expireBucketAccess(ctx context.Context, ba *v1alpha1.BucketAccess) error {
+	accountIdData, bacAccountSecret, err := fetchDataFromResourceId(ba.Status.AccountID, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", ba.Status.AccountID, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	userName := accountIdData.resourceName
+	listUserAksResp, err := userClient.ListUserAccessKeys(ctx, &api.ListUserAccessKeysInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("list user [%s] access keys failed, error is [%v]", userName, err)
+	}
+
+	for _, accessKey := range listUserAksResp.AccessKeys {
+		_, err = userClient.DeleteUserAccess(ctx, &api.DeleteUserAccessInput{UserName: userName,
+			AccessKeyId: accessKey})
+		if err != nil {
+			return fmt.Errorf("delete user [%s] access key [%s] failed, error is [%v]", userName, accessKey, err)
+		}
+	}
+
+	if ba.Annotations == nil {
+		ba.Annotations = map[string]string{}
+	}
+	ba.Annotations[accessExpiredAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	_, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, ba, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("mark bucketAccess [%s/%s] expired failed, error is [%v]", ba.Namespace, ba.Name, err)
+	}
+
+	log.AddContext(ctx).Infof("access of bucketAccess [%s/%s] expired, access keys of user [%s] are deleted",
+		ba.Namespace, ba.Name, userName)
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+)
+
+func Test_AccessExpiry(t *testing.T) {
+	// arrange
+	created := metaV1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: created}}
+	overridden := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: created,
+		Annotations: map[string]string{accessDurationAnnotation: "1h"}}}
+	extended := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: created,
+		Annotations: map[string]string{accessDurationAnnotation: "48h"}}}
+	parameters := map[string]string{accessDuration: "24h"}
+
+	// act
+	gotNever, gotNeverErr := accessExpiry(ba, map[string]string{})
+	gotUnlimited, gotUnlimitedErr := accessExpiry(overridden, map[string]string{})
+	gotExpiry, gotErr := accessExpiry(ba, parameters)
+	gotOverridden, gotOverriddenErr := accessExpiry(overridden, parameters)
+	gotExtended, gotExtendedErr := accessExpiry(extended, parameters)
+	_, gotInvalidErr := accessExpiry(ba, map[string]string{accessDuration: "-1h"})
+
+	// assert
+	assert.NoError(t, gotNeverErr)
+	assert.True(t, gotNever.IsZero())
+	assert.NoError(t, gotUnlimitedErr)
+	assert.True(t, gotUnlimited.IsZero())
+	assert.NoError(t, gotErr)
+	assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), gotExpiry)
+	assert.NoError(t, gotOverriddenErr)
+	assert.Equal(t, time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC), gotOverridden)
+	assert.NoError(t, gotExtendedErr)
+	assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), gotExtended)
+	assert.Error(t, gotInvalidErr)
+}
+
+func Test_ProvisionerServer_ApplyAccessExpiry(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1",
+		CreationTimestamp: metaV1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))}}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(ba)}
+	statement := buildGrantStatement("ba-uid-1")
+
+	// act
+	gotErr := s.applyAccessExpiry(ctx, "ba-uid-1", nil, map[string]string{accessDuration: "720h"}, statement)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, policy.Condition{policy.ConditionDateLessThan: {
+		policy.ConditionKeyCurrentTime: {"2026-01-31T00:00:00Z"}}}, statement.Condition)
+}
+
+func Test_ProvisionerServer_ExpireBucketAccesses(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	bac := &v1alpha1.BucketAccessClass{ObjectMeta: metaV1.ObjectMeta{Name: "bac-timed"},
+		DriverName: "cosi.huawei.com", Parameters: map[string]string{accessDuration: "1h"}}
+	newBucketAccess := func(name string, created time.Time) *v1alpha1.BucketAccess {
+		return &v1alpha1.BucketAccess{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "app", CreationTimestamp: metaV1.NewTime(created)},
+			Spec:       v1alpha1.BucketAccessSpec{BucketAccessClassName: "bac-timed"},
+			Status: v1alpha1.BucketAccessStatus{AccessGranted: true,
+				AccountID: "default/bac-secret/ba-" + name},
+		}
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(bac,
+		newBucketAccess("expired", time.Now().Add(-2*time.Hour)), newBucketAccess("valid", time.Now()))}
+	var deleted []string
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-expired"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "ListUserAccessKeys", &api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-1"}}, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+		func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+			deleted = append(deleted, input.AccessKeyId)
+			return &api.DeleteUserAccessOutput{}, nil
+		})
+
+	// act
+	s.expireBucketAccesses(ctx)
+	s.expireBucketAccesses(ctx)
+
+	// assert
+	assert.Equal(t, []string{"ak-1"}, deleted)
+	expired, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+		Get(ctx, "expired", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, expired.Annotations, accessExpiredAnnotation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
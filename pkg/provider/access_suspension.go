@@ -0,0 +1,228 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	"sigs.k8s.io/container-object-storage-interface-api/client/informers/externalversions"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	accessSuspensionSyncInterval = flag.Duration("access-suspension-sync-interval",
+		defaultAccessSuspensionSyncInterval, "the interval of resyncing all BucketAccesses while watching their "+
+			"access suspension annotation, the failed ones are retried then, 0 means disabled")
+)
+
+const (
+	defaultAccessSuspensionSyncInterval = 10 * time.Minute
+
+	// accessSuspendedAnnotation of BucketAccess freezes its access keys when it is 'true',
+	// the access keys are activated again when it is removed or 'false'
+	accessSuspendedAnnotation = "cosi.huawei.com/access-suspended"
+
+	// accessKeyStatusAnnotation of BucketAccess records the access key status applied by the driver
+	accessKeyStatusAnnotation = "cosi.huawei.com/access-key-status"
+)
+
+// desiredAccessKeyStatus returns the access key status requested by the suspension annotation of BucketAccess
+func desiredAccessKeyStatus(ba *v1alpha1.BucketAccess) (api.AccessKeyStatus, error) {
+	value, exist := ba.Annotations[accessSuspendedAnnotation]
+	if !exist {
+		return api.AccessKeyStatusActive, nil
+	}
+
+	suspended, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid annotation [%s] value [%s]", accessSuspendedAnnotation, value)
+	}
+
+	if suspended {
+		return api.AccessKeyStatusInactive, nil
+	}
+	return api.AccessKeyStatusActive, nil
+}
+
+// accessSuspended checks whether the access keys of BucketAccess are requested or applied to be inactive
+func accessSuspended(ba *v1alpha1.BucketAccess) bool {
+	desired, err := desiredAccessKeyStatus(ba)
+	return err != nil || desired == api.AccessKeyStatusInactive ||
+		ba.Annotations[accessKeyStatusAnnotation] == string(api.AccessKeyStatusInactive)
+}
+
+// newAccessKeyStatus returns the status of the access key issued for BucketAccess,
+// the access key is inactive while the access is suspended, so it never works before the suspension is lifted
+func newAccessKeyStatus(ba *v1alpha1.BucketAccess) api.AccessKeyStatus {
+	if accessSuspended(ba) {
+		return api.AccessKeyStatusInactive
+	}
+	return api.AccessKeyStatusActive
+}
+
+// createAccessKey creates a new access key of user in the status, the access key is deleted if its status
+// can not be updated, so that an access key never works against the suspension
+func createAccessKey(ctx context.Context, userClient api.UserAPI, userName string,
+	status api.AccessKeyStatus) (*api.CreateUserAccessOutput, error) {
+	accessResp, err := userClient.CreateUserAccess(ctx, &api.CreateUserAccessInput{UserName: userName})
+	if err != nil {
+		return nil, fmt.Errorf("create user [%s] access failed, error is [%v]", userName, err)
+	}
+
+	// access keys are active when they are created
+	if status == api.AccessKeyStatusActive {
+		return accessResp, nil
+	}
+
+	_, err = userClient.UpdateAccessKeyStatus(ctx, &api.UpdateAccessKeyStatusInput{UserName: userName,
+		AccessKeyId: accessResp.AccessKeyId, Status: status})
+	if err == nil {
+		return accessResp, nil
+	}
+
+	_, deleteErr := userClient.DeleteUserAccess(ctx, &api.DeleteUserAccessInput{UserName: userName,
+		AccessKeyId: accessResp.AccessKeyId})
+	if deleteErr != nil {
+		log.AddContext(ctx).Warningf("delete access key [%s] of user [%s] failed, error is [%v]",
+			accessResp.AccessKeyId, userName, deleteErr)
+	}
+	return nil, fmt.Errorf("update user [%s] access key [%s] status to [%s] failed, error is [%v]",
+		userName, accessResp.AccessKeyId, status, err)
+}
+
+// watchAccessSuspensions applies the suspension annotation of BucketAccesses to their access keys as soon as
+// the BucketAccesses change, so the access is frozen and restored without issuing new credentials.
+// All BucketAccesses are resynced every interval, the failed ones are retried then. It returns when ctx is done.
+func (s *provisionerServer) watchAccessSuspensions(ctx context.Context, resync time.Duration) {
+	factory := externalversions.NewSharedInformerFactory(s.BucketClient, resync)
+	informer := factory.Objectstorage().V1alpha1().BucketAccesses().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s.onBucketAccessSuspensionChanged(ctx, obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			s.onBucketAccessSuspensionChanged(ctx, obj)
+		},
+	})
+	if err != nil {
+		log.AddContext(ctx).Errorf("watch access suspensions of bucketAccesses failed, error is [%v]", err)
+		return
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	log.AddContext(ctx).Infof("watching access suspensions of bucketAccesses stopped")
+}
+
+func (s *provisionerServer) onBucketAccessSuspensionChanged(ctx context.Context, obj interface{}) {
+	ba, ok := obj.(*v1alpha1.BucketAccess)
+	if !ok {
+		return
+	}
+
+	eventCtx, err := log.SetRequestInfo(ctx)
+	if err != nil {
+		eventCtx = ctx
+	}
+	defer utils.RecoverPanic(eventCtx)
+
+	// the objects of informer cache are shared, they must not be modified
+	s.syncAccessSuspension(eventCtx, ba.DeepCopy())
+}
+
+// syncAccessSuspension applies the suspension annotation of BucketAccess to its access keys
+func (s *provisionerServer) syncAccessSuspension(ctx context.Context, ba *v1alpha1.BucketAccess) {
+	if !ba.Status.AccessGranted || ba.Status.AccountID == "" || ba.DeletionTimestamp != nil {
+		return
+	}
+
+	desired, err := desiredAccessKeyStatus(ba)
+	if err != nil {
+		log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], error is [%v]", ba.Namespace, ba.Name, err)
+		return
+	}
+
+	// access keys are active when they are created, so the missing status is regarded as active
+	applied := api.AccessKeyStatus(ba.Annotations[accessKeyStatusAnnotation])
+	if applied == "" {
+		applied = api.AccessKeyStatusActive
+	}
+	if applied == desired {
+		return
+	}
+
+	err = s.updateBucketAccessKeyStatus(ctx, ba, desired)
+	if err != nil {
+		log.AddContext(ctx).Errorf("update access key status of bucketAccess [%s/%s] to [%s] failed, "+
+			"error is [%v]", ba.Namespace, ba.Name, desired, err)
+	}
+}
+
+// updateBucketAccessKeyStatus updates all access keys of the user of BucketAccess to the status,
+// and records the applied status in the annotation of BucketAccess
+func (s *provisionerServer) updateBucketAccessKeyStatus(ctx context.Context, ba *v1alpha1.BucketAccess,
+	status api.AccessKeyStatus) error {
+	accountIdData, bacAccountSecret, err := fetchDataFromResourceId(ba.Status.AccountID, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", ba.Status.AccountID, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	userName := accountIdData.resourceName
+	listUserAksResp, err := userClient.ListUserAccessKeys(ctx, &api.ListUserAccessKeysInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("list user [%s] access keys failed, error is [%v]", userName, err)
+	}
+
+	for _, accessKey := range listUserAksResp.AccessKeys {
+		_, err = userClient.UpdateAccessKeyStatus(ctx, &api.UpdateAccessKeyStatusInput{UserName: userName,
+			AccessKeyId: accessKey, Status: status})
+		if err != nil {
+			return fmt.Errorf("update user [%s] access key [%s] status failed, error is [%v]",
+				userName, accessKey, err)
+		}
+	}
+
+	if ba.Annotations == nil {
+		ba.Annotations = map[string]string{}
+	}
+	ba.Annotations[accessKeyStatusAnnotation] = string(status)
+	_, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, ba, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("record access key status of bucketAccess [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Name, err)
+	}
+
+	log.AddContext(ctx).Infof("update access keys of user [%s] for bucketAccess [%s/%s] to [%s] successfully",
+		userName, ba.Namespace, ba.Name, status)
+	return nil
+}
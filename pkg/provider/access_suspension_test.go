@@ -0,0 +1,181 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+)
+
+func Test_DesiredAccessKeyStatus(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		wantStatus  api.AccessKeyStatus
+		wantErr     bool
+	}{
+		{name: "not annotated", wantStatus: api.AccessKeyStatusActive},
+		{name: "suspended", annotations: map[string]string{accessSuspendedAnnotation: "true"},
+			wantStatus: api.AccessKeyStatusInactive},
+		{name: "resumed", annotations: map[string]string{accessSuspendedAnnotation: "false"},
+			wantStatus: api.AccessKeyStatusActive},
+		{name: "invalid", annotations: map[string]string{accessSuspendedAnnotation: "frozen"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Annotations: c.annotations}}
+
+			// act
+			gotStatus, gotErr := desiredAccessKeyStatus(ba)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+			assert.Equal(t, c.wantStatus, gotStatus)
+		})
+	}
+}
+
+func Test_ProvisionerServer_WatchAccessSuspensions(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.TODO())
+	c := &poe.Client{}
+	newBucketAccess := func(name string, annotations map[string]string) *v1alpha1.BucketAccess {
+		return &v1alpha1.BucketAccess{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "app", Annotations: annotations},
+			Status: v1alpha1.BucketAccessStatus{AccessGranted: true,
+				AccountID: "default/bac-secret/ba-" + name},
+		}
+	}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(
+		newBucketAccess("suspending", map[string]string{accessSuspendedAnnotation: "true"}),
+		newBucketAccess("suspended", map[string]string{accessSuspendedAnnotation: "true",
+			accessKeyStatusAnnotation: string(api.AccessKeyStatusInactive)}),
+		newBucketAccess("active", nil))}
+	var updated []api.UpdateAccessKeyStatusInput
+	var updatedLock sync.Mutex
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-suspending"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "ListUserAccessKeys", &api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-1"}}, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "UpdateAccessKeyStatus",
+		func(_ *poe.Client, _ context.Context,
+			input *api.UpdateAccessKeyStatusInput) (*api.UpdateAccessKeyStatusOutput, error) {
+			updatedLock.Lock()
+			defer updatedLock.Unlock()
+			updated = append(updated, *input)
+			return &api.UpdateAccessKeyStatusOutput{}, nil
+		})
+
+	// act
+	go s.watchAccessSuspensions(ctx, time.Hour)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		gotBa, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+			Get(ctx, "suspending", metaV1.GetOptions{})
+		return err == nil && gotBa.Annotations[accessKeyStatusAnnotation] == string(api.AccessKeyStatusInactive)
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	updatedLock.Lock()
+	defer updatedLock.Unlock()
+	assert.Equal(t, []api.UpdateAccessKeyStatusInput{{UserName: "ba-suspending", AccessKeyId: "ak-1",
+		Status: api.AccessKeyStatusInactive}}, updated)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CreateAccessKey_Inactive(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	var updated []api.UpdateAccessKeyStatusInput
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(c, "CreateUserAccess",
+		&api.CreateUserAccessOutput{AccessKeyId: "ak-new", SecretAccessKey: "sk-new"}, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "UpdateAccessKeyStatus",
+		func(_ *poe.Client, _ context.Context,
+			input *api.UpdateAccessKeyStatusInput) (*api.UpdateAccessKeyStatusOutput, error) {
+			updated = append(updated, *input)
+			return &api.UpdateAccessKeyStatusOutput{}, nil
+		})
+
+	// act
+	gotResp, gotErr := createAccessKey(ctx, c, "ba-uid-1", api.AccessKeyStatusInactive)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, "ak-new", gotResp.AccessKeyId)
+	assert.Equal(t, []api.UpdateAccessKeyStatusInput{{UserName: "ba-uid-1", AccessKeyId: "ak-new",
+		Status: api.AccessKeyStatusInactive}}, updated)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CreateAccessKey_DeactivateFailed(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	var deleted []string
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(c, "CreateUserAccess",
+		&api.CreateUserAccessOutput{AccessKeyId: "ak-new", SecretAccessKey: "sk-new"}, nil)
+	mock.ApplyMethodReturn(c, "UpdateAccessKeyStatus", nil, errors.New("mock error"))
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+		func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+			deleted = append(deleted, input.AccessKeyId)
+			return &api.DeleteUserAccessOutput{}, nil
+		})
+
+	// act
+	gotResp, gotErr := createAccessKey(ctx, c, "ba-uid-1", api.AccessKeyStatusInactive)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "status to [Inactive] failed")
+	assert.Nil(t, gotResp)
+	assert.Equal(t, []string{"ak-new"}, deleted)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,99 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"time"
+
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/utils"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// backgroundTask is a task running periodically in the background of the driver
+type backgroundTask struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context)
+}
+
+// RunBackgroundTasks starts the periodic background tasks of provisioner server, they stop when ctx is done
+func RunBackgroundTasks(ctx context.Context, ps cosispec.ProvisionerServer) {
+	s, ok := ps.(*provisionerServer)
+	if !ok {
+		log.AddContext(ctx).Warningf("provisioner server [%T] has no background task", ps)
+		return
+	}
+
+	for _, task := range s.backgroundTasks() {
+		if task.interval <= 0 {
+			log.AddContext(ctx).Infof("background task [%s] is disabled", task.name)
+			continue
+		}
+
+		log.AddContext(ctx).Infof("start background task [%s], interval is [%v]", task.name, task.interval)
+		go runPeriodically(ctx, task)
+	}
+
+	if *accessSuspensionSyncInterval <= 0 {
+		log.AddContext(ctx).Infof("background task [access suspension] is disabled")
+		return
+	}
+
+	log.AddContext(ctx).Infof("start watching access suspensions, resync interval is [%v]",
+		*accessSuspensionSyncInterval)
+	go s.watchAccessSuspensions(ctx, *accessSuspensionSyncInterval)
+}
+
+func (s *provisionerServer) backgroundTasks() []backgroundTask {
+	return []backgroundTask{
+		{name: "bucket policy restore", interval: *bucketPolicyRestoreInterval, run: s.handlePolicyRestoreRequests},
+		{name: "bucket policy reconcile", interval: *bucketPolicyReconcileInterval, run: s.reconcileBucketPolicies},
+		{name: "access expiry", interval: *accessExpiryInterval, run: s.expireBucketAccesses},
+		{name: "access key rotation", interval: *keyRotationCheckInterval, run: s.rotateAccessKeys},
+		{name: "user gc", interval: *userGcInterval, run: s.collectUserGarbage},
+		{name: "user gc request", interval: *userGcRequestInterval, run: s.handleUserGcRequests},
+		{name: "bucket adoption validation", interval: *bucketAdoptionValidationInterval,
+			run: s.handleBucketAdoptionValidations},
+	}
+}
+
+func runPeriodically(ctx context.Context, task backgroundTask) {
+	ticker := time.NewTicker(task.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.AddContext(ctx).Infof("background task [%s] stopped", task.name)
+			return
+		case <-ticker.C:
+			runOnce(ctx, task)
+		}
+	}
+}
+
+func runOnce(ctx context.Context, task backgroundTask) {
+	taskCtx, err := log.SetRequestInfo(ctx)
+	if err != nil {
+		taskCtx = ctx
+	}
+	defer utils.RecoverPanic(taskCtx)
+
+	task.run(taskCtx)
+}
@@ -0,0 +1,39 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RunOnce_RecoverPanic(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	var ran bool
+	task := backgroundTask{name: "panic task", run: func(context.Context) {
+		ran = true
+		panic("mock panic")
+	}}
+
+	// act & assert
+	assert.NotPanics(t, func() {
+		runOnce(ctx, task)
+	})
+	assert.True(t, ran)
+}
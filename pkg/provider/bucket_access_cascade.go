@@ -0,0 +1,284 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/errors"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+func checkCascadeDeleteParameters(parameters map[string]string) error {
+	value, exist := parameters[cascadeDeleteAccess]
+	if !exist {
+		return nil
+	}
+
+	if _, err := strconv.ParseBool(value); err != nil {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s]", cascadeDeleteAccess, value))
+	}
+
+	return nil
+}
+
+// cascadeDeleteAccessEnabled returns whether the access of the bucket is cleaned up when it is deleted,
+// false by default
+func cascadeDeleteAccessEnabled(parameters map[string]string) bool {
+	enabled, err := strconv.ParseBool(parameters[cascadeDeleteAccess])
+	return err == nil && enabled
+}
+
+// getBucketByBucketId finds the Bucket of the driver by its bucket id, including the one being deleted,
+// nil is returned if it is not found
+func (s *provisionerServer) getBucketByBucketId(ctx context.Context, bucketId string) (*v1alpha1.Bucket, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list buckets failed, error is [%v]", err)
+	}
+
+	for i := range list.Items {
+		bucket := &list.Items[i]
+		if bucket.Spec.DriverName == s.Provisioner &&
+			(bucket.Status.BucketID == bucketId || bucket.Spec.ExistingBucketID == bucketId) {
+			return bucket, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// cascadeDeleteBucketAccess removes the users granted on the bucket before the bucket is deleted, if it is enabled
+// by the parameters of Bucket. The users are read from the bucket itself, the principals of the bucket policy and
+// the grantees of the bucket acl, because the BucketAccesses are gone when the revoking requests never arrive.
+// Only the users created by the driver of this cluster and referred to by no BucketAccess are removed together
+// with their access keys, so the access does not outlive the bucket. The users granted by user policy or groups
+// are collected by the user gc once no BucketAccess refers to them.
+func (s *provisionerServer) cascadeDeleteBucketAccess(ctx context.Context, bucketId string,
+	bcAccountSecret *coreV1.Secret, s3Agent *agent.S3Agent, bucketName string) error {
+	bucket, err := s.getBucketByBucketId(ctx, bucketId)
+	if err != nil {
+		return err
+	}
+
+	if bucket == nil || !cascadeDeleteAccessEnabled(bucket.Spec.Parameters) {
+		return nil
+	}
+
+	// the grants of bucket are not changed during the cleanup
+	s.keyLock.Lock(bucketId)
+	defer s.keyLock.Unlock(bucketId)
+
+	userNames, err := bucketGrantedUsers(ctx, bcAccountSecret, s3Agent, bucketName)
+	if err != nil {
+		return err
+	}
+
+	if len(userNames) == 0 {
+		log.AddContext(ctx).Infof("bucket [%s] has no user granted, skip cascade cleanup of access", bucketName)
+		return nil
+	}
+
+	// the users of the bucket are created in the account of the bucket if their BucketAccesses are gone
+	inUse, err := s.listBucketAccessUsers(ctx, bcAccountSecret)
+	if err != nil {
+		return err
+	}
+
+	for _, userName := range userNames {
+		err = s.cascadeDeleteUser(ctx, bcAccountSecret, userName, inUse)
+		if err != nil {
+			return fmt.Errorf("cascade cleanup of user [%s] failed, error is [%v]", userName, err)
+		}
+	}
+
+	return nil
+}
+
+// bucketGrantedUsers returns the sorted names of the users in the account of bucket,
+// which are the principals of the bucket policy or the grantees of the bucket acl
+func bucketGrantedUsers(ctx context.Context, bcAccountSecret *coreV1.Secret, s3Agent *agent.S3Agent,
+	bucketName string) ([]string, error) {
+	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket,
+		errors.ErrNoSuchBucketPolicy, errors.ErrNotImplemented, errors.ErrMethodNotAllowed))
+	if err != nil {
+		return nil, fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	users := make(map[string]bool)
+	if bp != nil {
+		for _, principal := range bp.Principals() {
+			if userName := policy.UserNameFromArn(principal); userName != "" {
+				users[userName] = true
+			}
+		}
+	}
+
+	aclUsers, err := bucketAclGrantedUsers(ctx, bcAccountSecret, s3Agent, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	for _, userName := range aclUsers {
+		users[userName] = true
+	}
+
+	userNames := make([]string, 0, len(users))
+	for userName := range users {
+		userNames = append(userNames, userName)
+	}
+	sort.Strings(userNames)
+	return userNames, nil
+}
+
+// bucketAclGrantedUsers returns the names of the users granted in the bucket acl, the grantees are identified by
+// the ids of users. The acl is skipped if it can not be read, e.g. the backend does not support it.
+func bucketAclGrantedUsers(ctx context.Context, bcAccountSecret *coreV1.Secret, s3Agent *agent.S3Agent,
+	bucketName string) ([]string, error) {
+	acp, err := s3Agent.GetBucketAcl(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket))
+	if err != nil {
+		log.AddContext(ctx).Warningf("skip the acl grantees of bucket [%s], error is [%v]", bucketName, err)
+		return nil, nil
+	}
+
+	if acp == nil {
+		return nil, nil
+	}
+
+	grantees := make(map[string]bool)
+	for _, id := range agent.CanonicalUserIds(acp) {
+		grantees[id] = true
+	}
+
+	if len(grantees) == 0 {
+		return nil, nil
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bcAccountSecret)
+	if err != nil {
+		return nil, fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	listUsersResp, err := userClient.ListUsers(ctx, &api.ListUsersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("list users failed, error is [%v]", err)
+	}
+
+	var userNames []string
+	for _, u := range listUsersResp.Users {
+		if u.UserID != "" && grantees[u.UserID] {
+			userNames = append(userNames, u.UserName)
+		}
+	}
+
+	return userNames, nil
+}
+
+// bucketAccessUser is a backend user referred to by a BucketAccess,
+// the account secret is empty if the account of the user is unknown yet
+type bucketAccessUser struct {
+	accountSecret string
+	userName      string
+}
+
+// listBucketAccessUsers lists the backend users referred to by the BucketAccesses. The user of AccountId is keyed
+// by the account secret it is created in, so the users of the same name on other backends are not mixed up.
+// The BucketAccesses being granted have no AccountId, their users are keyed by the names generated for them.
+func (s *provisionerServer) listBucketAccessUsers(ctx context.Context, accountSecret *coreV1.Secret) (
+	map[bucketAccessUser]bool, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list bucketAccesses failed, error is [%v]", err)
+	}
+
+	users := make(map[bucketAccessUser]bool)
+	for i := range list.Items {
+		ba := &list.Items[i]
+		accountIdData, err := disassembleResourceId(ba.Status.AccountID)
+		if err != nil {
+			accountName := bucketAccessAccountName(ba)
+			users[bucketAccessUser{userName: accountName}] = true
+			users[bucketAccessUser{userName: backendUserName(accountName, accountSecret)}] = true
+			continue
+		}
+
+		namespace, name, _, err := redirectAccountSecret(ctx, s.K8sClient, accountIdData.acSecretNameSpace,
+			accountIdData.acSecretName)
+		if err != nil {
+			return nil, err
+		}
+		users[bucketAccessUser{accountSecret: namespace + "/" + name, userName: accountIdData.resourceName}] = true
+	}
+
+	return users, nil
+}
+
+func (s *provisionerServer) cascadeDeleteUser(ctx context.Context, accountSecret *coreV1.Secret, userName string,
+	inUse map[bucketAccessUser]bool) error {
+	if inUse[bucketAccessUser{accountSecret: accountSecret.Namespace + "/" + accountSecret.Name,
+		userName: userName}] || inUse[bucketAccessUser{userName: userName}] {
+		log.AddContext(ctx).Infof("user [%s] is referred to by bucketAccess, keep it", userName)
+		return nil
+	}
+
+	owned, err := userOwnedByDriver(ctx, accountSecret, userName, s.Provisioner)
+	if err != nil {
+		return err
+	}
+
+	if !owned {
+		log.AddContext(ctx).Infof("user [%s] does not exist or is not owned by driver [%s] of cluster [%s], "+
+			"keep it", userName, s.Provisioner, *clusterId)
+		return nil
+	}
+
+	err = removeUser(ctx, accountSecret, userName, s.Provisioner)
+	if err != nil {
+		return err
+	}
+
+	log.AddContext(ctx).Infof("remove user [%s] and its access keys along with the bucket", userName)
+	return nil
+}
+
+// userOwnedByDriver checks whether the user exists and is created by the driver of this cluster
+func userOwnedByDriver(ctx context.Context, accountSecret *coreV1.Secret, userName, driverName string) (bool,
+	error) {
+	userClient, err := buildClientFromSecret(ctx, accountSecret)
+	if err != nil {
+		return false, fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	getUserResp, err := userClient.GetUser(ctx, &api.GetUserInput{UserName: userName})
+	if err != nil {
+		return false, fmt.Errorf("get user [%s] failed, error is [%v]", userName, err)
+	}
+
+	return getUserResp != nil && ownsUser(getUserResp.Ownership, driverName), nil
+}
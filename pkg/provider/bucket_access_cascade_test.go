@@ -0,0 +1,159 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+	"github.com/huawei/cosi-driver/pkg/utils/keylock"
+)
+
+func Test_CheckCascadeDeleteParameters(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    bool
+	}{
+		{name: "not set", parameters: map[string]string{}},
+		{name: "enabled", parameters: map[string]string{cascadeDeleteAccess: "true"}},
+		{name: "invalid", parameters: map[string]string{cascadeDeleteAccess: "always"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotErr := checkCascadeDeleteParameters(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+		})
+	}
+}
+
+func Test_ProvisionerServer_CascadeDeleteBucketAccess_Disabled(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucketId := "cosi:v2:default/bc-secret/bucket-demo"
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "bucket-demo"},
+		Spec:       v1alpha1.BucketSpec{DriverName: "cosi.huawei.com"},
+		Status:     v1alpha1.BucketStatus{BucketID: bucketId},
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(bucket)}
+	s3Agent := &agent.S3Agent{}
+	policyRead := false
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Agent), "GetBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ []string) (*policy.BucketPolicy,
+			error) {
+			policyRead = true
+			return nil, nil
+		})
+
+	// act
+	gotErr := s.cascadeDeleteBucketAccess(ctx, bucketId, &coreV1.Secret{}, s3Agent, "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.False(t, policyRead)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CascadeDeleteBucketAccess_Enabled(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucketId := "cosi:v2:default/bc-secret/bucket-demo"
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "bucket-demo"},
+		Spec: v1alpha1.BucketSpec{DriverName: "cosi.huawei.com",
+			Parameters: map[string]string{cascadeDeleteAccess: "true"}},
+		Status: v1alpha1.BucketStatus{BucketID: bucketId},
+	}
+	live := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-live", Namespace: "app"},
+		Status:     v1alpha1.BucketAccessStatus{AccountID: "default/bc-secret/user-live"},
+	}
+	otherBackend := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-other-backend", Namespace: "app"},
+		Status:     v1alpha1.BucketAccessStatus{AccountID: "default/other-secret/user-orphan"},
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", keyLock: keylock.NewKeyLock(keyLockSize),
+		K8sClient: fake.NewSimpleClientset(), BucketClient: cosifake.NewSimpleClientset(bucket, live, otherBackend)}
+	bcAccountSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "bc-secret"},
+		Data: map[string][]byte{ak: []byte("accessKey123"), sk: []byte("secretKey123"),
+			endpoint: []byte("https://xxxx.com:8088")}}
+	s3Agent := &agent.S3Agent{}
+	bp := &policy.BucketPolicy{Statement: []policy.Statement{{
+		Sid: "consolidated",
+		Principal: map[string][]string{"AWS": {"arn:aws:iam::1:user/user-orphan",
+			"arn:aws:iam::1:user/user-live", "arn:aws:iam::1:user/user-foreign"}},
+	}}}
+	acp := &s3.AccessControlPolicy{Grants: []*s3.Grant{{
+		Grantee:    &s3.Grantee{ID: aws.String("id-acl"), Type: aws.String(s3.TypeCanonicalUser)},
+		Permission: aws.String(s3.PermissionRead),
+	}}}
+	c := &poe.Client{}
+	var removed []string
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "GetBucketPolicy", bp, nil)
+	mock.ApplyMethodReturn(s3Agent, "GetBucketAcl", acp, nil)
+	mock.ApplyFuncReturn(user.NewUserClient, c, nil)
+	mock.ApplyMethodReturn(c, "ListUsers", &api.ListUsersOutput{Users: []api.User{
+		{UserName: "user-acl", UserID: "id-acl"}, {UserName: "user-other", UserID: "id-other"}}}, nil)
+	mock.ApplyFunc(userOwnedByDriver, func(_ context.Context, _ *coreV1.Secret, userName, _ string) (bool, error) {
+		return userName != "user-foreign", nil
+	})
+	mock.ApplyFunc(removeUser, func(_ context.Context, _ *coreV1.Secret, userName, _ string) error {
+		removed = append(removed, userName)
+		return nil
+	})
+
+	// act
+	gotErr := s.cascadeDeleteBucketAccess(ctx, bucketId, bcAccountSecret, s3Agent, "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"user-acl", "user-orphan"}, removed)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,145 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/errors"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// bucketAclPermissions maps the bucket policy model to the permissions of bucket acl, default is rw model
+func bucketAclPermissions(parameters map[string]string) []string {
+	if parameters[bucketPolicyModel] == bucketPolicyModelRO {
+		return []string{s3.PermissionRead}
+	}
+
+	return []string{s3.PermissionRead, s3.PermissionWrite}
+}
+
+// setBucketAcl grants the user access to bucket by the acl grants of its canonical user id,
+// it is for the storages which reject bucket policies.
+func setBucketAcl(ctx context.Context, bcAccountSecret *coreV1.Secret, bucketName, userName, canonicalId string,
+	permissions []string) error {
+	if canonicalId == "" {
+		return fmt.Errorf("canonical id of user [%s] is empty", userName)
+	}
+
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	acp, err := s3Agent.GetBucketAcl(ctx, bucketName, errors.EmptyExceptionalErrCodes)
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] acl failed, error is [%v]", bucketName, err)
+	}
+
+	err = s3Agent.PutBucketAcl(ctx, bucketName, agent.SetCanonicalUserGrants(acp, canonicalId, permissions...),
+		errors.EmptyExceptionalErrCodes)
+	if err != nil {
+		return fmt.Errorf("put bucket [%s] acl about user [%s] failed, error is [%v]", bucketName, userName, err)
+	}
+
+	// read back the acl to make sure that the user actually has the intended permissions
+	acp, err = s3Agent.GetBucketAcl(ctx, bucketName, errors.EmptyExceptionalErrCodes)
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] acl for verification failed, error is [%v]", bucketName, err)
+	}
+
+	got := agent.CanonicalUserPermissions(acp, canonicalId)
+	if !reflect.DeepEqual(got, permissions) {
+		return fmt.Errorf("verify bucket [%s] acl failed, permissions of user [%s] are expected to be %v, "+
+			"but got %v", bucketName, userName, permissions, got)
+	}
+
+	log.AddContext(ctx).Infof("set bucket [%s] acl about user [%s] successfully", bucketName, userName)
+	return nil
+}
+
+// removeBucketAclGrantee removes the acl grants of the user from bucket, other grantees are kept unchanged.
+// It must be called before the user is deleted, because the grantee is identified by the id of user.
+func removeBucketAclGrantee(ctx context.Context, bcAccountSecret, bacAccountSecret *coreV1.Secret,
+	bucketName, userName string) error {
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	getUserResp, err := userClient.GetUser(ctx, &api.GetUserInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("get user [%s] failed, error is [%v]", userName, err)
+	}
+
+	if getUserResp == nil || getUserResp.UserID == "" {
+		log.AddContext(ctx).Infof("user [%s] not exist, skip remove bucket acl operation", userName)
+		return nil
+	}
+
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	// nothing is granted by acl on the backends rejecting the acl apis
+	acp, err := s3Agent.GetBucketAcl(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket,
+		errors.ErrNotImplemented, errors.ErrMethodNotAllowed))
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] acl failed, error is [%v]", bucketName, err)
+	}
+
+	if acp == nil {
+		log.AddContext(ctx).Infof("bucket [%s] not exist or has no acl, skip remove bucket acl operation",
+			bucketName)
+		return nil
+	}
+
+	editedAcp, removed := agent.RemoveCanonicalUserGrants(acp, getUserResp.UserID)
+	if !removed {
+		log.AddContext(ctx).Infof("bucket [%s] acl has no grant about user [%s], "+
+			"skip remove bucket acl operation", bucketName, userName)
+		return nil
+	}
+
+	err = s3Agent.PutBucketAcl(ctx, bucketName, editedAcp, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket))
+	if err != nil {
+		return fmt.Errorf("remove bucket [%s] acl about user [%s] failed, error is [%v]", bucketName, userName, err)
+	}
+
+	log.AddContext(ctx).Infof("remove bucket [%s] acl about user [%s] successfully", bucketName, userName)
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+)
+
+func buildCanonicalUserGrant(canonicalId, permission string) *s3.Grant {
+	return &s3.Grant{
+		Grantee:    &s3.Grantee{ID: aws.String(canonicalId), Type: aws.String(s3.TypeCanonicalUser)},
+		Permission: aws.String(permission),
+	}
+}
+
+func Test_BucketAclPermissions(t *testing.T) {
+	// act
+	gotRO := bucketAclPermissions(map[string]string{bucketPolicyModel: bucketPolicyModelRO})
+	gotDefault := bucketAclPermissions(map[string]string{})
+
+	// assert
+	assert.Equal(t, []string{s3.PermissionRead}, gotRO)
+	assert.Equal(t, []string{s3.PermissionRead, s3.PermissionWrite}, gotDefault)
+}
+
+func Test_SetBucketAcl_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	ownerGrant := buildCanonicalUserGrant("owner-id", s3.PermissionFullControl)
+	stored := &s3.AccessControlPolicy{Owner: &s3.Owner{ID: aws.String("owner-id")}, Grants: []*s3.Grant{ownerGrant}}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "GetBucketAcl",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ []string) (*s3.AccessControlPolicy, error) {
+			return stored, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketAcl",
+		func(_ *agent.S3Agent, _ context.Context, _ string, acp *s3.AccessControlPolicy, _ []string) error {
+			stored = acp
+			return nil
+		})
+
+	// act
+	gotErr := setBucketAcl(ctx, &coreV1.Secret{}, "bucket-demo", "user-demo", "user-id",
+		[]string{s3.PermissionRead, s3.PermissionWrite})
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []*s3.Grant{ownerGrant, buildCanonicalUserGrant("user-id", s3.PermissionRead),
+		buildCanonicalUserGrant("user-id", s3.PermissionWrite)}, stored.Grants)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RemoveBucketAclGrantee_OnlyRemoveOwnGrantee(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	userClient := &poe.Client{}
+	ownerGrant := buildCanonicalUserGrant("owner-id", s3.PermissionFullControl)
+	otherGrant := buildCanonicalUserGrant("other-id", s3.PermissionRead)
+	stored := &s3.AccessControlPolicy{Owner: &s3.Owner{ID: aws.String("owner-id")}, Grants: []*s3.Grant{ownerGrant,
+		buildCanonicalUserGrant("user-id", s3.PermissionRead), otherGrant}}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, userClient, nil)
+	mock.ApplyMethodReturn(userClient, "Close", nil)
+	mock.ApplyMethodReturn(userClient, "GetUser", &api.GetUserOutput{UserName: "user-demo", UserID: "user-id"}, nil)
+	mock.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "GetBucketAcl",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ []string) (*s3.AccessControlPolicy, error) {
+			return stored, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketAcl",
+		func(_ *agent.S3Agent, _ context.Context, _ string, acp *s3.AccessControlPolicy, _ []string) error {
+			stored = acp
+			return nil
+		})
+
+	// act
+	gotErr := removeBucketAclGrantee(ctx, &coreV1.Secret{}, &coreV1.Secret{}, "bucket-demo", "user-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []*s3.Grant{ownerGrant, otherGrant}, stored.Grants)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,318 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// A bucket which is not created by the driver is adopted only if it exists, is owned by the account of the
+// account secret, and carries the tag of adoptionTag parameter if it is set. Static Buckets are checked when
+// the driver grants access to them for the first time, and they are checked on demand by annotating the
+// Bucket with 'cosi.huawei.com/validate-adoption=true', the result is reported in the annotation
+// 'cosi.huawei.com/adoption-result'.
+
+var (
+	bucketAdoptionValidationInterval = flag.Duration("bucket-adoption-validation-interval",
+		defaultBucketAdoptionValidationInterval, "the interval of handling bucket adoption validation "+
+			"requests, 0 means disabled")
+)
+
+const (
+	defaultBucketAdoptionValidationInterval = 30 * time.Second
+
+	// bucketCreatedByTag is tagged on the buckets created by the driver, its value is the name of create request,
+	// so the retried create request is not regarded as adopting
+	bucketCreatedByTag = "cosi.huawei.com/bucket"
+
+	// adoptedAtAnnotation of Bucket records when the existing bucket passed the adoption check
+	adoptedAtAnnotation = "cosi.huawei.com/adopted-at"
+
+	adoptionValidationAnnotation = "cosi.huawei.com/validate-adoption"
+	adoptionResultAnnotation     = "cosi.huawei.com/adoption-result"
+	adoptionResultSucceeded      = "succeeded"
+)
+
+func checkAdoptionParameters(parameters map[string]string) error {
+	if value, exist := parameters[adoptExisting]; exist {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s]", adoptExisting, value))
+		}
+	}
+
+	if _, _, err := parseAdoptionTag(parameters); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// adoptExistingEnabled returns whether the existing bucket owned by the account is adopted, true by default
+func adoptExistingEnabled(parameters map[string]string) bool {
+	value, exist := parameters[adoptExisting]
+	if !exist {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && enabled
+}
+
+// parseAdoptionTag parses the adoptionTag parameter likes 'key=value', the key is empty if it is not set
+func parseAdoptionTag(parameters map[string]string) (string, string, error) {
+	value, exist := parameters[adoptionTag]
+	if !exist {
+		return "", "", nil
+	}
+
+	key, tagValue, found := strings.Cut(value, "=")
+	if !found || strings.TrimSpace(key) == "" {
+		return "", "", utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], the format should be "+
+			"'key=value'", adoptionTag, value))
+	}
+
+	return strings.TrimSpace(key), strings.TrimSpace(tagValue), nil
+}
+
+// checkBucketAdoption checks whether the existing bucket can be adopted by the account of s3 agent
+func checkBucketAdoption(ctx context.Context, s3Agent *agent.S3Agent, bucketName string,
+	parameters map[string]string) error {
+	err := s3Agent.CheckBucketExist(ctx, bucketName)
+	if err != nil {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] can not be adopted, it does not exist "+
+			"or is not accessible, error is [%v]", bucketName, err))
+	}
+
+	err = s3Agent.CheckBucketOwned(ctx, bucketName)
+	if err != nil {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] can not be adopted, error is [%v]",
+			bucketName, err))
+	}
+
+	key, value, err := parseAdoptionTag(parameters)
+	if err != nil || key == "" {
+		return err
+	}
+
+	tags, err := s3Agent.GetBucketTags(ctx, bucketName)
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] tags failed, error is [%v]", bucketName, err)
+	}
+
+	if got, exist := tags[key]; !exist || got != value {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] can not be adopted, it does not carry "+
+			"the tag [%s=%s]", bucketName, key, value))
+	}
+
+	log.AddContext(ctx).Infof("bucket [%s] passed the adoption check", bucketName)
+	return nil
+}
+
+// createOrAdoptBucket creates the bucket, the existing bucket owned by the account is adopted only if it is
+// enabled by the parameters and passes the adoption check, otherwise it is a conflict.
+// The existing bucket created by the driver for another request is always a conflict.
+func createOrAdoptBucket(ctx context.Context, s3Agent *agent.S3Agent, req *cosispec.DriverCreateBucketRequest,
+	bucketName string) error {
+	parameters := req.GetParameters()
+	created, err := s3Agent.CreateBucketIfAbsent(ctx, bucketName, parameters[bucketACL], parameters[bucketLocation])
+	if err != nil {
+		return err
+	}
+
+	if created {
+		// An untagged bucket can not be told from an existing one when the request is retried,
+		// so the empty bucket is deleted if the tag fails, and the retried request creates it again.
+		err = s3Agent.PutBucketTags(ctx, bucketName, map[string]string{bucketCreatedByTag: req.GetName()})
+		if err != nil {
+			deleteErr := s3Agent.DeleteBucket(ctx, bucketName)
+			if deleteErr != nil {
+				log.AddContext(ctx).Errorf("delete untagged bucket [%s] failed, error is [%v]", bucketName,
+					deleteErr)
+			}
+			return fmt.Errorf("tag created bucket [%s] failed, error is [%v]", bucketName, err)
+		}
+		return nil
+	}
+
+	tags, err := s3Agent.GetBucketTags(ctx, bucketName)
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] tags failed, error is [%v]", bucketName, err)
+	}
+
+	createdBy, exist := tags[bucketCreatedByTag]
+	if createdBy == req.GetName() {
+		log.AddContext(ctx).Infof("bucket [%s] is created by request [%s] before", bucketName, req.GetName())
+		return nil
+	}
+
+	// the bucket created by the driver for another request belongs to another claim, it is never shared
+	if exist {
+		return utilErrors.NewAlreadyExistsErr(fmt.Sprintf("bucket [%s] already exists, it is created for "+
+			"request [%s]", bucketName, createdBy))
+	}
+
+	if !adoptExistingEnabled(parameters) {
+		return utilErrors.NewAlreadyExistsErr(fmt.Sprintf("bucket [%s] already exists and %s is disabled",
+			bucketName, adoptExisting))
+	}
+
+	return checkBucketAdoption(ctx, s3Agent, bucketName, parameters)
+}
+
+// getStaticBucket finds the Bucket which refers to the existing bucket by the bucket id,
+// nil is returned if the bucket is provisioned by the driver
+func (s *provisionerServer) getStaticBucket(ctx context.Context, bucketId string) (*v1alpha1.Bucket, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list buckets failed, error is [%v]", err)
+	}
+
+	for i := range list.Items {
+		bucket := &list.Items[i]
+		if bucket.Spec.DriverName == s.Provisioner && bucket.Spec.ExistingBucketID == bucketId {
+			return bucket, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// checkStaticBucketAdoption checks the existing bucket of static Bucket when the driver touches it for the first
+// time, the Bucket is annotated after it passes the check. The bucket ids needing no check are remembered,
+// so the Buckets are not listed again for them.
+func (s *provisionerServer) checkStaticBucketAdoption(ctx context.Context, bucketId string,
+	bcAccountSecret *coreV1.Secret, bucketName string) error {
+	if _, checked := s.adoptionCheckedBuckets.Load(bucketId); checked {
+		return nil
+	}
+
+	bucket, err := s.getStaticBucket(ctx, bucketId)
+	if err != nil {
+		return err
+	}
+
+	if bucket == nil || bucket.Annotations[adoptedAtAnnotation] != "" {
+		s.adoptionCheckedBuckets.Store(bucketId, struct{}{})
+		return nil
+	}
+
+	err = s.validateBucketAdoption(ctx, bucket, bcAccountSecret, bucketName)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.BucketClient.ObjectstorageV1alpha1().Buckets().Update(ctx, bucket, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("record adoption of bucket [%s] failed, error is [%v]", bucket.Name, err)
+	}
+
+	s.adoptionCheckedBuckets.Store(bucketId, struct{}{})
+	return nil
+}
+
+// validateBucketAdoption runs the adoption check against the parameters of Bucket,
+// and annotates the Bucket with the adoption time if it passes
+func (s *provisionerServer) validateBucketAdoption(ctx context.Context, bucket *v1alpha1.Bucket,
+	bcAccountSecret *coreV1.Secret, bucketName string) error {
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	err = checkBucketAdoption(ctx, s3Agent, bucketName, bucket.Spec.Parameters)
+	if err != nil {
+		return err
+	}
+
+	if bucket.Annotations == nil {
+		bucket.Annotations = map[string]string{}
+	}
+	bucket.Annotations[adoptedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+// handleBucketAdoptionValidations validates the adoption of Buckets annotated with the validation request
+func (s *provisionerServer) handleBucketAdoptionValidations(ctx context.Context) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		log.AddContext(ctx).Errorf("list buckets failed, error is [%v]", err)
+		return
+	}
+
+	for i := range list.Items {
+		bucket := &list.Items[i]
+		if bucket.Spec.DriverName != s.Provisioner || bucket.DeletionTimestamp != nil {
+			continue
+		}
+
+		requested, err := strconv.ParseBool(bucket.Annotations[adoptionValidationAnnotation])
+		if err != nil || !requested {
+			continue
+		}
+
+		result := adoptionResultSucceeded
+		err = s.validateRequestedBucketAdoption(ctx, bucket)
+		if err != nil {
+			log.AddContext(ctx).Errorf("validate adoption of bucket [%s] failed, error is [%v]", bucket.Name, err)
+			result = err.Error()
+		}
+
+		delete(bucket.Annotations, adoptionValidationAnnotation)
+		bucket.Annotations[adoptionResultAnnotation] = result
+		_, err = s.BucketClient.ObjectstorageV1alpha1().Buckets().Update(ctx, bucket, metaV1.UpdateOptions{})
+		if err != nil {
+			log.AddContext(ctx).Errorf("record adoption result of bucket [%s] failed, error is [%v]",
+				bucket.Name, err)
+		}
+	}
+}
+
+func (s *provisionerServer) validateRequestedBucketAdoption(ctx context.Context, bucket *v1alpha1.Bucket) error {
+	bucketId := bucket.Spec.ExistingBucketID
+	if bucketId == "" {
+		bucketId = bucket.Status.BucketID
+	}
+	if bucketId == "" {
+		return fmt.Errorf("bucket [%s] has no bucket id", bucket.Name)
+	}
+
+	bucketIdData, bcAccountSecret, err := fetchDataFromResourceId(bucketId, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", bucketId, err)
+	}
+
+	return s.validateBucketAdoption(ctx, bucket, bcAccountSecret, bucketIdData.resourceName)
+}
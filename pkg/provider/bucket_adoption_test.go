@@ -0,0 +1,357 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func Test_CheckAdoptionParameters(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    bool
+	}{
+		{name: "not set", parameters: map[string]string{}},
+		{name: "valid", parameters: map[string]string{adoptExisting: "false", adoptionTag: "owner=team-a"}},
+		{name: "invalid adopt existing", parameters: map[string]string{adoptExisting: "maybe"}, wantErr: true},
+		{name: "invalid tag", parameters: map[string]string{adoptionTag: "owner"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotErr := checkAdoptionParameters(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+			assert.Equal(t, c.wantErr, utilErrors.IsInvalidArgumentErr(gotErr))
+		})
+	}
+}
+
+func Test_CreateOrAdoptBucket_Created_Tagged(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo", Parameters: map[string]string{}}
+	var gotTags map[string]string
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CreateBucketIfAbsent", true, nil)
+	mock.ApplyMethod(reflect.TypeOf(s3Agent), "PutBucketTags",
+		func(_ *agent.S3Agent, _ context.Context, _ string, tags map[string]string) error {
+			gotTags = tags
+			return nil
+		})
+
+	// act
+	gotErr := createOrAdoptBucket(ctx, s3Agent, req, "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, map[string]string{bucketCreatedByTag: "bc-demo"}, gotTags)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CreateOrAdoptBucket_TagFailed_Deleted(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{adoptExisting: "false"}}
+	var deleted string
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CreateBucketIfAbsent", true, nil)
+	mock.ApplyMethodReturn(s3Agent, "PutBucketTags", errors.New("tag error"))
+	mock.ApplyMethod(reflect.TypeOf(s3Agent), "DeleteBucket",
+		func(_ *agent.S3Agent, _ context.Context, bucketName string) error {
+			deleted = bucketName
+			return nil
+		})
+
+	// act
+	gotErr := createOrAdoptBucket(ctx, s3Agent, req, "bucket-demo")
+
+	// assert
+	assert.ErrorContains(t, gotErr, "tag created bucket [bucket-demo] failed")
+	assert.Equal(t, "bucket-demo", deleted)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CreateOrAdoptBucket_RetriedRequest_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{adoptExisting: "false"}}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CreateBucketIfAbsent", false, nil)
+	mock.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{bucketCreatedByTag: "bc-demo"}, nil)
+
+	// act
+	gotErr := createOrAdoptBucket(ctx, s3Agent, req, "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CreateOrAdoptBucket_AdoptDisabled_AlreadyExists(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{adoptExisting: "false"}}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CreateBucketIfAbsent", false, nil)
+	mock.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{bucketCreatedByTag: "bc-other"}, nil)
+
+	// act
+	gotErr := createOrAdoptBucket(ctx, s3Agent, req, "bucket-demo")
+
+	// assert
+	assert.True(t, utilErrors.IsAlreadyExistsErr(gotErr))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CreateOrAdoptBucket_CreatedForOther_AlreadyExists(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{adoptExisting: "true"}}
+	adoptionChecked := false
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CreateBucketIfAbsent", false, nil)
+	mock.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{bucketCreatedByTag: "bc-other"}, nil)
+	mock.ApplyFunc(checkBucketAdoption, func(_ context.Context, _ *agent.S3Agent, _ string,
+		_ map[string]string) error {
+		adoptionChecked = true
+		return nil
+	})
+
+	// act
+	gotErr := createOrAdoptBucket(ctx, s3Agent, req, "bucket-demo")
+
+	// assert
+	assert.True(t, utilErrors.IsAlreadyExistsErr(gotErr))
+	assert.False(t, adoptionChecked)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CheckBucketAdoption_NotOwned_InvalidArgument(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CheckBucketExist", nil)
+	mock.ApplyMethodReturn(s3Agent, "CheckBucketOwned", errors.New("not owned"))
+
+	// act
+	gotErr := checkBucketAdoption(ctx, s3Agent, "bucket-demo", map[string]string{})
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CheckBucketAdoption_TagMismatch_InvalidArgument(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Agent := &agent.S3Agent{}
+	parameters := map[string]string{adoptionTag: "owner=team-a"}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "CheckBucketExist", nil)
+	mock.ApplyMethodReturn(s3Agent, "CheckBucketOwned", nil)
+	mock.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{"owner": "team-b"}, nil)
+
+	// act
+	gotErr := checkBucketAdoption(ctx, s3Agent, "bucket-demo", parameters)
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckStaticBucketAdoption_Adopted(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucketId := "cosi:v2:default/bc-secret/bucket-demo"
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "static-bucket"},
+		Spec:       v1alpha1.BucketSpec{DriverName: "cosi.huawei.com", ExistingBucketID: bucketId},
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(bucket)}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, &agent.S3Agent{}, nil)
+	mock.ApplyFuncReturn(checkBucketAdoption, nil)
+
+	// act
+	gotErr := s.checkStaticBucketAdoption(ctx, bucketId, &coreV1.Secret{}, "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	gotBucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, "static-bucket", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, gotBucket.Annotations[adoptedAtAnnotation])
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckStaticBucketAdoption_Refused(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucketId := "cosi:v2:default/bc-secret/bucket-demo"
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "static-bucket"},
+		Spec:       v1alpha1.BucketSpec{DriverName: "cosi.huawei.com", ExistingBucketID: bucketId},
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(bucket)}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, &agent.S3Agent{}, nil)
+	mock.ApplyFuncReturn(checkBucketAdoption, utilErrors.NewInvalidArgumentErr("not owned"))
+
+	// act
+	gotErr := s.checkStaticBucketAdoption(ctx, bucketId, &coreV1.Secret{}, "bucket-demo")
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+	gotBucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, "static-bucket", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, gotBucket.Annotations[adoptedAtAnnotation])
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckStaticBucketAdoption_CheckedOnce(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucketId := "cosi:v2:default/bc-secret/bucket-demo"
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset()}
+	listed := 0
+
+	// mock
+	mock := gomonkey.ApplyPrivateMethod(s, "getStaticBucket",
+		func(_ *provisionerServer, _ context.Context, _ string) (*v1alpha1.Bucket, error) {
+			listed++
+			return nil, nil
+		})
+
+	// act
+	gotErr := s.checkStaticBucketAdoption(ctx, bucketId, &coreV1.Secret{}, "bucket-demo")
+	gotAgainErr := s.checkStaticBucketAdoption(ctx, bucketId, &coreV1.Secret{}, "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.NoError(t, gotAgainErr)
+	assert.Equal(t, 1, listed)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_HandleBucketAdoptionValidations(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "static-bucket",
+			Annotations: map[string]string{adoptionValidationAnnotation: "true"}},
+		Spec: v1alpha1.BucketSpec{DriverName: "cosi.huawei.com",
+			ExistingBucketID: "cosi:v2:default/bc-secret/bucket-demo"},
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(bucket)}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "bucket-demo"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(agent.NewS3Agent, &agent.S3Agent{}, nil)
+	mock.ApplyFuncReturn(checkBucketAdoption, nil)
+
+	// act
+	s.handleBucketAdoptionValidations(ctx)
+
+	// assert
+	gotBucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, "static-bucket", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, gotBucket.Annotations, adoptionValidationAnnotation)
+	assert.Equal(t, adoptionResultSucceeded, gotBucket.Annotations[adoptionResultAnnotation])
+	assert.NotEmpty(t, gotBucket.Annotations[adoptedAtAnnotation])
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
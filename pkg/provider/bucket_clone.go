@@ -0,0 +1,137 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	cloneOperation = "clone"
+
+	defaultCloneParallelism = 8
+	maxCloneParallelism     = 64
+
+	// the clone progress is logged each time cloneProgressLogInterval objects are copied
+	cloneProgressLogInterval = 100
+
+	// clonedFromTag is tagged on the bucket after the objects of the source bucket are copied into it,
+	// so the retried create request does not copy them again
+	clonedFromTag = "cosi.huawei.com/cloned-from"
+)
+
+// cloneProgressReporter reports the copy progress to the operation, and logs it each time
+// cloneProgressLogInterval objects are copied
+func cloneProgressReporter(ctx context.Context, bucketName, source string,
+	report func(progress string)) func(progress agent.CopyProgress) {
+	return func(progress agent.CopyProgress) {
+		message := fmt.Sprintf("copied [%d/%d] objects, [%d/%d] bytes", progress.CopiedObjects,
+			progress.TotalObjects, progress.CopiedBytes, progress.TotalBytes)
+		if !progress.Listed {
+			message += ", the source bucket is still being listed"
+		}
+		report(message)
+
+		if progress.CopiedObjects%cloneProgressLogInterval == 0 ||
+			(progress.Listed && progress.CopiedObjects == progress.TotalObjects) {
+			log.AddContext(ctx).Infof("cloning bucket [%s] from [%s], %s", bucketName, source, message)
+		}
+	}
+}
+
+func checkCloneParameters(parameters map[string]string) error {
+	if source, exist := parameters[cloneSourceBucket]; exist {
+		if err := validateBucketName(source); err != nil {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], error is [%v]",
+				cloneSourceBucket, source, err))
+		}
+	}
+
+	if value, exist := parameters[cloneParallelism]; exist {
+		parallelism, err := strconv.Atoi(value)
+		if err != nil || parallelism < 1 || parallelism > maxCloneParallelism {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], it should be an integer "+
+				"between 1 and %d", cloneParallelism, value, maxCloneParallelism))
+		}
+	}
+
+	return nil
+}
+
+func cloneParallelismOf(parameters map[string]string) int {
+	parallelism, err := strconv.Atoi(parameters[cloneParallelism])
+	if err != nil || parallelism < 1 {
+		return defaultCloneParallelism
+	}
+
+	return min(parallelism, maxCloneParallelism)
+}
+
+// cloneBucket copies the objects of the source bucket in the parameters into the created bucket,
+// the copy runs as an operation of the bucket id and an unavailable error is returned until it completes
+func (s *provisionerServer) cloneBucket(ctx context.Context, s3Agent *agent.S3Agent,
+	req *cosispec.DriverCreateBucketRequest, bucketId, bucketName string) error {
+	source := req.GetParameters()[cloneSourceBucket]
+	if source == "" {
+		return nil
+	}
+
+	if source == bucketName {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] can not be cloned from itself", bucketName))
+	}
+
+	tags, err := s3Agent.GetBucketTags(ctx, bucketName)
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] tags failed, error is [%v]", bucketName, err)
+	}
+
+	// the objects of an adopted bucket belong to its owner, they must not be mixed with the cloned ones
+	if tags[bucketCreatedByTag] != req.GetName() {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] is adopted, objects are only cloned "+
+			"into the bucket created by the driver", bucketName))
+	}
+
+	if tags[clonedFromTag] == source {
+		log.AddContext(ctx).Infof("bucket [%s] is already cloned from [%s]", bucketName, source)
+		return nil
+	}
+
+	parallelism := cloneParallelismOf(req.GetParameters())
+	err = s.operations.run(ctx, bucketId, cloneOperation, func(ctx context.Context, report func(string)) error {
+		return s3Agent.CopyBucketObjects(ctx, source, bucketName, parallelism,
+			cloneProgressReporter(ctx, bucketName, source, report))
+	})
+	if err != nil {
+		return err
+	}
+
+	tags[clonedFromTag] = source
+	err = s3Agent.PutBucketTags(ctx, bucketName, tags)
+	if err != nil {
+		return fmt.Errorf("tag cloned bucket [%s] failed, error is [%v]", bucketName, err)
+	}
+
+	log.AddContext(ctx).Infof("clone bucket [%s] from [%s] successfully", bucketName, source)
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func Test_CheckCloneParameters(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    bool
+	}{
+		{name: "not set", parameters: map[string]string{}},
+		{name: "valid", parameters: map[string]string{cloneSourceBucket: "golden-data", cloneParallelism: "16"}},
+		{name: "invalid source", parameters: map[string]string{cloneSourceBucket: "Golden_Data"}, wantErr: true},
+		{name: "invalid parallelism", parameters: map[string]string{cloneParallelism: "0"}, wantErr: true},
+		{name: "too large parallelism", parameters: map[string]string{cloneParallelism: "65"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotErr := checkCloneParameters(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+			assert.Equal(t, c.wantErr, utilErrors.IsInvalidArgumentErr(gotErr))
+		})
+	}
+}
+
+func Test_ProvisionerServer_CloneBucket_NoSource(t *testing.T) {
+	// arrange
+	s := &provisionerServer{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo", Parameters: map[string]string{}}
+
+	// act
+	gotErr := s.cloneBucket(context.TODO(), &agent.S3Agent{}, req, "bucket-id", "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+}
+
+func Test_ProvisionerServer_CloneBucket_InProgress(t *testing.T) {
+	// arrange
+	s := &provisionerServer{}
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{cloneSourceBucket: "golden-data"}}
+	release := make(chan struct{})
+	var started atomic.Int32
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{bucketCreatedByTag: "bc-demo"}, nil)
+	mock.ApplyMethod(reflect.TypeOf(s3Agent), "CopyBucketObjects",
+		func(_ *agent.S3Agent, _ context.Context, _, _ string, _ int, report func(agent.CopyProgress)) error {
+			started.Add(1)
+			report(agent.CopyProgress{CopiedObjects: 1, TotalObjects: 2, CopiedBytes: 5, TotalBytes: 10})
+			<-release
+			return nil
+		})
+
+	// act
+	gotErr := s.cloneBucket(context.TODO(), s3Agent, req, "bucket-id", "bucket-demo")
+	assert.Eventually(t, func() bool { return started.Load() == 1 }, time.Second, 10*time.Millisecond)
+	gotRetryErr := s.cloneBucket(context.TODO(), s3Agent, req, "bucket-id", "bucket-demo")
+
+	// assert
+	assert.True(t, utilErrors.IsUnavailableErr(gotErr))
+	assert.True(t, utilErrors.IsUnavailableErr(gotRetryErr))
+	assert.ErrorContains(t, gotRetryErr, "copied [1/2] objects, [5/10] bytes")
+	assert.Equal(t, int32(1), started.Load())
+
+	// cleanup
+	t.Cleanup(func() {
+		close(release)
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CloneBucket_Done(t *testing.T) {
+	// arrange
+	s := &provisionerServer{}
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{cloneSourceBucket: "golden-data"}}
+	s.operations.operations.Store("bucket-id", &operation{name: cloneOperation, done: true,
+		finishedAt: time.Now()})
+	var gotTags map[string]string
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{bucketCreatedByTag: "bc-demo"}, nil)
+	mock.ApplyMethod(reflect.TypeOf(s3Agent), "PutBucketTags",
+		func(_ *agent.S3Agent, _ context.Context, _ string, tags map[string]string) error {
+			gotTags = tags
+			return nil
+		})
+
+	// act
+	gotErr := s.cloneBucket(context.TODO(), s3Agent, req, "bucket-id", "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, map[string]string{bucketCreatedByTag: "bc-demo", clonedFromTag: "golden-data"}, gotTags)
+	_, exist := s.operations.operations.Load("bucket-id")
+	assert.False(t, exist)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CloneBucket_Failed(t *testing.T) {
+	// arrange
+	s := &provisionerServer{}
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{cloneSourceBucket: "golden-data"}}
+	s.operations.operations.Store("bucket-id", &operation{name: cloneOperation, done: true,
+		err: errors.New("copy failed"), finishedAt: time.Now()})
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{bucketCreatedByTag: "bc-demo"}, nil)
+
+	// act
+	gotErr := s.cloneBucket(context.TODO(), s3Agent, req, "bucket-id", "bucket-demo")
+
+	// assert
+	assert.ErrorContains(t, gotErr, "copy failed")
+	assert.False(t, utilErrors.IsUnavailableErr(gotErr))
+	_, exist := s.operations.operations.Load("bucket-id")
+	assert.False(t, exist)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CloneBucket_AlreadyCloned(t *testing.T) {
+	// arrange
+	s := &provisionerServer{}
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{cloneSourceBucket: "golden-data"}}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "GetBucketTags",
+		map[string]string{bucketCreatedByTag: "bc-demo", clonedFromTag: "golden-data"}, nil)
+
+	// act
+	gotErr := s.cloneBucket(context.TODO(), s3Agent, req, "bucket-id", "bucket-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CloneBucket_Adopted(t *testing.T) {
+	// arrange
+	s := &provisionerServer{}
+	s3Agent := &agent.S3Agent{}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-demo",
+		Parameters: map[string]string{cloneSourceBucket: "golden-data"}}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Agent, "GetBucketTags", map[string]string{"team": "a"}, nil)
+
+	// act
+	gotErr := s.cloneBucket(context.TODO(), s3Agent, req, "bucket-id", "bucket-demo")
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+	_, exist := s.operations.operations.Load("bucket-id")
+	assert.False(t, exist)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
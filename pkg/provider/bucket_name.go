@@ -0,0 +1,170 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+const (
+	minBucketNameLength = 3
+
+	bucketNamePlaceholderPrefix    = "{prefix}"
+	bucketNamePlaceholderNamespace = "{namespace}"
+	bucketNamePlaceholderClaim     = "{claim}"
+	bucketNamePlaceholderName      = "{name}"
+)
+
+var (
+	strictBucketNames = flag.Bool("strict-bucket-names", false,
+		"validate all bucket names against the s3 bucket naming rules, by default only the bucket names "+
+			"rendered from bucketNameTemplate are validated, so that the existing names with uppercase letters "+
+			"or underscores keep working")
+
+	// bucketNameCharacters are the characters allowed in bucket names
+	bucketNameCharacters = regexp.MustCompile(`^[a-z0-9.-]+$`)
+
+	// bucketNameInvalidCharacters are replaced by hyphens when rendering bucket name template
+	bucketNameInvalidCharacters = regexp.MustCompile(`[^a-z0-9.-]`)
+
+	// bucketNameReservedPrefixes and bucketNameReservedSuffixes are reserved by s3
+	bucketNameReservedPrefixes = []string{"xn--", "sthree-", "amzn-s3-demo-"}
+	bucketNameReservedSuffixes = []string{"-s3alias", "--ol-s3", ".mrap", "--x-s3", "--table-s3"}
+)
+
+// validateBucketName checks the bucket name against the s3 bucket naming rules
+func validateBucketName(name string) error {
+	if len(name) < minBucketNameLength || len(name) > maxBucketNameLength {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] must be between %d and %d "+
+			"characters long", name, minBucketNameLength, maxBucketNameLength))
+	}
+
+	if !bucketNameCharacters.MatchString(name) {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] can consist only of lowercase "+
+			"letters, numbers, dots and hyphens", name))
+	}
+
+	if !isLowerAlphanumeric(name[0]) || !isLowerAlphanumeric(name[len(name)-1]) {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] must begin and end with "+
+			"a letter or number", name))
+	}
+
+	if strings.Contains(name, "..") || strings.Contains(name, ".-") || strings.Contains(name, "-.") {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] must not contain two adjacent "+
+			"periods, or a period adjacent to a hyphen", name))
+	}
+
+	if net.ParseIP(name) != nil {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] must not be formatted as "+
+			"an IP address", name))
+	}
+
+	for _, prefix := range bucketNameReservedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] must not start with "+
+				"the reserved prefix [%s]", name, prefix))
+		}
+	}
+
+	for _, suffix := range bucketNameReservedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket name [%s] must not end with "+
+				"the reserved suffix [%s]", name, suffix))
+		}
+	}
+
+	return nil
+}
+
+// checkBucketName validates the name of the bucket to create, the name rendered from bucketNameTemplate is
+// always validated, the other names are validated only if strict-bucket-names is enabled
+func checkBucketName(name string, parameters map[string]string) error {
+	if _, exist := parameters[bucketNameTemplate]; !exist && !*strictBucketNames {
+		return nil
+	}
+
+	return validateBucketName(name)
+}
+
+func isLowerAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// checkBucketNameTemplate checks that the bucketNameTemplate of bucketClass only has the known placeholders
+func checkBucketNameTemplate(parameters map[string]string) error {
+	template, exist := parameters[bucketNameTemplate]
+	if !exist {
+		return nil
+	}
+
+	rest := strings.NewReplacer(bucketNamePlaceholderPrefix, "", bucketNamePlaceholderNamespace, "",
+		bucketNamePlaceholderClaim, "", bucketNamePlaceholderName, "").Replace(template)
+	if strings.TrimSpace(template) == "" || strings.ContainsAny(rest, "{}") {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], the placeholders can only be "+
+			"%s, %s, %s and %s", bucketNameTemplate, template, bucketNamePlaceholderPrefix,
+			bucketNamePlaceholderNamespace, bucketNamePlaceholderClaim, bucketNamePlaceholderName))
+	}
+
+	return nil
+}
+
+// resolveBucketName generates the name of the bucket to create. The bucketNameTemplate of bucketClass is
+// rendered with the BucketClaim of the Bucket if configured, otherwise the bucket naming strategy is used.
+func (s *provisionerServer) resolveBucketName(ctx context.Context, req *cosispec.DriverCreateBucketRequest) (string,
+	error) {
+	template, exist := req.GetParameters()[bucketNameTemplate]
+	if !exist {
+		return backendBucketName(req.GetName()), nil
+	}
+
+	bucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, req.GetName(), metaV1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get bucket [%s] failed, error is [%v]", req.GetName(), err)
+	}
+
+	if bucket.Spec.BucketClaim == nil {
+		return "", fmt.Errorf("bucket [%s] has no bucketClaim", req.GetName())
+	}
+
+	prefix := req.GetParameters()[bucketNamePrefix]
+	if prefix == "" {
+		prefix = *clusterId
+	}
+
+	return renderBucketNameTemplate(template, prefix, bucket.Spec.BucketClaim.Namespace,
+		bucket.Spec.BucketClaim.Name, req.GetName()), nil
+}
+
+// renderBucketNameTemplate replaces the placeholders of template, and converts the result into a bucket name:
+// the invalid characters are replaced by hyphens, and the name exceeding the length limit is truncated and
+// suffixed with the hash of the full name.
+func renderBucketNameTemplate(template, prefix, namespace, claim, name string) string {
+	rendered := strings.NewReplacer(bucketNamePlaceholderPrefix, prefix, bucketNamePlaceholderNamespace, namespace,
+		bucketNamePlaceholderClaim, claim, bucketNamePlaceholderName, name).Replace(template)
+	rendered = bucketNameInvalidCharacters.ReplaceAllString(strings.ToLower(rendered), "-")
+	rendered = strings.Trim(rendered, ".-")
+	return limitNameLength(rendered, maxBucketNameLength)
+}
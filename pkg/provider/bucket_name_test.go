@@ -0,0 +1,129 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func Test_ValidateBucketName(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name    string
+		bucket  string
+		wantErr string
+	}{
+		{name: "valid", bucket: "my-bucket.v1"},
+		{name: "too short", bucket: "ab", wantErr: "must be between 3 and 63 characters long"},
+		{name: "too long", bucket: strings.Repeat("a", 64), wantErr: "must be between 3 and 63 characters long"},
+		{name: "uppercase", bucket: "My-Bucket", wantErr: "can consist only of lowercase letters"},
+		{name: "underscore", bucket: "my_bucket", wantErr: "can consist only of lowercase letters"},
+		{name: "begin with hyphen", bucket: "-bucket", wantErr: "must begin and end with a letter or number"},
+		{name: "end with dot", bucket: "bucket.", wantErr: "must begin and end with a letter or number"},
+		{name: "adjacent periods", bucket: "my..bucket", wantErr: "must not contain two adjacent periods"},
+		{name: "period and hyphen", bucket: "my.-bucket", wantErr: "must not contain two adjacent periods"},
+		{name: "ip address", bucket: "192.168.5.4", wantErr: "must not be formatted as an IP address"},
+		{name: "reserved prefix", bucket: "xn--bucket", wantErr: "must not start with the reserved prefix [xn--]"},
+		{name: "reserved suffix", bucket: "bucket-s3alias",
+			wantErr: "must not end with the reserved suffix [-s3alias]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotErr := validateBucketName(c.bucket)
+
+			// assert
+			if c.wantErr == "" {
+				assert.NoError(t, gotErr)
+				return
+			}
+			assert.ErrorContains(t, gotErr, c.wantErr)
+			assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+		})
+	}
+}
+
+func Test_CheckBucketNameTemplate(t *testing.T) {
+	// act & assert
+	assert.NoError(t, checkBucketNameTemplate(map[string]string{}))
+	assert.NoError(t, checkBucketNameTemplate(map[string]string{bucketNameTemplate: "{prefix}-{namespace}-{claim}"}))
+	assert.Error(t, checkBucketNameTemplate(map[string]string{bucketNameTemplate: "{prefix}-{uid}"}))
+	assert.Error(t, checkBucketNameTemplate(map[string]string{bucketNameTemplate: " "}))
+}
+
+func Test_RenderBucketNameTemplate(t *testing.T) {
+	// act
+	gotName := renderBucketNameTemplate("{prefix}-{namespace}-{claim}", "Prod", "team_a", "logs", "bc-uid")
+	gotLongName := renderBucketNameTemplate("{prefix}-{namespace}-{claim}", "prod", strings.Repeat("n", 40),
+		strings.Repeat("c", 40), "bc-uid")
+
+	// assert
+	assert.Equal(t, "prod-team-a-logs", gotName)
+	assert.Len(t, gotLongName, maxBucketNameLength)
+	assert.NoError(t, validateBucketName(gotLongName))
+}
+
+func Test_CheckBucketName(t *testing.T) {
+	// arrange
+	templated := map[string]string{bucketNameTemplate: "{prefix}-{claim}"}
+
+	// act & assert
+	assert.NoError(t, checkBucketName("bucketName", map[string]string{}))
+	assert.Error(t, checkBucketName("bucketName", templated))
+
+	mock := gomonkey.ApplyGlobalVar(strictBucketNames, true)
+	assert.Error(t, checkBucketName("bucketName", map[string]string{}))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_ResolveBucketName_Template(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucket := &v1alpha1.Bucket{ObjectMeta: metaV1.ObjectMeta{Name: "bc-uid"},
+		Spec: v1alpha1.BucketSpec{BucketClaim: &coreV1.ObjectReference{Namespace: "app", Name: "logs"}}}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(bucket)}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bc-uid",
+		Parameters: map[string]string{bucketNameTemplate: "{prefix}-{namespace}-{claim}"}}
+	reqWithPrefix := &cosispec.DriverCreateBucketRequest{Name: "bc-uid", Parameters: map[string]string{
+		bucketNameTemplate: "{prefix}-{namespace}-{claim}", bucketNamePrefix: "team"}}
+
+	// act
+	gotName, gotErr := s.resolveBucketName(ctx, req)
+	gotPrefixedName, gotPrefixedErr := s.resolveBucketName(ctx, reqWithPrefix)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, defaultClusterId+"-app-logs", gotName)
+	assert.NoError(t, gotPrefixedErr)
+	assert.Equal(t, "team-app-logs", gotPrefixedName)
+}
@@ -0,0 +1,354 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/errors"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+	"github.com/huawei/cosi-driver/pkg/utils/version"
+)
+
+// Before the driver replaces or deletes a bucket policy, the previous policy document is kept in the
+// configMap 'cosi-policy-backup-{bucket}-{hash of bucket id}' of the driver namespace, together with the replacing
+// operation.
+// Backups are listed by 'kubectl get configmap -l cosi.huawei.com/policy-backup=true -n {driver namespace}',
+// and a backup is restored by annotating its configMap with 'cosi.huawei.com/restore-revision={revision}',
+// the result is reported in the annotation 'cosi.huawei.com/restore-result'.
+
+var (
+	bucketPolicyBackupLimit = flag.Int("bucket-policy-backup-limit", defaultBucketPolicyBackupLimit,
+		"the max number of bucket policy backups kept for each bucket")
+	bucketPolicyRestoreInterval = flag.Duration("bucket-policy-restore-interval", defaultBucketPolicyRestoreInterval,
+		"the interval of handling bucket policy restore requests, 0 means disabled")
+)
+
+const (
+	defaultBucketPolicyBackupLimit     = 10
+	defaultBucketPolicyRestoreInterval = 30 * time.Second
+
+	policyBackupConfigMapPrefix        = "cosi-policy-backup-"
+	policyBackupHistoryKey             = "history"
+	policyBackupLabel                  = "cosi.huawei.com/policy-backup"
+	policyBackupBucketIdAnnotation     = "cosi.huawei.com/bucket-id"
+	policyRestoreRevisionAnnotation    = "cosi.huawei.com/restore-revision"
+	policyRestoreResultAnnotation      = "cosi.huawei.com/restore-result"
+	policyBackupOperationRestoreFormat = "restore revision [%d]"
+)
+
+// policyBackupRecord is a bucket policy document replaced by the driver
+type policyBackupRecord struct {
+	// Revision increases with each backup of the bucket
+	Revision int `json:"revision"`
+
+	// Time is the time when the policy is replaced, the format is RFC3339
+	Time string `json:"time"`
+
+	// Operation is the driver operation replacing the policy
+	Operation string `json:"operation"`
+
+	// Policy is the replaced policy document, empty means the bucket had no policy
+	Policy string `json:"policy"`
+}
+
+// driverNamespace returns the namespace which the driver is deployed in
+func driverNamespace() string {
+	namespace := os.Getenv(version.EnvNamespace)
+	if namespace == "" {
+		namespace = version.DefaultNamespace
+	}
+
+	return namespace
+}
+
+// policyBackupConfigMapName is suffixed with the hash of the bucket id, so the buckets of the same name on
+// different backends are backed up in different configMaps
+func policyBackupConfigMapName(bcAccountSecret *coreV1.Secret, bucketName string) string {
+	sum := sha256.Sum256([]byte(assembleResourceId(bcAccountSecret.Namespace, bcAccountSecret.Name, bucketName)))
+	return policyBackupConfigMapPrefix + bucketName + "-" + hex.EncodeToString(sum[:])[:nameHashLength]
+}
+
+// backupBucketPolicy keeps the current policy of bucket before the operation replaces it,
+// only the latest bucketPolicyBackupLimit backups are kept.
+func (s *provisionerServer) backupBucketPolicy(ctx context.Context, bcAccountSecret *coreV1.Secret,
+	bucketName, operation string, bp *policy.BucketPolicy) error {
+	var document string
+	if bp != nil {
+		var err error
+		document, err = bp.ToJsonString()
+		if err != nil {
+			return fmt.Errorf("marshal bucket [%s] policy failed, error is [%v]", bucketName, err)
+		}
+	}
+
+	namespace := driverNamespace()
+	cmName := policyBackupConfigMapName(bcAccountSecret, bucketName)
+	var revision int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.getOrCreatePolicyBackupConfigMap(ctx, bcAccountSecret, bucketName)
+		if err != nil {
+			return err
+		}
+
+		history, err := parsePolicyBackupHistory(cm)
+		if err != nil {
+			return err
+		}
+
+		revision = 1
+		if len(history) > 0 {
+			revision = history[len(history)-1].Revision + 1
+		}
+		history = append(history, policyBackupRecord{
+			Revision:  revision,
+			Time:      time.Now().UTC().Format(time.RFC3339),
+			Operation: operation,
+			Policy:    document,
+		})
+		if limit := *bucketPolicyBackupLimit; limit > 0 && len(history) > limit {
+			history = history[len(history)-limit:]
+		}
+
+		data, err := json.Marshal(history)
+		if err != nil {
+			return fmt.Errorf("marshal bucket [%s] policy backups failed, error is [%v]", bucketName, err)
+		}
+		cm.Data[policyBackupHistoryKey] = string(data)
+
+		_, err = s.K8sClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metaV1.UpdateOptions{})
+		if err != nil && !apiErrors.IsConflict(err) {
+			return fmt.Errorf("update configMap [%s/%s] failed, error is [%v]", namespace, cmName, err)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.AddContext(ctx).Infof("backup bucket [%s] policy as revision [%d] before [%s]",
+		bucketName, revision, operation)
+	return nil
+}
+
+func (s *provisionerServer) getOrCreatePolicyBackupConfigMap(ctx context.Context, bcAccountSecret *coreV1.Secret,
+	bucketName string) (*coreV1.ConfigMap, error) {
+	namespace := driverNamespace()
+	cmName := policyBackupConfigMapName(bcAccountSecret, bucketName)
+	cm, err := s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		cm = &coreV1.ConfigMap{}
+		cm.Name = cmName
+		cm.Namespace = namespace
+		cm.Labels = map[string]string{policyBackupLabel: "true"}
+		cm.Annotations = map[string]string{policyBackupBucketIdAnnotation: encodeResourceId(bcAccountSecret,
+			bucketName)}
+		cm, err = s.K8sClient.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metaV1.CreateOptions{})
+		if apiErrors.IsAlreadyExists(err) {
+			cm, err = s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metaV1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get configMap [%s/%s] failed, error is [%v]", namespace, cmName, err)
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+
+	return cm, nil
+}
+
+func parsePolicyBackupHistory(cm *coreV1.ConfigMap) ([]policyBackupRecord, error) {
+	var history []policyBackupRecord
+	data, exist := cm.Data[policyBackupHistoryKey]
+	if !exist || data == "" {
+		return history, nil
+	}
+
+	err := json.Unmarshal([]byte(data), &history)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal policy backups of configMap [%s/%s] failed, error is [%v]",
+			cm.Namespace, cm.Name, err)
+	}
+
+	return history, nil
+}
+
+// listBucketPolicyBackups lists the policy backups in the backup configMap from the oldest to the latest
+func (s *provisionerServer) listBucketPolicyBackups(ctx context.Context,
+	cmName string) ([]policyBackupRecord, error) {
+	namespace := driverNamespace()
+	cm, err := s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get configMap [%s/%s] failed, error is [%v]", namespace, cmName, err)
+	}
+
+	return parsePolicyBackupHistory(cm)
+}
+
+// restoreBucketPolicy puts the policy document of the backup revision in the backup configMap back to the bucket,
+// the current policy is backed up before, so the restore itself can be reverted.
+func (s *provisionerServer) restoreBucketPolicy(ctx context.Context, cmName, bucketId string, revision int) error {
+	// The same lock as grant and revoke, otherwise the restored policy may be overwritten.
+	s.keyLock.Lock(bucketId)
+	defer s.keyLock.Unlock(bucketId)
+
+	bucketIdData, bcAccountSecret, err := fetchDataFromResourceId(bucketId, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", bucketId, err)
+	}
+	bucketName := bucketIdData.resourceName
+
+	history, err := s.listBucketPolicyBackups(ctx, cmName)
+	if err != nil {
+		return err
+	}
+
+	var record *policyBackupRecord
+	for i := range history {
+		if history[i].Revision == revision {
+			record = &history[i]
+			break
+		}
+	}
+	if record == nil {
+		return fmt.Errorf("revision [%d] of bucket [%s] policy backups not found", revision, bucketName)
+	}
+
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	current, err := s3Agent.GetBucketPolicy(ctx, bucketName,
+		errors.NewExceptionalErrCodes(errors.ErrNoSuchBucketPolicy))
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	err = s.backupBucketPolicy(ctx, bcAccountSecret, bucketName,
+		fmt.Sprintf(policyBackupOperationRestoreFormat, revision), current)
+	if err != nil {
+		return fmt.Errorf("backup bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	// The backup is exactly what the bucket had, so it is restored as it is without lint.
+	if record.Policy == "" {
+		err = s3Agent.DeleteBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucketPolicy))
+		if err != nil {
+			return fmt.Errorf("delete bucket [%s] policy failed, error is [%v]", bucketName, err)
+		}
+	} else {
+		bp := &policy.BucketPolicy{}
+		err = json.Unmarshal([]byte(record.Policy), bp)
+		if err != nil {
+			return fmt.Errorf("unmarshal revision [%d] of bucket [%s] policy failed, error is [%v]",
+				revision, bucketName, err)
+		}
+
+		err = s3Agent.PutBucketPolicy(ctx, bucketName, bp, errors.EmptyExceptionalErrCodes)
+		if err != nil {
+			return fmt.Errorf("put bucket [%s] policy failed, error is [%v]", bucketName, err)
+		}
+	}
+
+	log.AddContext(ctx).Infof("restore bucket [%s] policy to revision [%d] successfully", bucketName, revision)
+	return nil
+}
+
+// handlePolicyRestoreRequests restores the bucket policies requested by the restore annotation of backup configMaps
+func (s *provisionerServer) handlePolicyRestoreRequests(ctx context.Context) {
+	namespace := driverNamespace()
+	list, err := s.K8sClient.CoreV1().ConfigMaps(namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: policyBackupLabel + "=true",
+	})
+	if err != nil {
+		log.AddContext(ctx).Errorf("list policy backup configMaps failed, error is [%v]", err)
+		return
+	}
+
+	for _, cm := range list.Items {
+		value, exist := cm.Annotations[policyRestoreRevisionAnnotation]
+		if !exist {
+			continue
+		}
+
+		var result string
+		revision, err := strconv.Atoi(value)
+		if err != nil {
+			result = fmt.Sprintf("invalid revision [%s]", value)
+		} else if err = s.restoreBucketPolicy(ctx, cm.Name, cm.Annotations[policyBackupBucketIdAnnotation],
+			revision); err != nil {
+			result = fmt.Sprintf("restore revision [%d] failed at %s, error is [%v]",
+				revision, time.Now().UTC().Format(time.RFC3339), err)
+		} else {
+			result = fmt.Sprintf("restore revision [%d] successfully at %s",
+				revision, time.Now().UTC().Format(time.RFC3339))
+		}
+
+		log.AddContext(ctx).Infof("handle restore request of configMap [%s/%s]: %s", namespace, cm.Name, result)
+		if err = s.finishPolicyRestoreRequest(ctx, cm.Name, result); err != nil {
+			log.AddContext(ctx).Errorf("finish restore request of configMap [%s/%s] failed, error is [%v]",
+				namespace, cm.Name, err)
+		}
+	}
+}
+
+// finishPolicyRestoreRequest removes the restore annotation and reports the result
+func (s *provisionerServer) finishPolicyRestoreRequest(ctx context.Context, cmName, result string) error {
+	namespace := driverNamespace()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		delete(cm.Annotations, policyRestoreRevisionAnnotation)
+		cm.Annotations[policyRestoreResultAnnotation] = result
+		_, err = s.K8sClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metaV1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("update configMap [%s/%s] failed, error is [%v]", namespace, cmName, err)
+	}
+
+	return nil
+}
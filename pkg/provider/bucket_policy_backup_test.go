@@ -0,0 +1,138 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/utils/keylock"
+)
+
+func Test_BackupBucketPolicy_KeepLimit(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	bp := policy.NewBucketPolicy(policy.Statement{Sid: "user-demo"})
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketPolicyBackupLimit, 2)
+
+	// act
+	errs := []error{
+		s.backupBucketPolicy(ctx, bcSecret, "bucket-demo", "grant user [user-1]", nil),
+		s.backupBucketPolicy(ctx, bcSecret, "bucket-demo", "grant user [user-2]", bp),
+		s.backupBucketPolicy(ctx, bcSecret, "bucket-demo", "revoke user [user-1]", bp),
+	}
+	gotHistory, gotErr := s.listBucketPolicyBackups(ctx, policyBackupConfigMapName(bcSecret, "bucket-demo"))
+
+	// assert
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	assert.NoError(t, gotErr)
+	assert.Len(t, gotHistory, 2)
+	assert.Equal(t, 2, gotHistory[0].Revision)
+	assert.Equal(t, 3, gotHistory[1].Revision)
+	assert.Equal(t, "revoke user [user-1]", gotHistory[1].Operation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RestoreBucketPolicy_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(bcSecret), keyLock: keylock.NewKeyLock(keyLockSize)}
+	backup := policy.NewBucketPolicy(policy.Statement{Sid: "hand-added"})
+	current := policy.NewBucketPolicy(policy.Statement{Sid: "user-demo"})
+	var restored *policy.BucketPolicy
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", current, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, bp *policy.BucketPolicy, _ []string) error {
+			restored = bp
+			return nil
+		})
+
+	// act
+	backupErr := s.backupBucketPolicy(ctx, bcSecret, "bucket-demo", "grant user [user-demo]", backup)
+	cmName := policyBackupConfigMapName(bcSecret, "bucket-demo")
+	gotErr := s.restoreBucketPolicy(ctx, cmName, "default/bc-secret/bucket-demo", 1)
+	gotHistory, _ := s.listBucketPolicyBackups(ctx, cmName)
+
+	// assert
+	assert.NoError(t, backupErr)
+	assert.NoError(t, gotErr)
+	assert.Equal(t, backup, restored)
+	assert.Len(t, gotHistory, 2)
+	assert.Equal(t, "restore revision [1]", gotHistory[1].Operation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_HandlePolicyRestoreRequests_InvalidRevision(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	cm := &coreV1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{
+		Name:        policyBackupConfigMapName(&coreV1.Secret{}, "bucket-demo"),
+		Namespace:   driverNamespace(),
+		Labels:      map[string]string{policyBackupLabel: "true"},
+		Annotations: map[string]string{policyRestoreRevisionAnnotation: "latest"},
+	}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(cm)}
+
+	// act
+	s.handlePolicyRestoreRequests(ctx)
+	gotCm, gotErr := s.K8sClient.CoreV1().ConfigMaps(cm.Namespace).Get(ctx, cm.Name, metaV1.GetOptions{})
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.NotContains(t, gotCm.Annotations, policyRestoreRevisionAnnotation)
+	assert.Equal(t, "invalid revision [latest]", gotCm.Annotations[policyRestoreResultAnnotation])
+}
+
+func Test_PolicyBackupConfigMapName_DifferentBackends(t *testing.T) {
+	// arrange
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	otherSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "other-secret", Namespace: "default"}}
+
+	// act
+	gotName := policyBackupConfigMapName(bcSecret, "bucket-demo")
+	gotOtherName := policyBackupConfigMapName(otherSecret, "bucket-demo")
+
+	// assert
+	assert.NotEqual(t, gotName, gotOtherName)
+	assert.Equal(t, gotName, policyBackupConfigMapName(bcSecret, "bucket-demo"))
+	assert.Regexp(t, "^cosi-policy-backup-bucket-demo-[0-9a-f]{8}$", gotName)
+}
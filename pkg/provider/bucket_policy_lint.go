@@ -0,0 +1,131 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// lintBucketPolicy lints the bucket policy to be written, which is edited from the origin policy.
+// Warnings are logged, and an invalid argument error is returned if there is any serious finding
+// about the statements changed by the edit.
+func lintBucketPolicy(ctx context.Context, bacAccountSecret *coreV1.Secret, bucketName string,
+	statement *policy.Statement, origin, bp *policy.BucketPolicy) error {
+	stale, err := findStalePrincipals(ctx, bacAccountSecret, statement, origin, bp)
+	if err != nil {
+		// principal existence is only a warning, so it does not block the write
+		log.AddContext(ctx).Warningf("find stale principals of bucket [%s] policy failed, error is [%v]",
+			bucketName, err)
+	}
+
+	findings := bp.Lint(policy.LintOptions{
+		BucketName: bucketName,
+		PrincipalExists: func(principal string) bool {
+			return !stale[principal]
+		},
+		AllowPublicRead: *enablePublicRead,
+		ChangedSids:     bp.ChangedSids(origin),
+	})
+	logLintFindings(ctx, bucketName, findings)
+
+	var serious []string
+	for _, f := range findings {
+		if f.Severity == policy.LintSeverityError {
+			serious = append(serious, f.String())
+		}
+	}
+
+	if len(serious) > 0 {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] policy is rejected by lint, "+
+			"findings are [%s]", bucketName, strings.Join(serious, "; ")))
+	}
+
+	return nil
+}
+
+// logLintFindings logs the lint findings of bucket policy
+func logLintFindings(ctx context.Context, bucketName string, findings []policy.LintFinding) {
+	for _, f := range findings {
+		if f.Severity == policy.LintSeverityError {
+			log.AddContext(ctx).Errorf("lint bucket [%s] policy: %s", bucketName, f)
+		} else {
+			log.AddContext(ctx).Warningf("lint bucket [%s] policy: %s", bucketName, f)
+		}
+	}
+}
+
+// findStalePrincipals finds the user principals added to the bucket policy which no longer exist.
+// The principals already in the origin policy are not queried, so the cost does not grow with the policy.
+// Only users of the same account as the principals of statement can be queried,
+// principals of other accounts are skipped.
+func findStalePrincipals(ctx context.Context, bacAccountSecret *coreV1.Secret, statement *policy.Statement,
+	origin, bp *policy.BucketPolicy) (map[string]bool, error) {
+	var accountPrefix string
+	for _, principal := range policy.NewBucketPolicy(*statement).Principals() {
+		if userName := policy.UserNameFromArn(principal); userName != "" {
+			accountPrefix = strings.TrimSuffix(principal, userName)
+			break
+		}
+	}
+
+	existing := make(map[string]bool)
+	if origin != nil {
+		for _, principal := range origin.Principals() {
+			existing[principal] = true
+		}
+	}
+
+	var added []string
+	for _, principal := range bp.Principals() {
+		if accountPrefix != "" && strings.HasPrefix(principal, accountPrefix) && !existing[principal] {
+			added = append(added, principal)
+		}
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return nil, fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	stale := make(map[string]bool)
+	for _, principal := range added {
+		userName := policy.UserNameFromArn(principal)
+		resp, err := userClient.GetUser(ctx, &api.GetUserInput{UserName: userName})
+		if err != nil {
+			return stale, fmt.Errorf("get user [%s] failed, error is [%v]", userName, err)
+		}
+
+		if resp == nil {
+			stale[principal] = true
+		}
+	}
+
+	return stale, nil
+}
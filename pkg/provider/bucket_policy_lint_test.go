@@ -0,0 +1,149 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+const testUserArnPrefix = "arn:aws:iam::domain-id:user/"
+
+func Test_LintBucketPolicy_SeriousFinding(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals(policy.AnonymousPrincipal).WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	bp := policy.NewBucketPolicy(*statement)
+
+	// act
+	gotErr := lintBucketPolicy(ctx, &coreV1.Secret{}, "bucket-demo", statement, nil, bp)
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+	assert.ErrorContains(t, gotErr, "wildcard principal grants access to everyone")
+}
+
+func Test_FindStalePrincipals_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	statement := policy.NewStatementBuilder().WithSID("user-new").WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix + "user-new").WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	legacy := policy.NewStatementBuilder().WithSID("user-old").WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix+"user-old", "arn:aws:iam::other-id:user/user-other").
+		WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	bp := policy.NewBucketPolicy(*statement, *legacy)
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "GetUser",
+		func(_ *poe.Client, _ context.Context, input *api.GetUserInput) (*api.GetUserOutput, error) {
+			if input.UserName == "user-old" {
+				return nil, nil
+			}
+			return &api.GetUserOutput{UserName: input.UserName}, nil
+		})
+
+	// act
+	gotStale, gotErr := findStalePrincipals(ctx, &coreV1.Secret{}, statement, nil, bp)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, map[string]bool{testUserArnPrefix + "user-old": true}, gotStale)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_LintBucketPolicy_UnchangedStatementOnlyWarned(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	wildcard := policy.NewStatementBuilder().WithSID("hand-written").WithEffect(policy.EffectAllow).
+		WithPrincipals(policy.AnonymousPrincipal).WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix + "user-demo").WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	origin := policy.NewBucketPolicy(*wildcard)
+	bp := policy.NewBucketPolicy(*wildcard, *statement)
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(findStalePrincipals, map[string]bool{}, nil)
+
+	// act
+	gotErr := lintBucketPolicy(ctx, &coreV1.Secret{}, "bucket-demo", statement, origin, bp)
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_FindStalePrincipals_OnlyAddedQueried(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	statement := policy.NewStatementBuilder().WithSID("user-new").WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix + "user-new").WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	legacy := policy.NewStatementBuilder().WithSID("user-old").WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix + "user-old").WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	origin := policy.NewBucketPolicy(*legacy)
+	bp := policy.NewBucketPolicy(*legacy, *statement)
+	var queried []string
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "GetUser",
+		func(_ *poe.Client, _ context.Context, input *api.GetUserInput) (*api.GetUserOutput, error) {
+			queried = append(queried, input.UserName)
+			return &api.GetUserOutput{UserName: input.UserName}, nil
+		})
+
+	// act
+	gotStale, gotErr := findStalePrincipals(ctx, &coreV1.Secret{}, statement, origin, bp)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Empty(t, gotStale)
+	assert.Equal(t, []string{"user-new"}, queried)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// When the bucket policy is full, the grant is rejected with ResourceExhausted by default. If bucket-policy-overflow
+// is userPolicy, the statement is attached to the user as an inline policy instead, which splits the grants of the
+// bucket between its policy and the user policies. The user policy only takes effect when the user and the bucket
+// belong to the same account. The BucketAccess is annotated, so the reconciliation does not expect its statement
+// in the bucket policy.
+
+var (
+	bucketPolicyOverflow = flag.String("bucket-policy-overflow", bucketPolicyOverflowReject,
+		"how to grant access when the bucket policy exceeds the size limit, reject or userPolicy")
+)
+
+const (
+	bucketPolicyOverflowReject     = "reject"
+	bucketPolicyOverflowUserPolicy = "userPolicy"
+
+	// grantedByAnnotation of BucketAccess records that its access is granted by the user policy
+	// since the bucket policy is full
+	grantedByAnnotation = "cosi.huawei.com/granted-by"
+)
+
+func checkBucketPolicyOverflow() error {
+	if *bucketPolicyOverflow != bucketPolicyOverflowReject && *bucketPolicyOverflow != bucketPolicyOverflowUserPolicy {
+		return fmt.Errorf("invalid bucket policy overflow [%s], it must be %s or %s", *bucketPolicyOverflow,
+			bucketPolicyOverflowReject, bucketPolicyOverflowUserPolicy)
+	}
+
+	return nil
+}
+
+// grantedByOverflow checks whether the access of BucketAccess is granted by the user policy
+// since the bucket policy is full
+func grantedByOverflow(ba *v1alpha1.BucketAccess) bool {
+	return ba.Annotations[grantedByAnnotation] == bucketPolicyOverflowUserPolicy
+}
+
+// markGrantedByOverflow annotates the BucketAccess of the account whose access is granted by the user policy
+func (s *provisionerServer) markGrantedByOverflow(ctx context.Context, accountName string) error {
+	ba, err := s.getBucketAccessByAccountName(ctx, accountName)
+	if err != nil {
+		return fmt.Errorf("get bucketAccess failed, error is [%v]", err)
+	}
+
+	if grantedByOverflow(ba) {
+		return nil
+	}
+
+	marked := ba.DeepCopy()
+	if marked.Annotations == nil {
+		marked.Annotations = map[string]string{}
+	}
+	marked.Annotations[grantedByAnnotation] = bucketPolicyOverflowUserPolicy
+	_, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, marked, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("annotate bucketAccess [%s/%s] failed, error is [%v]", ba.Namespace, ba.Name, err)
+	}
+
+	log.AddContext(ctx).Infof("bucketAccess [%s/%s] is granted by user policy since the bucket policy is full",
+		ba.Namespace, ba.Name)
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func newOverflowGrant() (*provisionerServer, *cosispec.DriverGrantBucketAccessRequest, *userInfo) {
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1"}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(), BucketClient: cosifake.NewSimpleClientset(ba)}
+	req := &cosispec.DriverGrantBucketAccessRequest{Name: "ba-uid-1", Parameters: map[string]string{}}
+	return s, req, &userInfo{userName: "ba-uid-1", userArn: "arn:aws:iam::domain-id:user/ba-uid-1"}
+}
+
+func Test_ProvisionerServer_SetBucketPolicy_ExceedLimit(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	var put bool
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", (*policy.BucketPolicy)(nil), nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ *policy.BucketPolicy, _ []string) error {
+			put = true
+			return nil
+		})
+	mock.ApplyGlobalVar(bucketPolicySizeLimit, 10)
+
+	// act
+	gotErr := s.setBucketPolicy(ctx, &coreV1.Secret{}, &coreV1.Secret{}, "bucket-demo", statement, nil)
+
+	// assert
+	assert.True(t, utilErrors.IsResourceExhaustedErr(gotErr))
+	assert.False(t, put)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_GrantAccess_PolicyFull_Reject(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s, req, userData := newOverflowGrant()
+	var userPolicyPut bool
+
+	// mock
+	mock := gomonkey.ApplyPrivateMethod(s, "setBucketPolicy",
+		func(_ *provisionerServer, _ context.Context, _, _ *coreV1.Secret, _ string, _ *policy.Statement,
+			_ *publicReadGrant) error {
+			return utilErrors.NewResourceExhaustedErr("bucket policy is full")
+		})
+	mock.ApplyFunc(putUserPolicy, func(_ context.Context, _ *coreV1.Secret, _, _, _ string,
+		_ *policy.Statement) error {
+		userPolicyPut = true
+		return nil
+	})
+
+	// act
+	gotErr := s.grantAccess(ctx, req, &coreV1.Secret{}, &coreV1.Secret{}, "bucket-demo", userData)
+
+	// assert
+	assert.True(t, utilErrors.IsResourceExhaustedErr(gotErr))
+	assert.False(t, userPolicyPut)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_GrantAccess_PolicyFull_SplitToUserPolicy(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s, req, userData := newOverflowGrant()
+	var userPolicyPut bool
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketPolicyOverflow, bucketPolicyOverflowUserPolicy)
+	mock.ApplyPrivateMethod(s, "setBucketPolicy",
+		func(_ *provisionerServer, _ context.Context, _, _ *coreV1.Secret, _ string, _ *policy.Statement,
+			_ *publicReadGrant) error {
+			return utilErrors.NewResourceExhaustedErr("bucket policy is full")
+		})
+	mock.ApplyFunc(putUserPolicy, func(_ context.Context, _ *coreV1.Secret, _, _, _ string,
+		_ *policy.Statement) error {
+		userPolicyPut = true
+		return nil
+	})
+
+	// act
+	gotErr := s.grantAccess(ctx, req, &coreV1.Secret{}, &coreV1.Secret{}, "bucket-demo", userData)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.True(t, userPolicyPut)
+	gotBa, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+		Get(ctx, "ba-demo", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, grantedByOverflow(gotBa))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CheckBucketPolicyOverflow_Invalid(t *testing.T) {
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketPolicyOverflow, "split")
+
+	// act
+	gotErr := checkBucketPolicyOverflow()
+
+	// assert
+	assert.ErrorContains(t, gotErr, "invalid bucket policy overflow [split]")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
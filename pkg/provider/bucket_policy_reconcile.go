@@ -0,0 +1,402 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/errors"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	bucketPolicyReconcileInterval = flag.Duration("bucket-policy-reconcile-interval",
+		defaultBucketPolicyReconcileInterval, "the interval of reconciling bucket policies with BucketAccesses, "+
+			"0 means disabled")
+	bucketPolicyReconcileRepair = flag.Bool("bucket-policy-reconcile-repair", false,
+		"repair the drift of bucket policies, otherwise the drift is only reported")
+)
+
+const (
+	defaultBucketPolicyReconcileInterval = 10 * time.Minute
+
+	bucketPolicyReconcileOperation = "reconcile policy drift"
+)
+
+// bucketGrants is what the driver expects in the policy of a bucket
+type bucketGrants struct {
+	// statements are the expected statements of granted BucketAccesses
+	statements []*policy.Statement
+
+	// users are the user names of all BucketAccesses of the bucket, including the ones being granted,
+	// whose principals are never regarded as stale
+	users map[string]bool
+
+	// unresolved are the BucketAccesses which may refer to the bucket but can not be resolved,
+	// the drift is only reported while there are any, since their grants are unknown
+	unresolved []string
+}
+
+// policyDrift is the difference between the bucket policy and the expected grants
+type policyDrift struct {
+	// missing are the expected statements which are not in effect
+	missing []*policy.Statement
+
+	// stale are the user names of driver-owned principals without BucketAccess
+	stale []string
+}
+
+func (d *policyDrift) isEmpty() bool {
+	return len(d.missing) == 0 && len(d.stale) == 0
+}
+
+func (d *policyDrift) String() string {
+	missing := make([]string, 0, len(d.missing))
+	for _, statement := range d.missing {
+		missing = append(missing, statement.Sid)
+	}
+
+	return fmt.Sprintf("missing grants of users %v, stale grants of users %v", missing, d.stale)
+}
+
+// reconcileBucketPolicies compares the driver-owned statements of bucket policies with BucketAccesses,
+// and repairs or reports the drift.
+func (s *provisionerServer) reconcileBucketPolicies(ctx context.Context) {
+	buckets, err := s.listDriverBuckets(ctx)
+	if err != nil {
+		log.AddContext(ctx).Errorf("reconcile bucket policies failed, error is [%v]", err)
+		return
+	}
+
+	grants, unresolved, err := s.collectBucketGrants(ctx)
+	if err != nil {
+		log.AddContext(ctx).Errorf("reconcile bucket policies failed, error is [%v]", err)
+		return
+	}
+
+	for _, bucket := range buckets {
+		bucketGrant, exist := grants[bucket.Name]
+		if !exist {
+			bucketGrant = &bucketGrants{users: map[string]bool{}}
+		}
+		// the bucket of a BucketAccess whose BucketClaim can not be got may be any one
+		bucketGrant.unresolved = append(bucketGrant.unresolved, unresolved...)
+
+		err = s.reconcileBucketPolicy(ctx, bucket.Status.BucketID, bucketGrant)
+		if err != nil {
+			log.AddContext(ctx).Errorf("reconcile bucket [%s] policy failed, error is [%v]",
+				bucket.Status.BucketID, err)
+		}
+	}
+}
+
+// collectBucketGrants collects the expected grants of BucketAccesses, the key is the name of Bucket object.
+// It also returns the BucketAccesses whose BucketClaims can not be got, which may refer to any bucket.
+func (s *provisionerServer) collectBucketGrants(ctx context.Context) (map[string]*bucketGrants, []string, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list bucketAccesses failed, error is [%v]", err)
+	}
+
+	grants := make(map[string]*bucketGrants)
+	bucketGrantsOf := func(bucketName string) *bucketGrants {
+		grant, exist := grants[bucketName]
+		if !exist {
+			grant = &bucketGrants{users: map[string]bool{}}
+			grants[bucketName] = grant
+		}
+		return grant
+	}
+
+	var unresolved []string
+	classes := make(map[string]*v1alpha1.BucketAccessClass)
+	secrets := make(map[string]*coreV1.Secret)
+	for i := range list.Items {
+		ba := &list.Items[i]
+		claim, err := s.BucketClient.ObjectstorageV1alpha1().BucketClaims(ba.Namespace).
+			Get(ctx, ba.Spec.BucketClaimName, metaV1.GetOptions{})
+		if err != nil {
+			log.AddContext(ctx).Warningf("get bucketClaim [%s/%s] of bucketAccess [%s] failed, error is [%v]",
+				ba.Namespace, ba.Spec.BucketClaimName, ba.Name, err)
+			unresolved = append(unresolved, ba.Namespace+"/"+ba.Name)
+			continue
+		}
+
+		// the access to a bucketClaim not bound yet is not granted to any bucket
+		if claim.Status.BucketName == "" {
+			continue
+		}
+
+		bucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().
+			Get(ctx, claim.Status.BucketName, metaV1.GetOptions{})
+		if err != nil {
+			log.AddContext(ctx).Warningf("get bucket [%s] of bucketAccess [%s/%s] failed, error is [%v]",
+				claim.Status.BucketName, ba.Namespace, ba.Name, err)
+			grant := bucketGrantsOf(claim.Status.BucketName)
+			grant.unresolved = append(grant.unresolved, ba.Namespace+"/"+ba.Name)
+			continue
+		}
+
+		if bucket.Spec.DriverName != s.Provisioner {
+			continue
+		}
+
+		grant := bucketGrantsOf(bucket.Name)
+
+		bac, exist := classes[ba.Spec.BucketAccessClassName]
+		if !exist {
+			bac, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccessClasses().
+				Get(ctx, ba.Spec.BucketAccessClassName, metaV1.GetOptions{})
+			if err != nil {
+				log.AddContext(ctx).Warningf("get bucketAccessClass [%s] of bucketAccess [%s/%s] failed, "+
+					"error is [%v]", ba.Spec.BucketAccessClassName, ba.Namespace, ba.Name, err)
+				bac = nil
+			}
+			classes[ba.Spec.BucketAccessClassName] = bac
+		}
+
+		// the user name is generated with the account secret of BucketAccess, as the grant does
+		accountName := bucketAccessAccountName(ba)
+		grant.users[accountName] = true
+		grant.users[backendUserName(accountName, s.bucketAccessAccountSecret(ctx, bac, secrets))] = true
+		if accountIdData, err := disassembleResourceId(ba.Status.AccountID); err == nil {
+			grant.users[accountIdData.resourceName] = true
+		}
+
+		if !ba.Status.AccessGranted || ba.Status.AccountID == "" || ba.DeletionTimestamp != nil || bac == nil {
+			continue
+		}
+
+		statement, err := s.buildExpectedStatement(ctx, ba, bucket, bac)
+		if err != nil {
+			log.AddContext(ctx).Warningf("build expected statement of bucketAccess [%s/%s] failed, "+
+				"error is [%v]", ba.Namespace, ba.Name, err)
+			continue
+		}
+		if statement == nil {
+			continue
+		}
+		grant.statements = append(grant.statements, statement)
+	}
+
+	return grants, unresolved, nil
+}
+
+// bucketAccessAccountSecret gets the account secret in the parameters of bucketAccessClass, the secrets are cached
+// for the run. It returns nil if the secret can not be got, then the user name is generated with the defaults.
+func (s *provisionerServer) bucketAccessAccountSecret(ctx context.Context, bac *v1alpha1.BucketAccessClass,
+	secrets map[string]*coreV1.Secret) *coreV1.Secret {
+	if bac == nil {
+		return nil
+	}
+
+	namespace, name := bac.Parameters[accountSecretNamespace], bac.Parameters[accountSecretName]
+	key := namespace + "/" + name
+	secret, exist := secrets[key]
+	if exist {
+		return secret
+	}
+
+	secret, err := s.K8sClient.CoreV1().Secrets(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if err != nil {
+		log.AddContext(ctx).Warningf("get account secret [%s] of bucketAccessClass [%s] failed, error is [%v]",
+			key, bac.Name, err)
+		secret = nil
+	}
+	secrets[key] = secret
+	return secret
+}
+
+// buildExpectedStatement builds the statement which the grant of BucketAccess puts into the bucket policy,
+// it returns nil if the access is not granted by bucket policy
+func (s *provisionerServer) buildExpectedStatement(ctx context.Context, ba *v1alpha1.BucketAccess,
+	bucket *v1alpha1.Bucket, bac *v1alpha1.BucketAccessClass) (*policy.Statement, error) {
+	// access granted by user policy, groups or bucket acl has no statement in the bucket policy
+	if !grantsByBucketPolicy(bac.Parameters) || grantedByOverflow(ba) {
+		return nil, nil
+	}
+
+	bucketIdData, err := disassembleResourceId(bucket.Status.BucketID)
+	if err != nil {
+		return nil, fmt.Errorf("disassemble bucketId failed, error is [%v]", err)
+	}
+
+	accountIdData, bacAccountSecret, err := fetchDataFromResourceId(ba.Status.AccountID, s.K8sClient)
+	if err != nil {
+		return nil, fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", ba.Status.AccountID, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return nil, fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	userName := accountIdData.resourceName
+	getUserResp, err := userClient.GetUser(ctx, &api.GetUserInput{UserName: userName})
+	if err != nil {
+		return nil, fmt.Errorf("get user [%s] failed, error is [%v]", userName, err)
+	}
+	if getUserResp == nil {
+		return nil, fmt.Errorf("user [%s] not exist", userName)
+	}
+
+	req := &cosispec.DriverGrantBucketAccessRequest{
+		BucketId:   bucket.Status.BucketID,
+		Name:       bucketAccessAccountName(ba),
+		Parameters: bac.Parameters,
+	}
+	return s.buildBucketPolicyStatement(ctx, req, &userInfo{userName: userName, userArn: getUserResp.Arn},
+		bucketIdData.resourceName, ba)
+}
+
+// reconcileBucketPolicy diffs the bucket policy against the expected grants, and repairs the drift if enabled
+func (s *provisionerServer) reconcileBucketPolicy(ctx context.Context, bucketId string, grants *bucketGrants) error {
+	// The same lock as grant and revoke, otherwise the policy may be overwritten.
+	s.keyLock.Lock(bucketId)
+	defer s.keyLock.Unlock(bucketId)
+
+	bucketIdData, bcAccountSecret, err := fetchDataFromResourceId(bucketId, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", bucketId, err)
+	}
+	bucketName := bucketIdData.resourceName
+
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName,
+		errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket, errors.ErrNoSuchBucketPolicy))
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	current := bp
+	if current == nil {
+		current = policy.NewBucketPolicy()
+	}
+
+	drift := diffBucketPolicy(current, grants)
+	if drift.isEmpty() {
+		log.AddContext(ctx).Debugf("bucket [%s] policy has no drift", bucketName)
+		return nil
+	}
+
+	if !*bucketPolicyReconcileRepair {
+		log.AddContext(ctx).Warningf("bucket [%s] policy drifts: %s", bucketName, drift)
+		return nil
+	}
+
+	if len(grants.unresolved) > 0 {
+		log.AddContext(ctx).Warningf("bucket [%s] policy drifts: %s, skip repairing it in this run since "+
+			"bucketAccesses %v can not be resolved", bucketName, drift, grants.unresolved)
+		return nil
+	}
+
+	log.AddContext(ctx).Warningf("bucket [%s] policy drifts: %s, start to repair it", bucketName, drift)
+	editedBp := current
+	for _, userName := range drift.stale {
+		editedBp = editedBp.RemoveUser(userName)
+	}
+	for _, statement := range drift.missing {
+		editedBp = editedBp.ConsolidateStatement(*statement)
+	}
+
+	findings := editedBp.Lint(policy.LintOptions{BucketName: bucketName, AllowPublicRead: *enablePublicRead,
+		ChangedSids: editedBp.ChangedSids(current)})
+	logLintFindings(ctx, bucketName, findings)
+	if policy.HasLintError(findings) {
+		return fmt.Errorf("repaired bucket [%s] policy is rejected by lint", bucketName)
+	}
+
+	err = checkBucketPolicySize(ctx, bucketName, editedBp)
+	if err != nil {
+		return err
+	}
+
+	err = s.backupBucketPolicy(ctx, bcAccountSecret, bucketName, bucketPolicyReconcileOperation, bp)
+	if err != nil {
+		return fmt.Errorf("backup bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	if len(editedBp.Statement) == 0 {
+		err = s3Agent.DeleteBucketPolicy(ctx, bucketName,
+			errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket, errors.ErrNoSuchBucketPolicy))
+	} else {
+		err = s3Agent.PutBucketPolicy(ctx, bucketName, editedBp, errors.EmptyExceptionalErrCodes)
+	}
+	if err != nil {
+		return fmt.Errorf("repair bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	log.AddContext(ctx).Infof("repair bucket [%s] policy drift successfully", bucketName)
+	return nil
+}
+
+// diffBucketPolicy finds the expected statements not in effect,
+// and the driver-owned principals whose BucketAccess no longer exists.
+// The driver-owned principals are the ones in consolidated statements,
+// and the legacy statements whose sid is the account name of BucketAccess.
+func diffBucketPolicy(bp *policy.BucketPolicy, grants *bucketGrants) *policyDrift {
+	drift := &policyDrift{}
+	for _, statement := range grants.statements {
+		if checkStatementGranted(bp, statement) != nil {
+			drift.missing = append(drift.missing, statement)
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, ps := range bp.Statement {
+		var owned []string
+		if policy.IsConsolidatedSid(ps.Sid) {
+			for _, principal := range policy.NewBucketPolicy(ps).Principals() {
+				owned = append(owned, policy.UserNameFromArn(principal))
+			}
+		} else if strings.HasPrefix(ps.Sid, bucketAccessAccountPrefix) {
+			owned = append(owned, ps.Sid)
+		}
+
+		for _, userName := range owned {
+			if userName != "" && !grants.users[userName] && !found[userName] {
+				found[userName] = true
+				drift.stale = append(drift.stale, userName)
+			}
+		}
+	}
+
+	return drift
+}
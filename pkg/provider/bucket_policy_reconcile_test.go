@@ -0,0 +1,313 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+	"github.com/huawei/cosi-driver/pkg/utils/keylock"
+)
+
+func buildGrantStatement(userName string) *policy.Statement {
+	return policy.NewStatementBuilder().WithSID(userName).WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix + userName).WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").WithSubResources("bucket-demo").Build()
+}
+
+func Test_DiffBucketPolicy(t *testing.T) {
+	// arrange
+	custom := policy.NewStatementBuilder().WithSID("custom").WithEffect(policy.EffectAllow).
+		WithPrincipals(testUserArnPrefix + "custom").WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	bp := policy.NewBucketPolicy(*buildGrantStatement("ba-3"), *custom).
+		ConsolidateStatement(*buildGrantStatement("ba-1")).
+		ConsolidateStatement(*buildGrantStatement("ba-2"))
+	grants := &bucketGrants{
+		statements: []*policy.Statement{buildGrantStatement("ba-1"), buildGrantStatement("ba-4")},
+		users:      map[string]bool{"ba-1": true, "ba-4": true, "ba-5": true},
+	}
+
+	// act
+	gotDrift := diffBucketPolicy(bp, grants)
+
+	// assert
+	assert.Equal(t, []*policy.Statement{buildGrantStatement("ba-4")}, gotDrift.missing)
+	assert.Equal(t, []string{"ba-3", "ba-2"}, gotDrift.stale)
+}
+
+func Test_DiffBucketPolicy_UserArnWithPath(t *testing.T) {
+	// arrange
+	pathArnPrefix := testUserArnPrefix + "cosi/default/cosi.huawei.com/ns/name/"
+	statement := func(userName string) policy.Statement {
+		return *policy.NewStatementBuilder().WithEffect(policy.EffectAllow).
+			WithPrincipals(pathArnPrefix + userName).WithActions(policy.AllowedReadActions).
+			WithResources("bucket-demo").WithSubResources("bucket-demo").Build()
+	}
+	bp := (&policy.BucketPolicy{}).ConsolidateStatement(statement("ba-1")).ConsolidateStatement(statement("ba-2"))
+	grants := &bucketGrants{users: map[string]bool{"ba-1": true}}
+
+	// act
+	gotDrift := diffBucketPolicy(bp, grants)
+
+	// assert
+	assert.Equal(t, []string{"ba-2"}, gotDrift.stale)
+}
+
+func Test_CollectBucketGrants_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	granted := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-granted", Namespace: "app", UID: "uid-1"},
+		Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-demo", BucketAccessClassName: "bac-demo"},
+		Status:     v1alpha1.BucketAccessStatus{AccessGranted: true, AccountID: "default/bac-secret/ba-uid-1"},
+	}
+	granting := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-granting", Namespace: "app", UID: "uid-2"},
+		Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-demo"},
+	}
+	claim := &v1alpha1.BucketClaim{
+		ObjectMeta: metaV1.ObjectMeta{Name: "claim-demo", Namespace: "app"},
+		Status:     v1alpha1.BucketClaimStatus{BucketName: "bucket-object"},
+	}
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "bucket-object"},
+		Spec:       v1alpha1.BucketSpec{DriverName: "cosi.huawei.com"},
+	}
+	bac := &v1alpha1.BucketAccessClass{ObjectMeta: metaV1.ObjectMeta{Name: "bac-demo"},
+		Parameters: map[string]string{accountSecretNamespace: "default", accountSecretName: "bac-secret"}}
+	s := &provisionerServer{
+		Provisioner:  "cosi.huawei.com",
+		K8sClient:    fake.NewSimpleClientset(),
+		BucketClient: cosifake.NewSimpleClientset(granted, granting, claim, bucket, bac),
+	}
+	statement := buildGrantStatement("ba-uid-1")
+
+	// mock
+	mock := gomonkey.ApplyPrivateMethod(s, "buildExpectedStatement",
+		func(_ *provisionerServer, _ context.Context, _ *v1alpha1.BucketAccess,
+			_ *v1alpha1.Bucket, _ *v1alpha1.BucketAccessClass) (*policy.Statement, error) {
+			return statement, nil
+		})
+
+	// act
+	gotGrants, gotUnresolved, gotErr := s.collectBucketGrants(ctx)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Empty(t, gotUnresolved)
+	assert.Equal(t, map[string]*bucketGrants{"bucket-object": {
+		statements: []*policy.Statement{statement},
+		users:      map[string]bool{"ba-uid-1": true, "ba-uid-2": true},
+	}}, gotGrants)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ReconcileBucketPolicy_Repair(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(), keyLock: keylock.NewKeyLock(keyLockSize)}
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	current := policy.NewBucketPolicy().ConsolidateStatement(*buildGrantStatement("ba-stale"))
+	grants := &bucketGrants{
+		statements: []*policy.Statement{buildGrantStatement("ba-1")},
+		users:      map[string]bool{"ba-1": true},
+	}
+	var repaired *policy.BucketPolicy
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketPolicyReconcileRepair, true)
+	mock.ApplyFuncReturn(fetchDataFromResourceId, &resourceIdInfo{resourceName: "bucket-demo"}, bcSecret, nil)
+	mock.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", current, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, bp *policy.BucketPolicy, _ []string) error {
+			repaired = bp
+			return nil
+		})
+
+	// act
+	gotErr := s.reconcileBucketPolicy(ctx, "default/bc-secret/bucket-demo", grants)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{testUserArnPrefix + "ba-1"}, repaired.Principals())
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CollectBucketGrants_Unresolved(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	claimLost := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-claim-lost", Namespace: "app", UID: "uid-1"},
+		Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-lost"},
+	}
+	bucketLost := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-bucket-lost", Namespace: "app", UID: "uid-2"},
+		Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-demo"},
+	}
+	claim := &v1alpha1.BucketClaim{
+		ObjectMeta: metaV1.ObjectMeta{Name: "claim-demo", Namespace: "app"},
+		Status:     v1alpha1.BucketClaimStatus{BucketName: "bucket-object"},
+	}
+	s := &provisionerServer{
+		Provisioner:  "cosi.huawei.com",
+		K8sClient:    fake.NewSimpleClientset(),
+		BucketClient: cosifake.NewSimpleClientset(claimLost, bucketLost, claim),
+	}
+
+	// act
+	gotGrants, gotUnresolved, gotErr := s.collectBucketGrants(ctx)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"app/ba-claim-lost"}, gotUnresolved)
+	assert.Equal(t, []string{"app/ba-bucket-lost"}, gotGrants["bucket-object"].unresolved)
+}
+
+func Test_ReconcileBucketPolicy_Unresolved_NotRepaired(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(), keyLock: keylock.NewKeyLock(keyLockSize)}
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	current := policy.NewBucketPolicy().ConsolidateStatement(*buildGrantStatement("ba-unknown"))
+	grants := &bucketGrants{users: map[string]bool{}, unresolved: []string{"app/ba-claim-lost"}}
+	var repaired bool
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketPolicyReconcileRepair, true)
+	mock.ApplyFuncReturn(fetchDataFromResourceId, &resourceIdInfo{resourceName: "bucket-demo"}, bcSecret, nil)
+	mock.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", current, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ []string) error {
+			repaired = true
+			return nil
+		})
+
+	// act
+	gotErr := s.reconcileBucketPolicy(ctx, "default/bc-secret/bucket-demo", grants)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.False(t, repaired)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CollectBucketGrants_UserNameOfAccountSecret(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	granting := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-granting", Namespace: "app", UID: "uid-2"},
+		Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-demo", BucketAccessClassName: "bac-demo"},
+	}
+	claim := &v1alpha1.BucketClaim{
+		ObjectMeta: metaV1.ObjectMeta{Name: "claim-demo", Namespace: "app"},
+		Status:     v1alpha1.BucketClaimStatus{BucketName: "bucket-object"},
+	}
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "bucket-object"},
+		Spec:       v1alpha1.BucketSpec{DriverName: "cosi.huawei.com"},
+	}
+	bac := &v1alpha1.BucketAccessClass{ObjectMeta: metaV1.ObjectMeta{Name: "bac-demo"},
+		Parameters: map[string]string{accountSecretNamespace: "default", accountSecretName: "bac-secret"}}
+	secret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bac-secret", Namespace: "default"},
+		Data: map[string][]byte{maxUserNameLength: []byte("12")}}
+	s := &provisionerServer{
+		Provisioner:  "cosi.huawei.com",
+		K8sClient:    fake.NewSimpleClientset(secret),
+		BucketClient: cosifake.NewSimpleClientset(granting, claim, bucket, bac),
+	}
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(userNameStrategy, namingStrategyClusterPrefix)
+
+	// act
+	gotGrants, _, gotErr := s.collectBucketGrants(ctx)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Len(t, backendUserName("ba-uid-2", secret), 12)
+	assert.True(t, gotGrants["bucket-object"].users[backendUserName("ba-uid-2", secret)])
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_BuildExpectedStatement_BucketAccessInHand(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	ba := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1",
+			CreationTimestamp: metaV1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))},
+		Status: v1alpha1.BucketAccessStatus{AccessGranted: true, AccountID: "default/bac-secret/ba-uid-1"},
+	}
+	bucket := &v1alpha1.Bucket{Status: v1alpha1.BucketStatus{BucketID: "default/bc-secret/bucket-demo"}}
+	bac := &v1alpha1.BucketAccessClass{Parameters: map[string]string{accessDuration: "720h"}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(), BucketClient: cosifake.NewSimpleClientset()}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-uid-1"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "GetUser", &api.GetUserOutput{UserName: "ba-uid-1",
+		Arn: testUserArnPrefix + "ba-uid-1"}, nil)
+
+	// act
+	gotStatement, gotErr := s.buildExpectedStatement(ctx, ba, bucket, bac)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, policy.Condition{policy.ConditionDateLessThan: {
+		policy.ConditionKeyCurrentTime: {"2026-01-31T00:00:00Z"}}}, gotStatement.Condition)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
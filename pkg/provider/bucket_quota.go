@@ -0,0 +1,289 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// The bucket quotas limit the number of buckets and the sum of their declared capacity, which is bucketCapacity
+// in bucketClass parameters. They are configured in the configMap 'cosi-bucket-quota' of the driver namespace,
+// keyed by 'namespace.{namespace of BucketClaim}' or 'bucketClass.{name of BucketClass}', the value likes
+// '{"maxBuckets": 10, "maxCapacity": "1Ti"}'. The buckets being created are counted in the order they are created,
+// so the earlier BucketClaims are not starved by the later ones.
+
+const (
+	bucketQuotaConfigMapName = "cosi-bucket-quota"
+
+	bucketQuotaNamespacePrefix   = "namespace."
+	bucketQuotaBucketClassPrefix = "bucketClass."
+)
+
+// bucketQuota is the value of bucket quota configMap data
+type bucketQuota struct {
+	// MaxBuckets is the most buckets, unlimited if it is not set
+	MaxBuckets *int64 `json:"maxBuckets,omitempty"`
+
+	// MaxCapacity is the most sum of declared capacity likes '1Ti', unlimited if it is empty
+	MaxCapacity string `json:"maxCapacity,omitempty"`
+}
+
+// bucketQuotaScope is a namespace or a bucketClass limited by the quota
+type bucketQuotaScope struct {
+	key      string
+	quota    bucketQuota
+	capacity int64
+	contains func(bucket *v1alpha1.Bucket) bool
+}
+
+func checkBucketCapacityParameters(parameters map[string]string) error {
+	if value, exist := parameters[bucketCapacity]; exist {
+		capacity, err := resource.ParseQuantity(value)
+		if err != nil || capacity.Sign() < 0 {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], it should be a non-negative "+
+				"quantity likes 10Gi", bucketCapacity, value))
+		}
+	}
+
+	return nil
+}
+
+// declaredCapacityOf returns the declared capacity of the bucket in bytes, 0 if it is not declared
+func declaredCapacityOf(parameters map[string]string) int64 {
+	capacity, err := resource.ParseQuantity(parameters[bucketCapacity])
+	if err != nil {
+		return 0
+	}
+
+	return capacity.Value()
+}
+
+func parseBucketQuota(key, value string) (bucketQuota, int64, error) {
+	var quota bucketQuota
+	err := json.Unmarshal([]byte(value), &quota)
+	if err != nil {
+		return quota, 0, fmt.Errorf("unmarshal bucket quota [%s] failed, error is [%v]", key, err)
+	}
+
+	if quota.MaxBuckets != nil && *quota.MaxBuckets < 0 {
+		return quota, 0, fmt.Errorf("invalid maxBuckets [%d] of bucket quota [%s]", *quota.MaxBuckets, key)
+	}
+
+	if quota.MaxCapacity == "" {
+		return quota, 0, nil
+	}
+
+	capacity, err := resource.ParseQuantity(quota.MaxCapacity)
+	if err != nil || capacity.Sign() < 0 {
+		return quota, 0, fmt.Errorf("invalid maxCapacity [%s] of bucket quota [%s]", quota.MaxCapacity, key)
+	}
+
+	return quota, capacity.Value(), nil
+}
+
+// checkBucketQuota rejects the bucket with ResourceExhausted if it exceeds the quota of its namespace or bucketClass.
+// The bucket passed the check once is not checked again when the request is retried, so the bucket created before
+// is not orphaned by a lowered quota.
+func (s *provisionerServer) checkBucketQuota(ctx context.Context, req *cosispec.DriverCreateBucketRequest,
+	bucketName string) error {
+	if _, checked := s.quotaCheckedBuckets.Load(req.GetName()); checked {
+		return nil
+	}
+
+	namespace := driverNamespace()
+	cm, err := s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, bucketQuotaConfigMapName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get configMap [%s/%s] failed, error is [%v]", namespace, bucketQuotaConfigMapName, err)
+	}
+
+	bucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, req.GetName(), metaV1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] failed, error is [%v]", req.GetName(), err)
+	}
+
+	// the bucket is placed after it passed the check
+	if bucket.Annotations[placedAccountSecretAnnotation] != "" {
+		log.AddContext(ctx).Infof("bucket [%s] is placed before, skip bucket quota check", req.GetName())
+		s.quotaCheckedBuckets.Store(req.GetName(), struct{}{})
+		return nil
+	}
+
+	scopes, err := bucketQuotaScopes(cm.Data, bucket)
+	if err != nil || len(scopes) == 0 {
+		return err
+	}
+
+	created, err := bucketCreatedByRequest(ctx, s.K8sClient, req, bucketName)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		log.AddContext(ctx).Infof("bucket [%s] is created by request [%s] before, skip bucket quota check",
+			bucketName, req.GetName())
+		s.quotaCheckedBuckets.Store(req.GetName(), struct{}{})
+		return nil
+	}
+
+	err = s.checkBucketQuotaScopes(ctx, req, bucket, scopes)
+	if err != nil {
+		return err
+	}
+
+	s.quotaCheckedBuckets.Store(req.GetName(), struct{}{})
+	return nil
+}
+
+// bucketCreatedByRequest checks whether the backend bucket exists and is tagged as created by the request
+func bucketCreatedByRequest(ctx context.Context, clientset kubernetes.Interface,
+	req *cosispec.DriverCreateBucketRequest, bucketName string) (bool, error) {
+	// the bucket of multiple backends is created after it is placed
+	if multiBackendPlacement(req.GetParameters()) {
+		return false, nil
+	}
+
+	s3Agent, err := newS3Client(ctx, clientset, req.GetParameters())
+	if err != nil {
+		return false, err
+	}
+
+	err = s3Agent.CheckBucketExist(ctx, bucketName)
+	if err != nil {
+		log.AddContext(ctx).Infof("bucket [%s] is not created before, reason [%v]", bucketName, err)
+		return false, nil
+	}
+
+	tags, err := s3Agent.GetBucketTags(ctx, bucketName)
+	if err != nil {
+		return false, fmt.Errorf("get bucket [%s] tags failed, error is [%v]", bucketName, err)
+	}
+
+	return tags[bucketCreatedByTag] == req.GetName(), nil
+}
+
+func (s *provisionerServer) checkBucketQuotaScopes(ctx context.Context, req *cosispec.DriverCreateBucketRequest,
+	bucket *v1alpha1.Bucket, scopes []bucketQuotaScope) error {
+	for _, scope := range scopes {
+		if scope.quota.MaxCapacity != "" {
+			if _, exist := req.GetParameters()[bucketCapacity]; !exist {
+				return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("%s is required by the capacity quota of [%s]",
+					bucketCapacity, scope.key))
+			}
+		}
+	}
+
+	// the buckets are listed consistently, a stale list misses the buckets just created and lets the quota be
+	// exceeded, they are listed only when the bucket is limited by quota
+	list, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list buckets failed, error is [%v]", err)
+	}
+
+	for _, scope := range scopes {
+		count, capacity := int64(1), declaredCapacityOf(req.GetParameters())
+		for i := range list.Items {
+			counted := &list.Items[i]
+			if s.countedInBucketQuota(counted, bucket) && scope.contains(counted) {
+				count++
+				capacity += declaredCapacityOf(counted.Spec.Parameters)
+			}
+		}
+
+		if scope.quota.MaxBuckets != nil && count > *scope.quota.MaxBuckets {
+			return utilErrors.NewResourceExhaustedErr(fmt.Sprintf("bucket [%s] exceeds the bucket quota of [%s], "+
+				"[%d] buckets are more than maxBuckets [%d]", req.GetName(), scope.key, count,
+				*scope.quota.MaxBuckets))
+		}
+
+		if scope.quota.MaxCapacity != "" && capacity > scope.capacity {
+			return utilErrors.NewResourceExhaustedErr(fmt.Sprintf("bucket [%s] exceeds the bucket quota of [%s], "+
+				"declared capacity [%d] bytes is more than maxCapacity [%s]", req.GetName(), scope.key, capacity,
+				scope.quota.MaxCapacity))
+		}
+
+		log.AddContext(ctx).Infof("bucket [%s] is within the bucket quota of [%s], [%d] buckets, declared capacity "+
+			"[%d] bytes", req.GetName(), scope.key, count, capacity)
+	}
+
+	return nil
+}
+
+// bucketQuotaScopes returns the quotas of the namespace and the bucketClass of the bucket
+func bucketQuotaScopes(data map[string]string, bucket *v1alpha1.Bucket) ([]bucketQuotaScope, error) {
+	var scopes []bucketQuotaScope
+	if bucket.Spec.BucketClaim != nil {
+		claimNamespace := bucket.Spec.BucketClaim.Namespace
+		key := bucketQuotaNamespacePrefix + claimNamespace
+		if value, exist := data[key]; exist {
+			quota, capacity, err := parseBucketQuota(key, value)
+			if err != nil {
+				return nil, err
+			}
+			scopes = append(scopes, bucketQuotaScope{key: key, quota: quota, capacity: capacity,
+				contains: func(counted *v1alpha1.Bucket) bool {
+					return counted.Spec.BucketClaim != nil && counted.Spec.BucketClaim.Namespace == claimNamespace
+				}})
+		}
+	}
+
+	className := bucket.Spec.BucketClassName
+	key := bucketQuotaBucketClassPrefix + className
+	if value, exist := data[key]; exist && className != "" {
+		quota, capacity, err := parseBucketQuota(key, value)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, bucketQuotaScope{key: key, quota: quota, capacity: capacity,
+			contains: func(counted *v1alpha1.Bucket) bool {
+				return counted.Spec.BucketClassName == className
+			}})
+	}
+
+	return scopes, nil
+}
+
+// countedInBucketQuota returns whether the other bucket counts against the quota of the bucket being created,
+// which are the provisioned buckets and the buckets being created earlier
+func (s *provisionerServer) countedInBucketQuota(counted, bucket *v1alpha1.Bucket) bool {
+	if counted.Name == bucket.Name || counted.Spec.DriverName != s.Provisioner || counted.DeletionTimestamp != nil {
+		return false
+	}
+
+	if counted.Status.BucketID != "" {
+		return true
+	}
+
+	if !counted.CreationTimestamp.Equal(&bucket.CreationTimestamp) {
+		return counted.CreationTimestamp.Before(&bucket.CreationTimestamp)
+	}
+
+	return strings.Compare(counted.Name, bucket.Name) < 0
+}
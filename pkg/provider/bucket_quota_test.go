@@ -0,0 +1,296 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func newQuotaBucket(name, namespace, className, capacity, bucketId string, created time.Time) *v1alpha1.Bucket {
+	return &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, CreationTimestamp: metaV1.NewTime(created)},
+		Spec: v1alpha1.BucketSpec{
+			DriverName:      "cosi.huawei.com",
+			BucketClassName: className,
+			BucketClaim:     &coreV1.ObjectReference{Namespace: namespace, Name: name},
+			Parameters:      map[string]string{bucketCapacity: capacity},
+		},
+		Status: v1alpha1.BucketStatus{BucketID: bucketId},
+	}
+}
+
+func newQuotaServer(data map[string]string, buckets ...*v1alpha1.Bucket) *provisionerServer {
+	cm := &coreV1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: bucketQuotaConfigMapName,
+		Namespace: driverNamespace()}, Data: data}
+	bucketClient := cosifake.NewSimpleClientset()
+	for _, bucket := range buckets {
+		_, _ = bucketClient.ObjectstorageV1alpha1().Buckets().Create(context.TODO(), bucket, metaV1.CreateOptions{})
+	}
+
+	return &provisionerServer{Provisioner: "cosi.huawei.com", K8sClient: fake.NewSimpleClientset(cm),
+		BucketClient: bucketClient}
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_NoConfigMap(t *testing.T) {
+	// arrange
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bucket-1", Parameters: map[string]string{}}
+
+	// act
+	gotErr := s.checkBucketQuota(context.TODO(), req, "bucket-1")
+
+	// assert
+	assert.NoError(t, gotErr)
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_MaxBuckets(t *testing.T) {
+	// arrange
+	now := time.Now()
+	s := newQuotaServer(map[string]string{"namespace.tenant-a": `{"maxBuckets": 2}`},
+		newQuotaBucket("bucket-1", "tenant-a", "gold", "1Gi", "huawei-cosi/secret/bucket-1", now),
+		newQuotaBucket("bucket-2", "tenant-b", "gold", "1Gi", "huawei-cosi/secret/bucket-2", now),
+		newQuotaBucket("bucket-3", "tenant-a", "gold", "1Gi", "", now.Add(time.Minute)),
+		newQuotaBucket("bucket-4", "tenant-a", "gold", "1Gi", "", now.Add(2*time.Minute)))
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(bucketCreatedByRequest, false, nil)
+
+	// act
+	gotWithinErr := s.checkBucketQuota(context.TODO(), &cosispec.DriverCreateBucketRequest{Name: "bucket-3"},
+		"bucket-3")
+	gotExceededErr := s.checkBucketQuota(context.TODO(), &cosispec.DriverCreateBucketRequest{Name: "bucket-4"},
+		"bucket-4")
+
+	// assert
+	assert.NoError(t, gotWithinErr)
+	assert.True(t, utilErrors.IsResourceExhaustedErr(gotExceededErr))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_MaxCapacity(t *testing.T) {
+	// arrange
+	now := time.Now()
+	s := newQuotaServer(map[string]string{"bucketClass.gold": `{"maxCapacity": "10Gi"}`},
+		newQuotaBucket("bucket-1", "tenant-a", "gold", "6Gi", "huawei-cosi/secret/bucket-1", now),
+		newQuotaBucket("bucket-2", "tenant-b", "silver", "6Gi", "huawei-cosi/secret/bucket-2", now),
+		newQuotaBucket("bucket-3", "tenant-b", "gold", "4Gi", "", now))
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(bucketCreatedByRequest, false, nil)
+
+	// act
+	gotExceededErr := s.checkBucketQuota(context.TODO(), &cosispec.DriverCreateBucketRequest{Name: "bucket-3",
+		Parameters: map[string]string{bucketCapacity: "5Gi"}}, "bucket-3")
+	gotUndeclaredErr := s.checkBucketQuota(context.TODO(), &cosispec.DriverCreateBucketRequest{Name: "bucket-3",
+		Parameters: map[string]string{}}, "bucket-3")
+	gotWithinErr := s.checkBucketQuota(context.TODO(), &cosispec.DriverCreateBucketRequest{Name: "bucket-3",
+		Parameters: map[string]string{bucketCapacity: "4Gi"}}, "bucket-3")
+
+	// assert
+	assert.True(t, utilErrors.IsResourceExhaustedErr(gotExceededErr))
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotUndeclaredErr))
+	assert.NoError(t, gotWithinErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_CreatedBefore(t *testing.T) {
+	// arrange
+	now := time.Now()
+	s := newQuotaServer(map[string]string{"namespace.tenant-a": `{"maxBuckets": 1}`},
+		newQuotaBucket("bucket-1", "tenant-a", "gold", "1Gi", "huawei-cosi/secret/bucket-1", now),
+		newQuotaBucket("bucket-2", "tenant-a", "gold", "1Gi", "", now.Add(time.Minute)))
+	req := &cosispec.DriverCreateBucketRequest{Name: "bucket-2"}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(bucketCreatedByRequest, true, nil)
+
+	// act
+	gotErr := s.checkBucketQuota(context.TODO(), req, "bucket-2")
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_Placed(t *testing.T) {
+	// arrange
+	now := time.Now()
+	placed := newQuotaBucket("bucket-2", "tenant-a", "gold", "1Gi", "", now.Add(time.Minute))
+	placed.Annotations = map[string]string{placedAccountSecretAnnotation: "huawei-cosi/secret"}
+	s := newQuotaServer(map[string]string{"namespace.tenant-a": `{"maxBuckets": 1}`},
+		newQuotaBucket("bucket-1", "tenant-a", "gold", "1Gi", "huawei-cosi/secret/bucket-1", now), placed)
+	req := &cosispec.DriverCreateBucketRequest{Name: "bucket-2"}
+	backendChecked := false
+
+	// mock
+	mock := gomonkey.ApplyFunc(bucketCreatedByRequest, func(_ context.Context, _ kubernetes.Interface,
+		_ *cosispec.DriverCreateBucketRequest, _ string) (bool, error) {
+		backendChecked = true
+		return false, nil
+	})
+
+	// act
+	gotErr := s.checkBucketQuota(context.TODO(), req, "bucket-2")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.False(t, backendChecked)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_CheckedOnce(t *testing.T) {
+	// arrange
+	now := time.Now()
+	s := newQuotaServer(map[string]string{"namespace.tenant-a": `{"maxBuckets": 1}`},
+		newQuotaBucket("bucket-1", "tenant-a", "gold", "1Gi", "", now))
+	req := &cosispec.DriverCreateBucketRequest{Name: "bucket-1"}
+	listed := 0
+	s.BucketClient.(*cosifake.Clientset).PrependReactor("list", "buckets",
+		func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			listed++
+			return false, nil, nil
+		})
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(bucketCreatedByRequest, false, nil)
+
+	// act
+	gotFirstErr := s.checkBucketQuota(context.TODO(), req, "bucket-1")
+	_, _ = s.BucketClient.ObjectstorageV1alpha1().Buckets().Create(context.TODO(),
+		newQuotaBucket("bucket-0", "tenant-a", "gold", "1Gi", "huawei-cosi/secret/bucket-0", now),
+		metaV1.CreateOptions{})
+	gotRetriedErr := s.checkBucketQuota(context.TODO(), req, "bucket-1")
+
+	// assert
+	assert.NoError(t, gotFirstErr)
+	assert.NoError(t, gotRetriedErr)
+	assert.Equal(t, 1, listed)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_BucketCreatedByRequest(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name      string
+		existErr  error
+		tags      map[string]string
+		wantValue bool
+	}{
+		{name: "not exist", existErr: errors.New("not found")},
+		{name: "created by request", tags: map[string]string{bucketCreatedByTag: "bucket-1"}, wantValue: true},
+		{name: "created by other", tags: map[string]string{bucketCreatedByTag: "bucket-2"}},
+		{name: "not created by driver", tags: map[string]string{}},
+	}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bucket-1", Parameters: map[string]string{}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// mock
+			s3Agent := &agent.S3Agent{}
+			mock := gomonkey.ApplyFuncReturn(newS3Client, s3Agent, nil)
+			mock.ApplyMethodReturn(s3Agent, "CheckBucketExist", c.existErr)
+			mock.ApplyMethodReturn(s3Agent, "GetBucketTags", c.tags, nil)
+
+			// act
+			gotValue, gotErr := bucketCreatedByRequest(context.TODO(), fake.NewSimpleClientset(), req, "bucket-demo")
+
+			// assert
+			assert.NoError(t, gotErr)
+			assert.Equal(t, c.wantValue, gotValue)
+
+			// cleanup
+			t.Cleanup(func() {
+				mock.Reset()
+			})
+		})
+	}
+}
+
+func Test_ProvisionerServer_CheckBucketQuota_InvalidQuota(t *testing.T) {
+	// arrange
+	s := newQuotaServer(map[string]string{"namespace.tenant-a": `{"maxCapacity": "lots"}`},
+		newQuotaBucket("bucket-1", "tenant-a", "gold", "1Gi", "", time.Now()))
+
+	// act
+	gotErr := s.checkBucketQuota(context.TODO(), &cosispec.DriverCreateBucketRequest{Name: "bucket-1",
+		Parameters: map[string]string{bucketCapacity: "1Gi"}}, "bucket-1")
+
+	// assert
+	assert.ErrorContains(t, gotErr, "invalid maxCapacity")
+	assert.False(t, utilErrors.IsResourceExhaustedErr(gotErr))
+}
+
+func Test_CheckBucketCapacityParameters(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    bool
+	}{
+		{name: "not set", parameters: map[string]string{}},
+		{name: "valid", parameters: map[string]string{bucketCapacity: "10Gi"}},
+		{name: "invalid", parameters: map[string]string{bucketCapacity: "ten"}, wantErr: true},
+		{name: "negative", parameters: map[string]string{bucketCapacity: "-1Gi"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotErr := checkBucketCapacityParameters(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, utilErrors.IsInvalidArgumentErr(gotErr))
+		})
+	}
+}
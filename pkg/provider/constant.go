@@ -25,6 +25,9 @@ const (
 	password      = "password"
 	maxConcurrent = "maxConcurrent"
 
+	// maxUserNameLength in account secret data limits the length of backend user names, 64 by default
+	maxUserNameLength = "maxUserNameLength"
+
 	// these keys are used in access/cred secret data
 	accessAk = "accessKeyID"
 	accessSk = "accessSecretKey"
@@ -38,6 +41,71 @@ const (
 	bucketACL              = "bucketACL"
 	bucketLocation         = "bucketLocation"
 
+	// accountSecretNames in bucketClass parameters is a comma separated list of account secrets likes 'name' in
+	// accountSecretNamespace or '{namespace}/{name}', accountSecretSelector is a label selector over the secrets in
+	// accountSecretNamespace, the bucket is placed on one of them by placementStrategy, which is roundRobin by default
+	accountSecretNames    = "accountSecretNames"
+	accountSecretSelector = "accountSecretSelector"
+	placementStrategy     = "placementStrategy"
+
+	// bucketNameTemplate in bucketClass parameters names the bucket likes '{prefix}-{namespace}-{claim}',
+	// {prefix} is bucketNamePrefix, which is the cluster id by default, {name} is the name generated by cosi
+	bucketNameTemplate = "bucketNameTemplate"
+	bucketNamePrefix   = "bucketNamePrefix"
+
+	// adoptExisting in bucketClass parameters decides whether the existing bucket owned by the account is adopted
+	// when it is created, true by default, adoptionTag likes 'key=value' is the tag the adopted bucket must carry,
+	// which is also checked in the parameters of static Bucket
+	adoptExisting = "adoptExisting"
+	adoptionTag   = "adoptionTag"
+
+	// cloneSourceBucket in bucketClass parameters is the bucket of the same account whose objects are copied into
+	// the created bucket, at most cloneParallelism objects are copied at the same time, 8 by default
+	cloneSourceBucket = "cloneSourceBucket"
+	cloneParallelism  = "cloneParallelism"
+
+	// cascadeDeleteAccess in bucketClass parameters removes the users granted on the bucket together with their
+	// access keys when the bucket is deleted, if they are created by the driver and not granted on other buckets
+	cascadeDeleteAccess = "cascadeDeleteAccess"
+
+	// bucketCapacity in bucketClass parameters is the declared capacity of the bucket likes '10Gi', which is counted
+	// against the capacity quota of the bucket
+	bucketCapacity = "bucketCapacity"
+
+	// these keys are used to customize the bucket policy statement in bucketAccessClass parameters,
+	// the template is either inline or stored in a configMap
+	bucketPolicyTemplate                   = "bucketPolicyTemplate"
+	bucketPolicyTemplateConfigMapName      = "bucketPolicyTemplateConfigMapName"
+	bucketPolicyTemplateConfigMapNamespace = "bucketPolicyTemplateConfigMapNamespace"
+	bucketPolicyTemplateConfigMapKey       = "bucketPolicyTemplateConfigMapKey"
+	defaultBucketPolicyTemplateKey         = "statement.json"
+
+	// grantMode in bucketAccessClass parameters selects where the access profile is attached,
+	// the bucket policy of the bucket by default, or the inline policy of the user
+	grantMode             = "grantMode"
+	grantModeBucketPolicy = "bucketPolicy"
+	grantModeUserPolicy   = "userPolicy"
+	grantModeGroup        = "group"
+	grantModeACL          = "acl"
+
+	// groups in bucketAccessClass parameters is a comma separated list of pre-defined groups the user joins,
+	// access is granted only by the groups if grant mode is group
+	groups = "groups"
+
+	// publicRead in bucketAccessClass parameters grants everyone to get objects of the bucket,
+	// the objects are limited to publicReadPrefix if it is set
+	publicRead       = "publicRead"
+	publicReadPrefix = "publicReadPrefix"
+
+	// accessDuration in bucketAccessClass parameters limits how long the access of BucketAccess lasts since it is
+	// created, the format likes '720h'
+	accessDuration = "accessDuration"
+
+	// keyRotationInterval in bucketAccessClass parameters is how often a new access key is issued to BucketAccess,
+	// the old access keys are deleted after keyRotationGracePeriod, which is 1h by default
+	keyRotationInterval    = "keyRotationInterval"
+	keyRotationGracePeriod = "keyRotationGracePeriod"
+
 	// these keys are protocols
 	s3Protocol = "s3"
 
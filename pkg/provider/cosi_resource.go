@@ -0,0 +1,164 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	// the cosi sidecar names the account of BucketAccess as 'ba-{BucketAccess UID}'
+	bucketAccessAccountPrefix = "ba-"
+
+	// bucketAccessAccountLabel of BucketAccess is its account name, it is labeled the first time the BucketAccess
+	// is found by the account name, so it is found by the label selector afterward instead of listing all
+	bucketAccessAccountLabel = "cosi.huawei.com/account-name"
+)
+
+func bucketAccessAccountName(ba *v1alpha1.BucketAccess) string {
+	return bucketAccessAccountPrefix + string(ba.UID)
+}
+
+// bucketAccessOwnsUser checks whether the backend user is named for BucketAccess,
+// either recorded in its AccountId or generated from its account name
+func bucketAccessOwnsUser(ba *v1alpha1.BucketAccess, userName string) bool {
+	if accountIdData, err := disassembleResourceId(ba.Status.AccountID); err == nil &&
+		accountIdData.resourceName == userName {
+		return true
+	}
+
+	return userNameMatches(bucketAccessAccountName(ba), userName)
+}
+
+// getBucketAccessByAccountName finds the BucketAccess which the account name of grant request is generated from,
+// the backend user name of BucketAccess is accepted as well
+func (s *provisionerServer) getBucketAccessByAccountName(ctx context.Context,
+	accountName string) (*v1alpha1.BucketAccess, error) {
+	if strings.HasPrefix(accountName, bucketAccessAccountPrefix) {
+		list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+			List(ctx, metaV1.ListOptions{LabelSelector: bucketAccessAccountLabel + "=" + accountName})
+		if err != nil {
+			return nil, fmt.Errorf("list bucketAccesses by account [%s] failed, error is [%v]", accountName, err)
+		}
+
+		for i := range list.Items {
+			if bucketAccessAccountName(&list.Items[i]) == accountName {
+				return &list.Items[i], nil
+			}
+		}
+	}
+
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list bucketAccesses failed, error is [%v]", err)
+	}
+
+	for i := range list.Items {
+		if bucketAccessOwnsUser(&list.Items[i], accountName) {
+			s.labelBucketAccessAccount(ctx, &list.Items[i])
+			return &list.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("bucketAccess of account [%s] not found", accountName)
+}
+
+// bucketAccessOfGrant returns the BucketAccess of grant if it is known already,
+// otherwise the BucketAccess is looked up by the account name of grant request
+func (s *provisionerServer) bucketAccessOfGrant(ctx context.Context, accountName string,
+	ba *v1alpha1.BucketAccess) (*v1alpha1.BucketAccess, error) {
+	if ba != nil {
+		return ba, nil
+	}
+
+	ba, err := s.getBucketAccessByAccountName(ctx, accountName)
+	if err != nil {
+		return nil, fmt.Errorf("get bucketAccess failed, error is [%v]", err)
+	}
+
+	return ba, nil
+}
+
+// labelBucketAccessAccount labels the account name of BucketAccess, the failure is only logged since
+// the BucketAccess is still found by listing all
+func (s *provisionerServer) labelBucketAccessAccount(ctx context.Context, ba *v1alpha1.BucketAccess) {
+	accountName := bucketAccessAccountName(ba)
+	if ba.Labels[bucketAccessAccountLabel] == accountName {
+		return
+	}
+
+	labeled := ba.DeepCopy()
+	if labeled.Labels == nil {
+		labeled.Labels = map[string]string{}
+	}
+	labeled.Labels[bucketAccessAccountLabel] = accountName
+	_, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, labeled, metaV1.UpdateOptions{})
+	if err != nil {
+		log.AddContext(ctx).Warningf("label account of bucketAccess [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Name, err)
+	}
+}
+
+// getBucketOfBucketAccess finds the Bucket which the BucketAccess refers to through its BucketClaim,
+// the errors of getting the BucketClaim and the Bucket are wrapped, so that the not found ones can be told
+func (s *provisionerServer) getBucketOfBucketAccess(ctx context.Context,
+	ba *v1alpha1.BucketAccess) (*v1alpha1.Bucket, error) {
+	claim, err := s.BucketClient.ObjectstorageV1alpha1().BucketClaims(ba.Namespace).
+		Get(ctx, ba.Spec.BucketClaimName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get bucketClaim [%s/%s] failed, error is [%w]",
+			ba.Namespace, ba.Spec.BucketClaimName, err)
+	}
+
+	if claim.Status.BucketName == "" {
+		return nil, fmt.Errorf("bucketClaim [%s/%s] is not bound to bucket", ba.Namespace, claim.Name)
+	}
+
+	bucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().
+		Get(ctx, claim.Status.BucketName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get bucket [%s] failed, error is [%w]", claim.Status.BucketName, err)
+	}
+
+	return bucket, nil
+}
+
+// listDriverBuckets lists the Buckets provisioned by the driver, which have the bucket id and are not being deleted
+func (s *provisionerServer) listDriverBuckets(ctx context.Context) ([]v1alpha1.Bucket, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list buckets failed, error is [%v]", err)
+	}
+
+	var buckets []v1alpha1.Bucket
+	for _, bucket := range list.Items {
+		if bucket.Spec.DriverName == s.Provisioner && bucket.Status.BucketID != "" &&
+			bucket.DeletionTimestamp == nil {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,71 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+)
+
+func Test_GetBucketAccessByAccountName_Found(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1"}}
+	other := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba-other", Namespace: "app", UID: "uid-2"}}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(ba, other)}
+
+	// act
+	gotBa, gotErr := s.getBucketAccessByAccountName(ctx, "ba-uid-1")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, "ba-demo", gotBa.Name)
+}
+
+func Test_GetBucketAccessByAccountName_NotFound(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset()}
+
+	// act
+	_, gotErr := s.getBucketAccessByAccountName(ctx, "ba-uid-1")
+
+	// assert
+	assert.ErrorContains(t, gotErr, "bucketAccess of account [ba-uid-1] not found")
+}
+
+func Test_GetBucketAccessByAccountName_Labeled(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1"}}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(ba)}
+
+	// act
+	_, gotErr := s.getBucketAccessByAccountName(ctx, "ba-uid-1")
+	gotLabeled, listErr := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{LabelSelector: bucketAccessAccountLabel + "=ba-uid-1"})
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.NoError(t, listErr)
+	assert.Len(t, gotLabeled.Items, 1)
+	assert.Equal(t, "ba-demo", gotLabeled.Items[0].Name)
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/huawei/cosi-driver/pkg/s3/agent"
 	"github.com/huawei/cosi-driver/pkg/utils"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
 	"github.com/huawei/cosi-driver/pkg/utils/log"
 )
 
@@ -40,11 +41,48 @@ func (s *provisionerServer) DriverCreateBucket(ctx context.Context,
 	if err != nil {
 		msg := fmt.Sprintf("check DriverCreateBucket failed, error is [%v]", err)
 		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	// the bucket name is kept in BucketId, so deleting finds the bucket even if the naming strategy changes
+	bucketName, err := s.resolveBucketName(ctx, req)
+	if err != nil {
+		msg := fmt.Sprintf("resolve bucket name failed, error is [%v]", err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	err = checkBucketName(bucketName, req.GetParameters())
+	if err != nil {
+		msg := fmt.Sprintf("validate bucket name failed, error is [%v]", err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	err = s.checkBucketQuota(ctx, req, bucketName)
+	if err != nil {
+		msg := fmt.Sprintf("check bucket quota failed, error is [%v]", err)
+		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsResourceExhaustedErr(err) {
+			return nil, status.Error(codes.ResourceExhausted, msg)
+		}
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	// the account secret is chosen before creating, so the bucket is created on the backend of it
+	parameters, err := s.placeBucket(ctx, req)
+	if err != nil {
+		msg := fmt.Sprintf("place bucket failed, error is [%v]", err)
+		log.AddContext(ctx).Errorf(msg)
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	bucketName := req.GetName()
-	parameters := req.GetParameters()
 	s3Client, err := newS3Client(ctx, s.K8sClient, parameters)
 	if err != nil {
 		msg := fmt.Sprintf("new s3 client failed, err is [%v]", err)
@@ -52,16 +90,45 @@ func (s *provisionerServer) DriverCreateBucket(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	err = s3Client.CreateBucket(ctx, bucketName, parameters[bucketACL], parameters[bucketLocation])
+	// the account secret is encoded into BucketId
+	accountSecret, err := s.K8sClient.CoreV1().Secrets(parameters[accountSecretNamespace]).
+		Get(ctx, parameters[accountSecretName], metav1.GetOptions{})
+	if err != nil {
+		msg := fmt.Sprintf("failed to get account secret, error is [%v]", err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	err = createOrAdoptBucket(ctx, s3Client, req, bucketName)
 	if err != nil {
 		msg := fmt.Sprintf("create bucket [%s] failed, error is [%v]", bucketName, err)
 		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsAlreadyExistsErr(err) {
+			return nil, status.Error(codes.AlreadyExists, msg)
+		}
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	bucketId := encodeResourceId(accountSecret, bucketName)
+	err = s.cloneBucket(ctx, s3Client, req, bucketId, bucketName)
+	if utilErrors.IsUnavailableErr(err) {
+		log.AddContext(ctx).Infof(err.Error())
+		return nil, status.Error(codes.Unavailable, err.Error())
+	} else if err != nil {
+		msg := fmt.Sprintf("clone bucket [%s] failed, error is [%v]", bucketName, err)
+		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
 		return nil, status.Error(codes.Internal, msg)
 	}
 
 	log.AddContext(ctx).Infof("handle DriverCreateBucket request successfully")
 	return &cosispec.DriverCreateBucketResponse{
-		BucketId: assembleResourceId(parameters[accountSecretNamespace], parameters[accountSecretName], bucketName),
+		BucketId: bucketId,
 	}, nil
 }
 
@@ -97,12 +164,36 @@ func checkDriverCreateBucketRequest(req *cosispec.DriverCreateBucketRequest) err
 	}
 
 	parameters := req.GetParameters()
-	if parameters[accountSecretName] == "" {
+	if multiBackendPlacement(parameters) {
+		err := checkPlacementParameters(parameters)
+		if err != nil {
+			return err
+		}
+	} else if parameters[accountSecretName] == "" {
 		return fmt.Errorf("accountSecretName value is empty")
+	} else if parameters[accountSecretNamespace] == "" {
+		return fmt.Errorf("accountSecretNamespace value is empty")
 	}
 
-	if parameters[accountSecretNamespace] == "" {
-		return fmt.Errorf("accountSecretNamespace value is empty")
+	err := checkAdoptionParameters(parameters)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	err = checkCloneParameters(parameters)
+	if err != nil {
+		return err
+	}
+
+	err = checkCascadeDeleteParameters(parameters)
+	if err != nil {
+		return err
+	}
+
+	err = checkBucketCapacityParameters(parameters)
+	if err != nil {
+		return err
+	}
+
+	return checkBucketNameTemplate(parameters)
 }
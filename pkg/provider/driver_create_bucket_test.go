@@ -34,6 +34,7 @@ import (
 	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
 
 	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
 )
 
 func TestProvisionerServerDriverCreateBucketSuccess(t *testing.T) {
@@ -41,10 +42,14 @@ func TestProvisionerServerDriverCreateBucketSuccess(t *testing.T) {
 	s3Agent := &agent.S3Agent{
 		Client: &s3.S3{},
 	}
-	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
 	bucketName := "bucketName"
 	acSecretName := "fake-secret"
 	acSecretNameSpace := "huawei-cosi"
+	accountSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: acSecretName, Namespace: acSecretNameSpace, UID: "secret-uid"},
+		Data:       map[string][]byte{"endpoint": []byte("https://xxxx.com:8088")},
+	}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(accountSecret)}
 	req := &cosispec.DriverCreateBucketRequest{
 		Name: bucketName,
 		Parameters: map[string]string{
@@ -63,10 +68,7 @@ func TestProvisionerServerDriverCreateBucketSuccess(t *testing.T) {
 		func(ctx context.Context, clientset kubernetes.Interface,
 			parameters map[string]string) (*agent.S3Agent, error) {
 			return s3Agent, nil
-		}).ApplyMethod(reflect.TypeOf(s3Agent), "CreateBucket",
-		func(_ *agent.S3Agent, ctx context.Context, bucketName, acl, location string) error {
-			return nil
-		})
+		}).ApplyFuncReturn(createOrAdoptBucket, nil)
 
 	// act
 	got, gotErr := s.DriverCreateBucket(context.TODO(), req)
@@ -88,7 +90,8 @@ func TestProvisionerServerDriverCreateBucketFailed(t *testing.T) {
 	s3Agent := &agent.S3Agent{
 		Client: &s3.S3{},
 	}
-	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	accountSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "fake-secret", Namespace: "huawei-cosi"}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(accountSecret)}
 	bucketName := "bucketName"
 	req := &cosispec.DriverCreateBucketRequest{
 		Name: bucketName,
@@ -109,10 +112,7 @@ func TestProvisionerServerDriverCreateBucketFailed(t *testing.T) {
 		func(ctx context.Context, clientset kubernetes.Interface,
 			parameters map[string]string) (*agent.S3Agent, error) {
 			return s3Agent, nil
-		}).ApplyMethod(reflect.TypeOf(s3Agent), "CreateBucket",
-		func(_ *agent.S3Agent, ctx context.Context, bucketName, acl, location string) error {
-			return errCodeBucketAlreadyExistsErr
-		})
+		}).ApplyFuncReturn(createOrAdoptBucket, errCodeBucketAlreadyExistsErr)
 
 	// act
 	_, gotErr := s.DriverCreateBucket(context.TODO(), req)
@@ -202,6 +202,58 @@ func TestProvisionerServerDriverCreateBucketEmptyBucketName(t *testing.T) {
 	assert.Contains(t, err.Error(), wantErr)
 }
 
+func TestProvisionerServerDriverCreateBucketInvalidBucketName(t *testing.T) {
+	// arrange
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	req := &cosispec.DriverCreateBucketRequest{
+		Name: "Bucket_Name",
+		Parameters: map[string]string{
+			"accountSecretName":      "fake-secret",
+			"accountSecretNamespace": "huawei-cosi",
+		},
+	}
+	wantErr := "bucket name [Bucket_Name] can consist only of lowercase letters, numbers, dots and hyphens"
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(strictBucketNames, true)
+
+	// act
+	_, err := s.DriverCreateBucket(context.TODO(), req)
+
+	// assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, err.Error(), wantErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestProvisionerServerDriverCreateBucketInvalidBucketNameTemplate(t *testing.T) {
+	// arrange
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	req := &cosispec.DriverCreateBucketRequest{
+		Name: "bucket-name",
+		Parameters: map[string]string{
+			"accountSecretName":      "fake-secret",
+			"accountSecretNamespace": "huawei-cosi",
+			"bucketNameTemplate":     "{prefix}-{bucketClass}",
+		},
+	}
+
+	// act
+	_, err := s.DriverCreateBucket(context.TODO(), req)
+
+	// assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, err.Error(), "invalid bucketNameTemplate [{prefix}-{bucketClass}]")
+}
+
 func TestProvisionerServerDriverCreateBucketNilParameters(t *testing.T) {
 	// arrange
 	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
@@ -357,3 +409,40 @@ func TestProvisionerServerDriverCreateBucketNewS3AgentError(t *testing.T) {
 	assert.Equal(t, codes.Internal, st.Code())
 	assert.Contains(t, err.Error(), wantErr)
 }
+
+func TestProvisionerServerDriverCreateBucketCloneInProgress(t *testing.T) {
+	// arrange
+	accountSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "fake-secret", Namespace: "huawei-cosi"}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(accountSecret)}
+	req := &cosispec.DriverCreateBucketRequest{
+		Name: "bucketName",
+		Parameters: map[string]string{
+			"accountSecretName":      "fake-secret",
+			"accountSecretNamespace": "huawei-cosi",
+			"cloneSourceBucket":      "golden-data",
+		},
+	}
+
+	// mock
+	mocks := gomonkey.ApplyFuncReturn(newS3Client, &agent.S3Agent{}, nil).
+		ApplyFuncReturn(createOrAdoptBucket, nil).
+		ApplyPrivateMethod(s, "cloneBucket",
+			func(_ *provisionerServer, _ context.Context, _ *agent.S3Agent, _ *cosispec.DriverCreateBucketRequest,
+				_, _ string) error {
+				return utilErrors.NewUnavailableErr("cloning is in progress")
+			})
+
+	// act
+	_, gotErr := s.DriverCreateBucket(context.TODO(), req)
+
+	// assert
+	if status.Code(gotErr) != codes.Unavailable {
+		t.Errorf("TestProvisionerServerDriverCreateBucketCloneInProgress failed, gotErr= [%v], "+
+			"want code= [%v]", gotErr, codes.Unavailable)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mocks.Reset()
+	})
+}
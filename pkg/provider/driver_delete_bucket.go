@@ -21,6 +21,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
 
 	"github.com/huawei/cosi-driver/pkg/s3/agent"
@@ -54,6 +55,14 @@ func (s *provisionerServer) DriverDeleteBucket(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
+	err = s.cascadeDeleteBucketAccess(ctx, req.GetBucketId(), bcAccountSecret, s3Agent,
+		bucketIdData.resourceName)
+	if err != nil {
+		msg := fmt.Sprintf("cascade cleanup of bucket [%s] access failed, err is [%v]", bucketIdData.resourceName, err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.Internal, msg)
+	}
+
 	err = s3Agent.DeleteBucket(ctx, bucketIdData.resourceName)
 	if err != nil {
 		msg := fmt.Sprintf("failed to delete bucket [%s], err is [%v]", bucketIdData.resourceName, err)
@@ -61,6 +70,44 @@ func (s *provisionerServer) DriverDeleteBucket(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
+	s.forgetDeletedBucket(ctx, req.GetBucketId())
 	log.AddContext(ctx).Infof("handle DriverDeleteBucket request successfully")
 	return &cosispec.DriverDeleteBucketResponse{}, nil
 }
+
+// forgetDeletedBucket drops the deleted bucket from the checked buckets, so they do not grow with all the buckets
+// ever provisioned. The quota checked Buckets are keyed by name, the ones of the deleted bucket and the ones not
+// existing any more are dropped, the failure is only logged since they are dropped at the next deletion.
+func (s *provisionerServer) forgetDeletedBucket(ctx context.Context, bucketId string) {
+	s.adoptionCheckedBuckets.Delete(bucketId)
+
+	var cached bool
+	s.quotaCheckedBuckets.Range(func(_, _ any) bool {
+		cached = true
+		return false
+	})
+	if !cached {
+		return
+	}
+
+	list, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		log.AddContext(ctx).Warningf("list buckets failed, the quota checked buckets are not pruned, "+
+			"error is [%v]", err)
+		return
+	}
+
+	existing := make(map[string]bool)
+	for _, bucket := range list.Items {
+		if bucket.Status.BucketID != bucketId {
+			existing[bucket.Name] = true
+		}
+	}
+
+	s.quotaCheckedBuckets.Range(func(name, _ any) bool {
+		if !existing[name.(string)] {
+			s.quotaCheckedBuckets.Delete(name)
+		}
+		return true
+	})
+}
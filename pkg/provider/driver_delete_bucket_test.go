@@ -24,11 +24,13 @@ import (
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fakeK8sClient "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
 	fakeBucketClient "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
 	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
 
@@ -198,3 +200,34 @@ func Test_provisionerServer_DriverDeleteBucket_Failed(t *testing.T) {
 		mocks.Reset()
 	})
 }
+
+func Test_provisionerServer_ForgetDeletedBucket(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	deleting := &v1alpha1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "bucket-deleting"},
+		Status: v1alpha1.BucketStatus{BucketID: "huawei-cosi/fake-secret/bucket-deleting"}}
+	live := &v1alpha1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "bucket-live"},
+		Status: v1alpha1.BucketStatus{BucketID: "huawei-cosi/fake-secret/bucket-live"}}
+	s := &provisionerServer{BucketClient: fakeBucketClient.NewSimpleClientset(deleting, live)}
+	s.adoptionCheckedBuckets.Store("huawei-cosi/fake-secret/bucket-deleting", struct{}{})
+	s.adoptionCheckedBuckets.Store("huawei-cosi/fake-secret/bucket-live", struct{}{})
+	s.quotaCheckedBuckets.Store("bucket-deleting", struct{}{})
+	s.quotaCheckedBuckets.Store("bucket-gone", struct{}{})
+	s.quotaCheckedBuckets.Store("bucket-live", struct{}{})
+
+	// act
+	s.forgetDeletedBucket(ctx, "huawei-cosi/fake-secret/bucket-deleting")
+
+	// assert
+	var gotAdoption, gotQuota []string
+	s.adoptionCheckedBuckets.Range(func(key, _ any) bool {
+		gotAdoption = append(gotAdoption, key.(string))
+		return true
+	})
+	s.quotaCheckedBuckets.Range(func(key, _ any) bool {
+		gotQuota = append(gotQuota, key.(string))
+		return true
+	})
+	assert.Equal(t, []string{"huawei-cosi/fake-secret/bucket-live"}, gotAdoption)
+	assert.Equal(t, []string{"bucket-live"}, gotQuota)
+}
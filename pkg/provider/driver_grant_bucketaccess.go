@@ -17,12 +17,14 @@ package provider
 
 import (
 	"context"
+	"flag"
 	"fmt"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
 	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
 
 	"github.com/huawei/cosi-driver/pkg/s3/agent"
@@ -30,9 +32,20 @@ import (
 	"github.com/huawei/cosi-driver/pkg/s3/policy"
 	"github.com/huawei/cosi-driver/pkg/user/api"
 	"github.com/huawei/cosi-driver/pkg/utils"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
 	"github.com/huawei/cosi-driver/pkg/utils/log"
 )
 
+var (
+	bucketPolicySizeLimit = flag.Int("bucket-policy-size-limit", policy.MaxPolicySize,
+		"the max size in bytes of bucket policy accepted by the storage backend")
+)
+
+const (
+	// warn when the remaining headroom of bucket policy size is less than 1/policySizeWarningRatio of the limit
+	policySizeWarningRatio = 10
+)
+
 // DriverGrantBucketAccess is used to grants access to an account
 func (s *provisionerServer) DriverGrantBucketAccess(ctx context.Context,
 	req *cosispec.DriverGrantBucketAccessRequest) (*cosispec.DriverGrantBucketAccessResponse, error) {
@@ -65,6 +78,16 @@ func (s *provisionerServer) DriverGrantBucketAccess(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
+	err = s.checkStaticBucketAdoption(ctx, req.GetBucketId(), bcAccountSecret, bucketIdData.resourceName)
+	if err != nil {
+		msg := fmt.Sprintf("check bucket adoption failed, error is [%v]", err)
+		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
+		return nil, status.Error(codes.Internal, msg)
+	}
+
 	bacAccountSecret, err := s.K8sClient.CoreV1().Secrets(req.Parameters[accountSecretNamespace]).
 		Get(ctx, req.Parameters[accountSecretName], metaV1.GetOptions{})
 	if err != nil {
@@ -73,23 +96,51 @@ func (s *provisionerServer) DriverGrantBucketAccess(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	userData, err := registerUser(ctx, req, bacAccountSecret)
+	ba, err := s.getBucketAccessByAccountName(ctx, req.GetName())
+	if err != nil {
+		msg := fmt.Sprintf("get bucketAccess of account [%s] failed, error is [%v]", req.GetName(), err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	// the backend user name is kept in AccountId, so revoking finds the user even if the naming strategy changes
+	userName := backendUserName(req.GetName(), bacAccountSecret)
+	userData, err := registerUser(ctx, userName, bacAccountSecret, s.userOwnership(ctx, req.GetName()),
+		newAccessKeyStatus(ba))
 	if err != nil {
 		msg := fmt.Sprintf("register user failed, error is [%v]", err)
 		log.AddContext(ctx).Errorf(msg)
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	err = setBucketPolicy(ctx, req, bcAccountSecret, userData, bucketIdData.resourceName)
+	err = joinGroups(ctx, bacAccountSecret, userName, parseGroups(req.Parameters))
 	if err != nil {
-		msg := fmt.Sprintf("set bucket policy about user failed, error is [%v]", err)
+		discardAccessKey(ctx, bacAccountSecret, userData)
+		msg := fmt.Sprintf("join user [%s] to groups failed, error is [%v]", userName, err)
 		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	err = s.grantAccess(ctx, req, bcAccountSecret, bacAccountSecret, bucketIdData.resourceName, userData)
+	if err != nil {
+		discardAccessKey(ctx, bacAccountSecret, userData)
+		msg := fmt.Sprintf("grant bucket access to user [%s] failed, error is [%v]", userName, err)
+		log.AddContext(ctx).Errorf(msg)
+		if utilErrors.IsResourceExhaustedErr(err) {
+			return nil, status.Error(codes.ResourceExhausted, msg)
+		}
+		if utilErrors.IsInvalidArgumentErr(err) {
+			return nil, status.Error(codes.InvalidArgument, msg)
+		}
 		return nil, status.Error(codes.Internal, msg)
 	}
 
 	log.AddContext(ctx).Infof("handle DriverGrantBucketAccess request successfully")
 	return &cosispec.DriverGrantBucketAccessResponse{
-		AccountId:   assembleResourceId(bacAccountSecret.Namespace, bacAccountSecret.Name, req.GetName()),
+		AccountId:   encodeResourceId(bacAccountSecret, userName),
 		Credentials: buildCredentials(bcAccountSecret, userData),
 	}, nil
 }
@@ -122,6 +173,42 @@ func checkDriverGrantBucketAccessRequest(req *cosispec.DriverGrantBucketAccessRe
 		return fmt.Errorf("account secret namespace value is empty")
 	}
 
+	err := checkBucketPolicyTemplateParameters(req.Parameters)
+	if err != nil {
+		return err
+	}
+
+	mode, exist := req.Parameters[grantMode]
+	if exist && mode != grantModeBucketPolicy && mode != grantModeUserPolicy && mode != grantModeGroup &&
+		mode != grantModeACL {
+		return fmt.Errorf("invalid grant mode [%s]", mode)
+	}
+
+	err = checkPublicReadParameters(req.Parameters)
+	if err != nil {
+		return err
+	}
+
+	err = checkAccessDurationParameter(req.Parameters)
+	if err != nil {
+		return err
+	}
+
+	err = checkKeyRotationParameters(req.Parameters)
+	if err != nil {
+		return err
+	}
+
+	_, inline := req.Parameters[bucketPolicyTemplate]
+	_, fromConfigMap := req.Parameters[bucketPolicyTemplateConfigMapName]
+	if mode == grantModeACL && (inline || fromConfigMap) {
+		return fmt.Errorf("bucket policy template can not be used when grant mode is [%s]", grantModeACL)
+	}
+
+	if mode == grantModeGroup && len(parseGroups(req.Parameters)) == 0 {
+		return fmt.Errorf("%s can not be empty when grant mode is [%s]", groups, grantModeGroup)
+	}
+
 	// BucketPolicyModel is optional
 	policModel, exist := req.Parameters[bucketPolicyModel]
 	if !exist {
@@ -135,23 +222,96 @@ func checkDriverGrantBucketAccessRequest(req *cosispec.DriverGrantBucketAccessRe
 	return nil
 }
 
+func checkBucketPolicyTemplateParameters(parameters map[string]string) error {
+	_, inline := parameters[bucketPolicyTemplate]
+	cmName, fromConfigMap := parameters[bucketPolicyTemplateConfigMapName]
+	if !inline && !fromConfigMap {
+		return nil
+	}
+
+	if inline && fromConfigMap {
+		return fmt.Errorf("%s and %s can not be set at the same time",
+			bucketPolicyTemplate, bucketPolicyTemplateConfigMapName)
+	}
+
+	if _, exist := parameters[bucketPolicyModel]; exist {
+		return fmt.Errorf("%s can not be set together with bucket policy template", bucketPolicyModel)
+	}
+
+	if fromConfigMap && (cmName == "" || parameters[bucketPolicyTemplateConfigMapNamespace] == "") {
+		return fmt.Errorf("bucket policy template configMap name or namespace value is empty")
+	}
+
+	return nil
+}
+
+// grantAccess attaches the access profile to the bucket policy, the bucket acl or the user according to the grant mode
+func (s *provisionerServer) grantAccess(ctx context.Context, req *cosispec.DriverGrantBucketAccessRequest,
+	bcAccountSecret, bacAccountSecret *coreV1.Secret, bucketName string, userData *userInfo) error {
+	mode := req.Parameters[grantMode]
+	if mode == grantModeGroup {
+		log.AddContext(ctx).Infof("access of user [%s] is granted by its groups", userData.userName)
+		return nil
+	}
+
+	if mode == grantModeACL {
+		return setBucketAcl(ctx, bcAccountSecret, bucketName, userData.userName, userData.userId,
+			bucketAclPermissions(req.Parameters))
+	}
+
+	statement, err := s.buildBucketPolicyStatement(ctx, req, userData, bucketName, nil)
+	if err != nil {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("build bucket policy statement failed, "+
+			"error is [%v]", err))
+	}
+
+	if mode == grantModeUserPolicy {
+		return putUserPolicy(ctx, bacAccountSecret, bucketName, userData.userName, userData.userArn, statement)
+	}
+
+	// the parameters are checked before, so the error is ignored
+	public, _ := parsePublicRead(req.Parameters)
+	err = s.setBucketPolicy(ctx, bcAccountSecret, bacAccountSecret, bucketName, statement, public)
+	if !utilErrors.IsResourceExhaustedErr(err) || *bucketPolicyOverflow != bucketPolicyOverflowUserPolicy ||
+		public != nil {
+		return err
+	}
+
+	// public read is only granted by the bucket policy, so it is never split to the user policy
+	log.AddContext(ctx).Warningf("%v, grant user [%s] by user policy instead", err, userData.userName)
+	err = putUserPolicy(ctx, bacAccountSecret, bucketName, userData.userName, userData.userArn, statement)
+	if err != nil {
+		return err
+	}
+
+	return s.markGrantedByOverflow(ctx, req.GetName())
+}
+
+// grantsByBucketPolicy checks whether the access is granted by a bucket policy statement
+func grantsByBucketPolicy(parameters map[string]string) bool {
+	mode := parameters[grantMode]
+	return mode == "" || mode == grantModeBucketPolicy
+}
+
 type userInfo struct {
+	userName        string
 	userArn         string
+	userId          string
 	accessKeyId     string
 	accessSecretKey string
 }
 
-func registerUser(ctx context.Context, req *cosispec.DriverGrantBucketAccessRequest,
-	bacAccountSecret *coreV1.Secret) (*userInfo, error) {
-	userName := req.GetName()
-
+// registerUser creates the user with ownership if it does not exist, and issues a new access key of the user
+// in the status
+func registerUser(ctx context.Context, userName string, bacAccountSecret *coreV1.Secret,
+	ownership *api.UserOwnership, keyStatus api.AccessKeyStatus) (*userInfo, error) {
 	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
 	if err != nil {
 		return nil, fmt.Errorf("build client from secret failed, error is [%v]", err)
 	}
 	defer userClient.Close(ctx)
 
-	var userArn string
+	var userArn, userId string
 	getUserResp, err := userClient.GetUser(ctx, &api.GetUserInput{UserName: userName})
 	if err != nil {
 		return nil, fmt.Errorf("get user failed, error is [%v]", err)
@@ -160,44 +320,96 @@ func registerUser(ctx context.Context, req *cosispec.DriverGrantBucketAccessRequ
 	// If user not exist, then create one.
 	if getUserResp != nil {
 		userArn = getUserResp.Arn
+		userId = getUserResp.UserID
 	} else {
-		createUserResp, err := userClient.CreateUser(ctx, &api.CreateUserInput{UserName: userName})
+		createUserResp, err := userClient.CreateUser(ctx,
+			&api.CreateUserInput{UserName: userName, Ownership: ownership})
 		if err != nil {
 			return nil, fmt.Errorf("create user [%s] failed, error is [%v]", userName, err)
 		}
 
 		userArn = createUserResp.Arn
+		userId = createUserResp.UserID
 	}
 
 	// If user access lost, a new one must be issued.
-	accessResp, err := userClient.CreateUserAccess(ctx, &api.CreateUserAccessInput{UserName: userName})
+	accessResp, err := createAccessKey(ctx, userClient, userName, keyStatus)
 	if err != nil {
-		return nil, fmt.Errorf("create user [%s] access failed, error is [%v]", userName, err)
+		return nil, err
 	}
 
 	return &userInfo{
+		userName:        userName,
 		userArn:         userArn,
+		userId:          userId,
 		accessKeyId:     accessResp.AccessKeyId,
 		accessSecretKey: accessResp.SecretAccessKey,
 	}, nil
 }
 
-func setBucketPolicy(ctx context.Context, req *cosispec.DriverGrantBucketAccessRequest,
-	bcAccountSecret *coreV1.Secret, userData *userInfo, bucketName string) error {
-	s3Agent, err := agent.NewS3Agent(
-		agent.Config{
-			SecretKey: string(bcAccountSecret.Data[sk]),
-			AccessKey: string(bcAccountSecret.Data[ak]),
-			Endpoint:  string(bcAccountSecret.Data[endpoint]),
-			RootCA:    bcAccountSecret.Data[rootCA],
-		})
+// discardAccessKey deletes the access key issued by the failed grant, so the retried grants do not pile up keys,
+// the failure is only logged since the grant error is returned
+func discardAccessKey(ctx context.Context, bacAccountSecret *coreV1.Secret, userData *userInfo) {
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
 	if err != nil {
-		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+		log.AddContext(ctx).Errorf("build client from secret failed, error is [%v]", err)
+		return
 	}
+	defer userClient.Close(ctx)
 
-	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucketPolicy))
+	_, err = userClient.DeleteUserAccess(ctx,
+		&api.DeleteUserAccessInput{UserName: userData.userName, AccessKeyId: userData.accessKeyId})
 	if err != nil {
-		return fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
+		log.AddContext(ctx).Errorf("discard access key [%s] of user [%s] failed, error is [%v]",
+			userData.accessKeyId, userData.userName, err)
+		return
+	}
+
+	log.AddContext(ctx).Infof("discard access key [%s] of user [%s] issued by the failed grant",
+		userData.accessKeyId, userData.userName)
+}
+
+// buildBucketPolicyStatement builds the statement granting the user access to bucket.
+// The statement is rendered from the template of bucketAccessClass if configured,
+// otherwise it is built according to the bucket policy model.
+// The statement is limited to the access duration if configured.
+// The BucketAccess of the grant is looked up by the account name of request only if it is nil and needed.
+func (s *provisionerServer) buildBucketPolicyStatement(ctx context.Context,
+	req *cosispec.DriverGrantBucketAccessRequest, userData *userInfo, bucketName string,
+	ba *v1alpha1.BucketAccess) (*policy.Statement, error) {
+	statement, err := s.buildBaseStatement(ctx, req, userData, bucketName, ba)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.applyAccessExpiry(ctx, req.GetName(), ba, req.Parameters, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+func (s *provisionerServer) buildBaseStatement(ctx context.Context, req *cosispec.DriverGrantBucketAccessRequest,
+	userData *userInfo, bucketName string, ba *v1alpha1.BucketAccess) (*policy.Statement, error) {
+	userName := userData.userName
+	text, err := s.getBucketPolicyTemplate(ctx, req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if text != "" {
+		ba, err = s.bucketAccessOfGrant(ctx, req.GetName(), ba)
+		if err != nil {
+			return nil, err
+		}
+
+		return policy.RenderStatementTemplate(text, userName, policy.TemplateData{
+			UserArn:   userData.userArn,
+			UserName:  userName,
+			Bucket:    bucketName,
+			Namespace: ba.Namespace,
+		})
 	}
 
 	// Default action is RW model
@@ -207,25 +419,231 @@ func setBucketPolicy(ctx context.Context, req *cosispec.DriverGrantBucketAccessR
 		actions = policy.AllowedReadActions
 	}
 
-	userName := req.GetName()
-	statement := policy.NewStatementBuilder().
+	return policy.NewStatementBuilder().
 		WithSID(userName).
 		WithEffect(policy.EffectAllow).
 		WithPrincipals(userData.userArn).
 		WithActions(actions).
 		WithResources(bucketName).
 		WithSubResources(bucketName).
-		Build()
-	if bp == nil {
-		bp = policy.NewBucketPolicy(*statement)
-	} else {
-		bp = bp.ModifyStatement(*statement)
+		Build(), nil
+}
+
+func (s *provisionerServer) getBucketPolicyTemplate(ctx context.Context, parameters map[string]string) (string, error) {
+	if text, exist := parameters[bucketPolicyTemplate]; exist {
+		return text, nil
+	}
+
+	cmName, exist := parameters[bucketPolicyTemplateConfigMapName]
+	if !exist {
+		return "", nil
+	}
+
+	cmNamespace := parameters[bucketPolicyTemplateConfigMapNamespace]
+	cm, err := s.K8sClient.CoreV1().ConfigMaps(cmNamespace).Get(ctx, cmName, metaV1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get bucket policy template configMap [%s/%s] failed, error is [%v]",
+			cmNamespace, cmName, err)
+	}
+
+	key := parameters[bucketPolicyTemplateConfigMapKey]
+	if key == "" {
+		key = defaultBucketPolicyTemplateKey
+	}
+
+	text, exist := cm.Data[key]
+	if !exist || text == "" {
+		return "", fmt.Errorf("key [%s] of bucket policy template configMap [%s/%s] is empty",
+			key, cmNamespace, cmName)
+	}
+
+	return text, nil
+}
+
+// setBucketPolicy adds the statement to the bucket policy, and adds the prefix of public read if it is not nil
+func (s *provisionerServer) setBucketPolicy(ctx context.Context, bcAccountSecret, bacAccountSecret *coreV1.Secret,
+	bucketName string, statement *policy.Statement, public *publicReadGrant) error {
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucketPolicy))
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	// Principals with the same access profile share one statement, which keeps the policy size small.
+	editedBp := policy.NewBucketPolicy()
+	if bp != nil {
+		editedBp = bp
+	}
+	editedBp = editedBp.ConsolidateStatement(*statement)
+	verifying := []*policy.Statement{statement}
+	if public != nil {
+		editedBp = editedBp.SetPublicRead(bucketName,
+			append(editedBp.PublicReadPrefixes(bucketName), public.prefix))
+		verifying = append(verifying, policy.NewPublicReadStatement(bucketName, public.prefix))
+	}
+
+	// The edited policy is evaluated before putting it, so the policy which does not grant the access,
+	// e.g. denied by other statements, is never written.
+	for _, expected := range verifying {
+		if err = checkStatementGranted(editedBp, expected); err != nil {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("bucket [%s] policy does not grant [%s] after "+
+				"editing, error is [%v]", bucketName, expected.Sid, err))
+		}
+	}
+
+	err = lintBucketPolicy(ctx, bacAccountSecret, bucketName, statement, bp, editedBp)
+	if err != nil {
+		return err
+	}
+
+	err = checkBucketPolicySize(ctx, bucketName, editedBp)
+	if err != nil {
+		return err
+	}
+
+	err = s.backupBucketPolicy(ctx, bcAccountSecret, bucketName,
+		fmt.Sprintf("grant user [%s]", statement.Sid), bp)
+	if err != nil {
+		return fmt.Errorf("backup bucket [%s] policy failed, error is [%v]", bucketName, err)
 	}
 
-	err = s3Agent.PutBucketPolicy(ctx, bucketName, bp, errors.EmptyExceptionalErrCodes)
+	err = s3Agent.PutBucketPolicy(ctx, bucketName, editedBp, errors.EmptyExceptionalErrCodes)
 	if err != nil {
 		return fmt.Errorf("put bucket [%s] policy about user [%s] failed, "+
-			"error is [%v]", bucketName, userName, err)
+			"error is [%v]", bucketName, statement.Sid, err)
+	}
+
+	err = verifyBucketPolicy(ctx, s3Agent, bucketName, verifying...)
+	if err != nil {
+		restoreBucketPolicy(ctx, s3Agent, bucketName, bp)
+		return err
+	}
+
+	return nil
+}
+
+// restoreBucketPolicy puts back the policy before granting when the granted policy fails the verification,
+// the failure is only logged since the verification error is returned
+func restoreBucketPolicy(ctx context.Context, s3Agent *agent.S3Agent, bucketName string, bp *policy.BucketPolicy) {
+	var err error
+	if bp == nil || len(bp.Statement) == 0 {
+		err = s3Agent.DeleteBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucketPolicy))
+	} else {
+		err = s3Agent.PutBucketPolicy(ctx, bucketName, bp, errors.EmptyExceptionalErrCodes)
+	}
+	if err != nil {
+		log.AddContext(ctx).Errorf("restore bucket [%s] policy failed, error is [%v]", bucketName, err)
+		return
+	}
+
+	log.AddContext(ctx).Infof("restore bucket [%s] policy after the verification failed", bucketName)
+}
+
+// verifyBucketPolicy reads back the bucket policy and evaluates it,
+// to make sure that the principals of statements actually have the intended rights.
+func verifyBucketPolicy(ctx context.Context, s3Agent *agent.S3Agent, bucketName string,
+	statements ...*policy.Statement) error {
+	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucketPolicy))
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] policy for verification failed, error is [%v]", bucketName, err)
+	}
+
+	if bp == nil {
+		return fmt.Errorf("bucket [%s] policy not found after putting it", bucketName)
+	}
+
+	if constructs := bp.UnmodeledConstructs(); len(constructs) > 0 {
+		log.AddContext(ctx).Warningf("bucket [%s] policy has the constructs %v which are not evaluated, "+
+			"only the presence of the statements is verified", bucketName, constructs)
+	}
+
+	for _, statement := range statements {
+		err = checkStatementGranted(bp, statement)
+		if err != nil {
+			return fmt.Errorf("verify bucket [%s] policy failed, error is [%v]", bucketName, err)
+		}
+
+		log.AddContext(ctx).Infof("verify bucket [%s] policy about [%s] successfully", bucketName, statement.Sid)
+	}
+
+	return nil
+}
+
+// checkStatementGranted checks whether the bucket policy grants what the statement intends.
+// The result of statement with conditions depends on the request, and the evaluation of the policy with
+// unmodeled constructs may be wrong, so they are checked by finding the statement with the same access profile
+// which contains all its principals.
+func checkStatementGranted(bp *policy.BucketPolicy, statement *policy.Statement) error {
+	principals := policy.NewBucketPolicy(*statement).Principals()
+	if len(statement.Condition) > 0 || len(bp.UnmodeledConstructs()) > 0 {
+		sid := statement.ProfileSid()
+		for _, ps := range bp.Statement {
+			if ps.ProfileSid() != sid {
+				continue
+			}
+
+			granted := policy.NewBucketPolicy(ps).Principals()
+			for _, principal := range principals {
+				if !utils.ContainsElement(granted, principal) {
+					return fmt.Errorf("principal [%s] not found in statement [%s]", principal, ps.Sid)
+				}
+			}
+			return nil
+		}
+
+		return fmt.Errorf("statement with the access profile [%s] not found", sid)
+	}
+
+	want := policy.DecisionAllow
+	if statement.Effect == policy.EffectDeny {
+		want = policy.DecisionExplicitDeny
+	}
+
+	for _, principal := range principals {
+		for _, a := range statement.Action {
+			for _, resource := range statement.Resource {
+				req := policy.Request{Principal: principal, Action: string(a), Resource: resource}
+				if got := bp.Evaluate(req); got != want {
+					return fmt.Errorf("principal [%s] action [%s] resource [%s] is expected to be [%s], "+
+						"but got [%s]", principal, a, resource, want, got)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBucketPolicySize rejects the policy exceeding the size limit, and reports the remaining headroom
+func checkBucketPolicySize(ctx context.Context, bucketName string, bp *policy.BucketPolicy) error {
+	size, err := bp.Size()
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] policy size failed, error is [%v]", bucketName, err)
+	}
+
+	limit := *bucketPolicySizeLimit
+	headroom := limit - size
+	if headroom < 0 {
+		return utilErrors.NewResourceExhaustedErr(fmt.Sprintf("bucket [%s] policy size [%d] bytes "+
+			"exceeds the limit [%d] bytes", bucketName, size, limit))
+	}
+
+	if headroom < limit/policySizeWarningRatio {
+		log.AddContext(ctx).Warningf("bucket [%s] policy size is [%d] bytes, only [%d] bytes headroom left "+
+			"of the limit [%d] bytes", bucketName, size, headroom, limit)
+	} else {
+		log.AddContext(ctx).Infof("bucket [%s] policy size is [%d] bytes, [%d] bytes headroom left "+
+			"of the limit [%d] bytes", bucketName, size, headroom, limit)
 	}
 
 	return nil
@@ -17,7 +17,9 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -25,12 +27,16 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
 	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
 
 	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
 	"github.com/huawei/cosi-driver/pkg/user"
 	"github.com/huawei/cosi-driver/pkg/user/api"
 	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
 	"github.com/huawei/cosi-driver/pkg/utils/keylock"
 )
 
@@ -58,8 +64,24 @@ func Test_ProvisionerServer_DriverGrantBucketAccess_Success(t *testing.T) {
 	patches := gomonkey.ApplyFuncReturn(checkDriverGrantBucketAccessRequest, nil)
 	patches.ApplyFuncReturn(fetchDataFromResourceId, bcResource, bcSecret, nil)
 	patches.ApplyFuncReturn(checkBucketExistence, nil)
+	patches.ApplyPrivateMethod(s, "checkStaticBucketAdoption",
+		func(_ *provisionerServer, _ context.Context, _ string, _ *coreV1.Secret, _ string) error {
+			return nil
+		})
+	patches.ApplyPrivateMethod(s, "getBucketAccessByAccountName",
+		func(_ *provisionerServer, _ context.Context, _ string) (*v1alpha1.BucketAccess, error) {
+			return &v1alpha1.BucketAccess{}, nil
+		})
+	patches.ApplyPrivateMethod(s, "userOwnership",
+		func(_ *provisionerServer, _ context.Context, _ string) *api.UserOwnership {
+			return &api.UserOwnership{}
+		})
 	patches.ApplyFuncReturn(registerUser, userData, nil)
-	patches.ApplyFuncReturn(setBucketPolicy, nil)
+	patches.ApplyPrivateMethod(s, "setBucketPolicy",
+		func(_ *provisionerServer, _ context.Context, _, _ *coreV1.Secret, _ string, _ *policy.Statement,
+			_ *publicReadGrant) error {
+			return nil
+		})
 
 	// act
 	gotResponse, gotErr := s.DriverGrantBucketAccess(ctx, req)
@@ -89,24 +111,60 @@ func Test_RegisterUser_NewUser_Success(t *testing.T) {
 	userId := "user-id"
 	userAk := "ak-id"
 	userSk := "sk-id"
-	req := &cosispec.DriverGrantBucketAccessRequest{Name: userName}
 	c := &poe.Client{}
 	createUserResp := &api.CreateUserOutput{UserName: userName, UserID: userId, Arn: userArn}
+	ownership := &api.UserOwnership{ClusterID: "default", DriverName: "cosi.huawei.com", BucketAccess: "app/ba-demo"}
+	var gotOwnership *api.UserOwnership
 	createUserAccessResp := &api.CreateUserAccessOutput{AccessKeyId: userAk, SecretAccessKey: userSk}
-	wantUserData := &userInfo{userArn: userArn, accessKeyId: userAk, accessSecretKey: userSk}
+	wantUserData := &userInfo{userName: userName, userArn: userArn, userId: userId, accessKeyId: userAk,
+		accessSecretKey: userSk}
 
 	// mock
 	mock := gomonkey.ApplyFuncReturn(user.NewUserClient, c, nil)
 	mock.ApplyMethodReturn(c, "GetUser", nil, nil)
-	mock.ApplyMethodReturn(c, "CreateUser", createUserResp, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "CreateUser",
+		func(_ *poe.Client, _ context.Context, input *api.CreateUserInput) (*api.CreateUserOutput, error) {
+			gotOwnership = input.Ownership
+			return createUserResp, nil
+		})
 	mock.ApplyMethodReturn(c, "CreateUserAccess", createUserAccessResp, nil)
 
 	// act
-	gotUserData, gotErr := registerUser(ctx, req, accountSecret)
+	gotUserData, gotErr := registerUser(ctx, userName, accountSecret, ownership, api.AccessKeyStatusActive)
 
 	// assert
 	assert.NoError(t, gotErr)
 	assert.Equal(t, wantUserData, gotUserData)
+	assert.Equal(t, ownership, gotOwnership)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_DiscardAccessKey_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	userData := &userInfo{userName: "user-demo", accessKeyId: "ak-id"}
+	var gotInput *api.DeleteUserAccessInput
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+		func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput,
+			error) {
+			gotInput = input
+			return &api.DeleteUserAccessOutput{}, nil
+		})
+
+	// act
+	discardAccessKey(ctx, &coreV1.Secret{}, userData)
+
+	// assert
+	assert.Equal(t, &api.DeleteUserAccessInput{UserName: "user-demo", AccessKeyId: "ak-id"}, gotInput)
 
 	// cleanup
 	t.Cleanup(func() {
@@ -118,23 +176,31 @@ func Test_SetBucketPolicy_NewPolicy_Success(t *testing.T) {
 	// arrange
 	userName := "user-demo"
 	userArn := "arn-id"
-	userAk := "ak-id"
-	userSk := "sk-id"
 	bucketName := "bucket-demo"
 
 	ctx := context.TODO()
 	c := &agent.S3Agent{}
 	accountSecret := &coreV1.Secret{}
-	req := &cosispec.DriverGrantBucketAccessRequest{Name: userName}
-	userData := &userInfo{userArn: userArn, accessKeyId: userAk, accessSecretKey: userSk}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	statement := policy.NewStatementBuilder().WithSID(userName).WithEffect(policy.EffectAllow).
+		WithPrincipals(userArn).WithActions(policy.AllowedReadActions).WithResources(bucketName).Build()
+
+	var stored *policy.BucketPolicy
 
 	// mock
 	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
-	mock.ApplyMethodReturn(c, "GetBucketPolicy", nil, nil)
-	mock.ApplyMethodReturn(c, "PutBucketPolicy", nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "GetBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ []string) (*policy.BucketPolicy, error) {
+			return stored, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, bp *policy.BucketPolicy, _ []string) error {
+			stored = bp
+			return nil
+		})
 
 	// act
-	gotErr := setBucketPolicy(ctx, req, accountSecret, userData, bucketName)
+	gotErr := s.setBucketPolicy(ctx, accountSecret, accountSecret, bucketName, statement, nil)
 
 	// assert
 	assert.NoError(t, gotErr)
@@ -145,6 +211,220 @@ func Test_SetBucketPolicy_NewPolicy_Success(t *testing.T) {
 	})
 }
 
+func Test_BuildBucketPolicyStatement_DefaultModel(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	req := &cosispec.DriverGrantBucketAccessRequest{
+		Name:       "user-demo",
+		Parameters: map[string]string{bucketPolicyModel: bucketPolicyModelRO},
+	}
+	userData := &userInfo{userName: "user-demo", userArn: "arn-id"}
+	wantStatement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").WithSubResources("bucket-demo").Build()
+
+	// act
+	gotStatement, gotErr := s.buildBucketPolicyStatement(ctx, req, userData, "bucket-demo", nil)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, wantStatement, gotStatement)
+}
+
+func Test_BuildBucketPolicyStatement_ConfigMapTemplate(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba", Namespace: "app", UID: "uid"}}
+	cm := &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: "template", Namespace: "huawei-cosi"},
+		Data: map[string]string{defaultBucketPolicyTemplateKey: `{"Effect": "Allow",
+"Principal": {"AWS": ["{{.UserArn}}"]}, "Action": ["s3:GetObject"],
+"Resource": ["arn:aws:s3:::{{.Bucket}}/{{.Namespace}}/*"]}`},
+	}
+	s := &provisionerServer{
+		K8sClient:    fake.NewSimpleClientset(cm),
+		BucketClient: cosifake.NewSimpleClientset(ba),
+	}
+	req := &cosispec.DriverGrantBucketAccessRequest{
+		Name: "ba-uid",
+		Parameters: map[string]string{
+			bucketPolicyTemplateConfigMapName:      "template",
+			bucketPolicyTemplateConfigMapNamespace: "huawei-cosi",
+		},
+	}
+	userData := &userInfo{userName: "ba-uid", userArn: "arn-id"}
+
+	// act
+	gotStatement, gotErr := s.buildBucketPolicyStatement(ctx, req, userData, "bucket-demo", nil)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, "ba-uid", gotStatement.Sid)
+	assert.Equal(t, []string{"arn:aws:s3:::bucket-demo/app/*"}, gotStatement.Resource)
+}
+
+func Test_VerifyBucketPolicy_DeniedByOtherStatement(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	deny := policy.NewStatementBuilder().WithSID("deny-all").WithEffect(policy.EffectDeny).
+		WithPrincipals(policy.AnonymousPrincipal).WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	mockBp := policy.NewBucketPolicy(*statement, *deny)
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(c, "GetBucketPolicy", mockBp, nil)
+
+	// act
+	gotErr := verifyBucketPolicy(ctx, c, "bucket-demo", statement)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "but got [ExplicitDeny]")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_VerifyBucketPolicy_UnmodeledConstructs(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	other := policy.NewStatementBuilder().WithSID("user-other").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-other").WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	deny := policy.NewStatementBuilder().WithSID("deny-others").WithEffect(policy.EffectDeny).
+		WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	deny.Principal = nil
+	deny.Unknown = map[string]json.RawMessage{"NotPrincipal": json.RawMessage(`{"AWS":["arn-id"]}`)}
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(c, "GetBucketPolicy", policy.NewBucketPolicy(*statement, *deny), nil)
+
+	// act
+	gotErr := verifyBucketPolicy(ctx, c, "bucket-demo", statement)
+	gotMissingErr := verifyBucketPolicy(ctx, c, "bucket-demo", other)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.ErrorContains(t, gotMissingErr, "not found")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_SetBucketPolicy_DeniedByOtherStatement_NotPut(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	deny := policy.NewStatementBuilder().WithSID("deny-all").WithEffect(policy.EffectDeny).
+		WithPrincipals(policy.AnonymousPrincipal).WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").Build()
+	var put bool
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", policy.NewBucketPolicy(*deny), nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ *policy.BucketPolicy, _ []string) error {
+			put = true
+			return nil
+		})
+
+	// act
+	gotErr := s.setBucketPolicy(ctx, &coreV1.Secret{}, &coreV1.Secret{}, "bucket-demo", statement, nil)
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+	assert.False(t, put)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_SetBucketPolicy_VerifyFailed_Restored(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	var deleted bool
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", (*policy.BucketPolicy)(nil), nil)
+	mock.ApplyMethodReturn(c, "PutBucketPolicy", nil)
+	mock.ApplyFuncReturn(verifyBucketPolicy, fmt.Errorf("verify failed"))
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ []string) error {
+			deleted = true
+			return nil
+		})
+
+	// act
+	gotErr := s.setBucketPolicy(ctx, &coreV1.Secret{}, &coreV1.Secret{}, "bucket-demo", statement, nil)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "verify failed")
+	assert.True(t, deleted)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CheckBucketPolicySize_ExceedLimit(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals("arn-id").WithActions(policy.AllowedReadWriteActions).WithResources("bucket-demo").Build()
+	bp := policy.NewBucketPolicy(*statement)
+	size, _ := bp.Size()
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketPolicySizeLimit, size-1)
+
+	// act
+	gotErr := checkBucketPolicySize(ctx, "bucket-demo", bp)
+
+	// assert
+	assert.True(t, utilErrors.IsResourceExhaustedErr(gotErr))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_CheckBucketPolicyTemplateParameters_BothInlineAndConfigMap(t *testing.T) {
+	// arrange
+	parameters := map[string]string{
+		bucketPolicyTemplate:                   "{}",
+		bucketPolicyTemplateConfigMapName:      "template",
+		bucketPolicyTemplateConfigMapNamespace: "huawei-cosi",
+	}
+
+	// act
+	gotErr := checkBucketPolicyTemplateParameters(parameters)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "can not be set at the same time")
+}
+
 func Test_CheckDriverGrantBucketAccessRequest_EmptyBucketId(t *testing.T) {
 	// arrange
 	req := &cosispec.DriverGrantBucketAccessRequest{}
@@ -359,3 +639,47 @@ func Test_CheckBucketExistence_NewAgent_Failed(t *testing.T) {
 		mock.Reset()
 	})
 }
+
+func Test_CheckDriverGrantBucketAccessRequest_InvalidGrantMode(t *testing.T) {
+	// arrange
+	req := &cosispec.DriverGrantBucketAccessRequest{}
+	req.BucketId = "bucketId"
+	req.Name = "userName"
+	req.AuthenticationType = cosispec.AuthenticationType_Key
+	req.Parameters = map[string]string{
+		accountSecretName:      "accountSecret",
+		accountSecretNamespace: "accountSecretNamespace",
+		grantMode:              "invalidMode",
+	}
+
+	wantErr := fmt.Errorf("invalid grant mode [invalidMode]")
+
+	// act
+	gotErr := checkDriverGrantBucketAccessRequest(req)
+
+	// assert
+	assert.Error(t, gotErr)
+	assert.Equal(t, wantErr.Error(), gotErr.Error())
+}
+
+func Test_CheckDriverGrantBucketAccessRequest_GroupModeWithoutGroups(t *testing.T) {
+	// arrange
+	req := &cosispec.DriverGrantBucketAccessRequest{}
+	req.BucketId = "bucketId"
+	req.Name = "userName"
+	req.AuthenticationType = cosispec.AuthenticationType_Key
+	req.Parameters = map[string]string{
+		accountSecretName:      "accountSecret",
+		accountSecretNamespace: "accountSecretNamespace",
+		grantMode:              grantModeGroup,
+	}
+
+	wantErr := fmt.Errorf("groups can not be empty when grant mode is [group]")
+
+	// act
+	gotErr := checkDriverGrantBucketAccessRequest(req)
+
+	// assert
+	assert.Error(t, gotErr)
+	assert.Equal(t, wantErr.Error(), gotErr.Error())
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/huawei/cosi-driver/pkg/s3/agent"
 	"github.com/huawei/cosi-driver/pkg/s3/errors"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
 	"github.com/huawei/cosi-driver/pkg/user/api"
 	"github.com/huawei/cosi-driver/pkg/utils"
 	"github.com/huawei/cosi-driver/pkg/utils/log"
@@ -57,23 +58,23 @@ func (s *provisionerServer) DriverRevokeBucketAccess(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	userName := accountIdData.resourceName
-	err = removeUser(ctx, bacAccountSecret, userName)
+	bucketIdData, bcAccountSecret, err := fetchDataFromResourceId(req.GetBucketId(), s.K8sClient)
 	if err != nil {
-		msg := fmt.Sprintf("remove user [%s] failed, error is [%v]", userName, err)
+		msg := fmt.Sprintf("fetch data from resourceId [%s] failed, error is [%v]", req.GetBucketId(), err)
 		log.AddContext(ctx).Errorf(msg)
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	bucketIdData, bcAccountSecret, err := fetchDataFromResourceId(req.GetBucketId(), s.K8sClient)
+	bucketName := bucketIdData.resourceName
+	userName := accountIdData.resourceName
+	err = removeBucketAclGrantee(ctx, bcAccountSecret, bacAccountSecret, bucketName, userName)
 	if err != nil {
-		msg := fmt.Sprintf("fetch data from resourceId [%s] failed, error is [%v]", req.GetBucketId(), err)
+		msg := fmt.Sprintf("remove bucket acl grantee of user [%s] failed, error is [%v]", userName, err)
 		log.AddContext(ctx).Errorf(msg)
 		return nil, status.Error(codes.Internal, msg)
 	}
 
-	bucketName := bucketIdData.resourceName
-	err = removeBucketPolicyStatement(ctx, bcAccountSecret, bucketName, userName)
+	err = s.removeBucketPolicyStatement(ctx, bcAccountSecret, bucketName, userName)
 	if err != nil {
 		msg := fmt.Sprintf("remove bucket policy statement of user [%s] failed, "+
 			"error is [%v]", userName, err)
@@ -81,6 +82,21 @@ func (s *provisionerServer) DriverRevokeBucketAccess(ctx context.Context,
 		return nil, status.Error(codes.Internal, msg)
 	}
 
+	err = s.revokePublicRead(ctx, req.GetBucketId(), bcAccountSecret, bucketName, userName)
+	if err != nil {
+		msg := fmt.Sprintf("revoke public read of user [%s] failed, error is [%v]", userName, err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.Internal, msg)
+	}
+
+	// The user is removed after all grants, so that an unowned user which is kept does not keep the access.
+	err = removeUser(ctx, bacAccountSecret, userName, s.Provisioner)
+	if err != nil {
+		msg := fmt.Sprintf("remove user [%s] failed, error is [%v]", userName, err)
+		log.AddContext(ctx).Errorf(msg)
+		return nil, status.Error(codes.Internal, msg)
+	}
+
 	log.AddContext(ctx).Infof("handle DriverRevokeBucketAccess request successfully")
 	return &cosispec.DriverRevokeBucketAccessResponse{}, nil
 }
@@ -97,13 +113,51 @@ func checkDriverRevokeBucketAccess(req *cosispec.DriverRevokeBucketAccessRequest
 	return nil
 }
 
-func removeUser(ctx context.Context, bacAccountSecret *coreV1.Secret, userName string) error {
+// removeUser removes the user and everything attached to it, the user not created by the driver of this cluster
+// is kept unless remove-unowned-users is enabled, only the policy granted by the driver is deleted from it.
+// The users created before the ownership is recorded are removed as the owned ones,
+// otherwise the access keys issued to them keep working after upgrading.
+func removeUser(ctx context.Context, bacAccountSecret *coreV1.Secret, userName, driverName string) error {
 	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
 	if err != nil {
 		return fmt.Errorf("build client from secret failed, error is [%v]", err)
 	}
 	defer userClient.Close(ctx)
 
+	getUserResp, err := userClient.GetUser(ctx, &api.GetUserInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("get user [%s] failed, error is [%v]", userName, err)
+	}
+
+	if getUserResp == nil {
+		log.AddContext(ctx).Infof("user [%s] not exist, skip removing it", userName)
+		return nil
+	}
+
+	if legacyUser(getUserResp.Ownership, userName) {
+		log.AddContext(ctx).Infof("user [%s] is created before the ownership is recorded, remove it", userName)
+	} else if !ownsUser(getUserResp.Ownership, driverName) {
+		if !*removeUnownedUsers {
+			log.AddContext(ctx).Warningf("user [%s] is not owned by driver [%s] of cluster [%s], skip removing it "+
+				"as remove-unowned-users is disabled", userName, driverName, *clusterId)
+			return deleteUserPolicy(ctx, userClient, userName)
+		}
+		log.AddContext(ctx).Warningf("user [%s] is not owned by driver [%s] of cluster [%s], "+
+			"remove it as remove-unowned-users is enabled", userName, driverName, *clusterId)
+	}
+
+	// The user can not be deleted until it leaves all groups.
+	err = leaveGroups(ctx, userClient, userName)
+	if err != nil {
+		return err
+	}
+
+	// The inline policy must be deleted before the user, it does not exist if access is granted by bucket policy.
+	err = deleteUserPolicy(ctx, userClient, userName)
+	if err != nil {
+		return err
+	}
+
 	listUserAksResp, err := userClient.ListUserAccessKeys(ctx,
 		&api.ListUserAccessKeysInput{UserName: userName})
 	if err != nil {
@@ -129,7 +183,18 @@ func removeUser(ctx context.Context, bacAccountSecret *coreV1.Secret, userName s
 	return nil
 }
 
-func removeBucketPolicyStatement(ctx context.Context, accountSecret *coreV1.Secret, bucketName, userName string) error {
+// deleteUserPolicy deletes the inline policy granted to the user by the driver
+func deleteUserPolicy(ctx context.Context, userClient api.UserAPI, userName string) error {
+	_, err := userClient.DeleteUserPolicy(ctx, &api.DeleteUserPolicyInput{UserName: userName, PolicyName: userPolicyName})
+	if err != nil {
+		return fmt.Errorf("delete user [%s] policy failed, error is [%v]", userName, err)
+	}
+
+	return nil
+}
+
+func (s *provisionerServer) removeBucketPolicyStatement(ctx context.Context, accountSecret *coreV1.Secret,
+	bucketName, userName string) error {
 	s3Agent, err := agent.NewS3Agent(
 		agent.Config{
 			SecretKey: string(accountSecret.Data[sk]),
@@ -141,8 +206,9 @@ func removeBucketPolicyStatement(ctx context.Context, accountSecret *coreV1.Secr
 		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
 	}
 
-	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName,
-		errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket, errors.ErrNoSuchBucketPolicy))
+	// the backends only supporting acl reject the bucket policy apis, nothing is granted by bucket policy on them
+	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket,
+		errors.ErrNoSuchBucketPolicy, errors.ErrNotImplemented, errors.ErrMethodNotAllowed))
 	if err != nil {
 		return fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
 	}
@@ -152,13 +218,22 @@ func removeBucketPolicyStatement(ctx context.Context, accountSecret *coreV1.Secr
 		return nil
 	}
 
-	editedBp := bp.RemoveStatement(userName)
+	editedBp := bp.RemoveUser(userName)
 	if reflect.DeepEqual(editedBp, bp) {
 		log.AddContext(ctx).Infof("bucket [%s] policy has no statement about user [%s], "+
 			"skip remove policy operation", bucketName, userName)
 		return nil
 	}
 
+	// Revoking only narrows the access, so lint findings are logged but never block it.
+	logLintFindings(ctx, bucketName, editedBp.Lint(policy.LintOptions{BucketName: bucketName,
+		AllowPublicRead: *enablePublicRead}))
+
+	err = s.backupBucketPolicy(ctx, accountSecret, bucketName, fmt.Sprintf("revoke user [%s]", userName), bp)
+	if err != nil {
+		return fmt.Errorf("backup bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
 	// If policy have other statements, then using PutBucketPolicy to update.
 	// If policy do not have any statement, then using DeleteBucketPolicy to delete policy statement,
 	// because put policy with empty statement will fail.
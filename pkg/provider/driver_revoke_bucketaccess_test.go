@@ -18,9 +18,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -55,9 +58,17 @@ func Test_ProvisionerServer_DriverRevokeBucketAccess_Success(t *testing.T) {
 	// mock
 	patches := gomonkey.ApplyFuncReturn(checkDriverRevokeBucketAccess, nil).
 		ApplyFuncReturn(fetchDataFromResourceId, bacResource, bacSecret, nil).
+		ApplyFuncReturn(removeBucketAclGrantee, nil).
 		ApplyFuncReturn(removeUser, nil).
 		ApplyFuncReturn(fetchDataFromResourceId, bcResource, bcSecret, nil).
-		ApplyFuncReturn(removeBucketPolicyStatement, nil)
+		ApplyPrivateMethod(s, "removeBucketPolicyStatement",
+			func(_ *provisionerServer, _ context.Context, _ *coreV1.Secret, _, _ string) error {
+				return nil
+			}).
+		ApplyPrivateMethod(s, "revokePublicRead",
+			func(_ *provisionerServer, _ context.Context, _ string, _ *coreV1.Secret, _, _ string) error {
+				return nil
+			})
 
 	// act
 	gotResponse, gotErr := s.DriverRevokeBucketAccess(ctx, req)
@@ -148,6 +159,15 @@ func Test_ProvisionerServer_DriverRevokeBucketAccess_RemoveUser_Failed(t *testin
 	// mock
 	patches := gomonkey.ApplyFuncReturn(checkDriverRevokeBucketAccess, nil).
 		ApplyFuncReturn(fetchDataFromResourceId, resource, sec, nil).
+		ApplyFuncReturn(removeBucketAclGrantee, nil).
+		ApplyPrivateMethod(s, "removeBucketPolicyStatement",
+			func(_ *provisionerServer, _ context.Context, _ *coreV1.Secret, _, _ string) error {
+				return nil
+			}).
+		ApplyPrivateMethod(s, "revokePublicRead",
+			func(_ *provisionerServer, _ context.Context, _ string, _ *coreV1.Secret, _, _ string) error {
+				return nil
+			}).
 		ApplyFuncReturn(removeUser, removeUserErr)
 
 	// act
@@ -184,9 +204,13 @@ func Test_ProvisionerServer_DriverRevokeBucketAccess_RemoveBucketPolicyStatement
 	// mock
 	patches := gomonkey.ApplyFuncReturn(checkDriverRevokeBucketAccess, nil)
 	patches.ApplyFuncReturn(fetchDataFromResourceId, bacResource, bacSecret, nil).
+		ApplyFuncReturn(removeBucketAclGrantee, nil).
 		ApplyFuncReturn(removeUser, nil).
 		ApplyFuncReturn(fetchDataFromResourceId, bcResource, bcSecret, nil).
-		ApplyFuncReturn(removeBucketPolicyStatement, removeBucketPolicyStatementErr)
+		ApplyPrivateMethod(s, "removeBucketPolicyStatement",
+			func(_ *provisionerServer, _ context.Context, _ *coreV1.Secret, _, _ string) error {
+				return removeBucketPolicyStatementErr
+			})
 
 	// act
 	gotResponse, gotErr := s.DriverRevokeBucketAccess(ctx, req)
@@ -217,12 +241,16 @@ func Test_RemoveUser_Normal_Success(t *testing.T) {
 
 	// mock
 	mock := gomonkey.ApplyFuncReturn(user.NewUserClient, c, nil).
+		ApplyMethodReturn(c, "GetUser", &api.GetUserOutput{UserName: userName,
+			Ownership: &api.UserOwnership{ClusterID: defaultClusterId, DriverName: "cosi.huawei.com"}}, nil).
+		ApplyMethodReturn(c, "ListGroupsForUser", &api.ListGroupsForUserOutput{}, nil).
+		ApplyMethodReturn(c, "DeleteUserPolicy", nil, nil).
 		ApplyMethodReturn(c, "ListUserAccessKeys", listUserAksResp, nil).
 		ApplyMethodReturn(c, "DeleteUserAccess", nil, nil).
 		ApplyMethodReturn(c, "DeleteUser", nil, nil)
 
 	// act
-	gotErr := removeUser(ctx, accountSecret, userName)
+	gotErr := removeUser(ctx, accountSecret, userName, "cosi.huawei.com")
 
 	// assert
 	assert.NoError(t, gotErr)
@@ -233,6 +261,106 @@ func Test_RemoveUser_Normal_Success(t *testing.T) {
 	})
 }
 
+func Test_RemoveUser_NotOwned_Kept(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	accountSecret := &coreV1.Secret{
+		Data: map[string][]byte{
+			ak:       []byte("accessKey123"),
+			sk:       []byte("secretKey123"),
+			endpoint: []byte("https://xxxx.com:8088"),
+		},
+	}
+	userName := "user-demo"
+	c := &poe.Client{}
+	deleted := false
+	policyDeleted := false
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(user.NewUserClient, c, nil).
+		ApplyMethodReturn(c, "GetUser", &api.GetUserOutput{UserName: userName}, nil).
+		ApplyMethod(reflect.TypeOf(c), "DeleteUserPolicy",
+			func(_ *poe.Client, _ context.Context,
+				_ *api.DeleteUserPolicyInput) (*api.DeleteUserPolicyOutput, error) {
+				policyDeleted = true
+				return &api.DeleteUserPolicyOutput{}, nil
+			}).
+		ApplyMethod(reflect.TypeOf(c), "DeleteUser",
+			func(_ *poe.Client, _ context.Context, _ *api.DeleteUserInput) (*api.DeleteUserOutput, error) {
+				deleted = true
+				return &api.DeleteUserOutput{}, nil
+			})
+
+	// act
+	gotErr := removeUser(ctx, accountSecret, userName, "cosi.huawei.com")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.True(t, policyDeleted)
+	assert.False(t, deleted)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RemoveUser_Legacy_Removed(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	accountSecret := &coreV1.Secret{
+		Data: map[string][]byte{
+			ak:       []byte("accessKey123"),
+			sk:       []byte("secretKey123"),
+			endpoint: []byte("https://xxxx.com:8088"),
+		},
+	}
+	userName := "ba-demo"
+	c := &poe.Client{}
+	listUserAksResp := &api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-1"}}
+	var deletedKeys []string
+	leftGroups := false
+	deleted := false
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(user.NewUserClient, c, nil).
+		ApplyMethodReturn(c, "GetUser", &api.GetUserOutput{UserName: userName}, nil).
+		ApplyMethodReturn(c, "ListGroupsForUser", &api.ListGroupsForUserOutput{GroupNames: []string{"readers"}}, nil).
+		ApplyMethod(reflect.TypeOf(c), "RemoveUserFromGroup",
+			func(_ *poe.Client, _ context.Context,
+				_ *api.RemoveUserFromGroupInput) (*api.RemoveUserFromGroupOutput, error) {
+				leftGroups = true
+				return &api.RemoveUserFromGroupOutput{}, nil
+			}).
+		ApplyMethodReturn(c, "DeleteUserPolicy", nil, nil).
+		ApplyMethodReturn(c, "ListUserAccessKeys", listUserAksResp, nil).
+		ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+			func(_ *poe.Client, _ context.Context,
+				input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+				deletedKeys = append(deletedKeys, input.AccessKeyId)
+				return &api.DeleteUserAccessOutput{}, nil
+			}).
+		ApplyMethod(reflect.TypeOf(c), "DeleteUser",
+			func(_ *poe.Client, _ context.Context, _ *api.DeleteUserInput) (*api.DeleteUserOutput, error) {
+				deleted = true
+				return &api.DeleteUserOutput{}, nil
+			})
+
+	// act
+	gotErr := removeUser(ctx, accountSecret, userName, "cosi.huawei.com")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.True(t, leftGroups)
+	assert.Equal(t, []string{"ak-1"}, deletedKeys)
+	assert.True(t, deleted)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
 func Test_RemoveBucketPolicyStatement_Normal_Success(t *testing.T) {
 	// arrange
 	ctx := context.TODO()
@@ -240,6 +368,7 @@ func Test_RemoveBucketPolicyStatement_Normal_Success(t *testing.T) {
 	accountSecret := &coreV1.Secret{}
 	userName := "user-demo"
 	bucketName := "bucket-demo"
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
 
 	statement := policy.Statement{
 		Sid: userName,
@@ -253,7 +382,31 @@ func Test_RemoveBucketPolicyStatement_Normal_Success(t *testing.T) {
 	mock.ApplyMethodReturn(c, "DeleteBucketPolicy", nil)
 
 	// act
-	gotErr := removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
+	gotErr := s.removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RemoveBucketPolicyStatement_NotImplemented(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s3Client := &s3.S3{}
+	c := &agent.S3Agent{Client: s3Client}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil).
+		ApplyMethodReturn(s3Client, "GetBucketPolicy", nil,
+			awserr.New("NotImplemented", "bucket policy is not supported", nil))
+
+	// act
+	gotErr := s.removeBucketPolicyStatement(ctx, &coreV1.Secret{}, "bucket-demo", "user-demo")
 
 	// assert
 	assert.NoError(t, gotErr)
@@ -271,13 +424,14 @@ func Test_RemoveBucketPolicyStatement_PolicyNotExist(t *testing.T) {
 	accountSecret := &coreV1.Secret{}
 	userName := "user-demo"
 	bucketName := "bucket-demo"
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
 
 	// mock
 	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil).
 		ApplyMethodReturn(c, "GetBucketPolicy", nil, nil)
 
 	// act
-	gotErr := removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
+	gotErr := s.removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
 
 	// assert
 	assert.NoError(t, gotErr)
@@ -295,6 +449,7 @@ func Test_RemoveBucketPolicyStatement_StatementNotExist(t *testing.T) {
 	accountSecret := &coreV1.Secret{}
 	userName := "user-demo"
 	bucketName := "bucket-demo"
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
 
 	statementUserName := "user-demo-2"
 	statement := policy.Statement{
@@ -307,10 +462,57 @@ func Test_RemoveBucketPolicyStatement_StatementNotExist(t *testing.T) {
 		ApplyMethodReturn(c, "GetBucketPolicy", mockBp, nil)
 
 	// act
-	gotErr := removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
+	gotErr := s.removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RemoveBucketPolicyStatement_UserArnWithPath(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	accountSecret := &coreV1.Secret{}
+	userName := "ba-uid-1"
+	bucketName := "bucket-demo"
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+
+	pathArnPrefix := "arn:aws:iam::domain-id:user/cosi/default/cosi.huawei.com/ns/name/"
+	statement := func(userName string) policy.Statement {
+		return *policy.NewStatementBuilder().WithEffect(policy.EffectAllow).
+			WithPrincipals(pathArnPrefix + userName).WithActions(policy.AllowedReadActions).
+			WithResources(bucketName).WithSubResources(bucketName).Build()
+	}
+	mockBp := (&policy.BucketPolicy{}).ConsolidateStatement(statement(userName)).
+		ConsolidateStatement(statement("ba-uid-2"))
+	var gotBp *policy.BucketPolicy
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil).
+		ApplyMethodReturn(c, "GetBucketPolicy", mockBp, nil).
+		ApplyPrivateMethod(s, "backupBucketPolicy",
+			func(_ *provisionerServer, _ context.Context, _ *coreV1.Secret, _, _ string,
+				_ *policy.BucketPolicy) error {
+				return nil
+			}).
+		ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+			func(_ *agent.S3Agent, _ context.Context, _ string, bp *policy.BucketPolicy,
+				_ []string) error {
+				gotBp = bp
+				return nil
+			})
+
+	// act
+	gotErr := s.removeBucketPolicyStatement(ctx, accountSecret, bucketName, userName)
 
 	// assert
 	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{pathArnPrefix + "ba-uid-2"}, gotBp.Principals())
 
 	// cleanup
 	t.Cleanup(func() {
@@ -393,8 +595,16 @@ func TestDriverRevokeBucketAccessWithCentralizedClientSuccess(t *testing.T) {
 	// Mock
 	patches := gomonkey.ApplyFuncReturn(checkDriverRevokeBucketAccess, nil).
 		ApplyFuncReturn(fetchDataFromResourceId, resource, accountSecret, nil).
+		ApplyFuncReturn(removeBucketAclGrantee, nil).
 		ApplyFuncReturn(removeUser, nil).
-		ApplyFuncReturn(removeBucketPolicyStatement, nil)
+		ApplyPrivateMethod(s, "removeBucketPolicyStatement",
+			func(_ *provisionerServer, _ context.Context, _ *coreV1.Secret, _, _ string) error {
+				return nil
+			}).
+		ApplyPrivateMethod(s, "revokePublicRead",
+			func(_ *provisionerServer, _ context.Context, _ string, _ *coreV1.Secret, _, _ string) error {
+				return nil
+			})
 	defer patches.Reset()
 
 	// Act
@@ -0,0 +1,396 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cosiapi "sigs.k8s.io/container-object-storage-interface-api/apis"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	keyRotationCheckInterval = flag.Duration("key-rotation-check-interval", defaultKeyRotationCheckInterval,
+		"the interval of checking whether the access keys of BucketAccesses need rotating, 0 means disabled")
+)
+
+const (
+	defaultKeyRotationCheckInterval = time.Minute
+	defaultKeyRotationGracePeriod   = time.Hour
+
+	// the cosi sidecar writes the bucket info json into the credentials secret with this key
+	bucketInfoSecretKey = "BucketInfo"
+
+	// keyRotatedAtAnnotation of BucketAccess records when the last new access key is issued
+	keyRotatedAtAnnotation = "cosi.huawei.com/key-rotated-at"
+
+	// retiringAccessKeysAnnotation of BucketAccess records the comma separated old access keys,
+	// which are deleted after the grace period
+	retiringAccessKeysAnnotation = "cosi.huawei.com/retiring-access-keys"
+
+	// keysRetiredAtAnnotation of BucketAccess records when the old access keys are deleted
+	keysRetiredAtAnnotation = "cosi.huawei.com/keys-retired-at"
+
+	// issuingAccessKeyAnnotation of BucketAccess records when a new access key starts to be issued,
+	// it is removed once the key rotation is recorded, the access keys are never collected in the meantime
+	issuingAccessKeyAnnotation = "cosi.huawei.com/issuing-access-key"
+)
+
+// keyRotation is the access key rotation schedule of bucketAccessClass
+type keyRotation struct {
+	// interval is how long an access key is used before a new one is issued
+	interval time.Duration
+
+	// gracePeriod is how long the old access keys keep working after a new one is issued
+	gracePeriod time.Duration
+}
+
+// parseKeyRotation parses the key rotation of bucketAccessClass parameters, returns nil if it is not configured
+func parseKeyRotation(parameters map[string]string) (*keyRotation, error) {
+	value, exist := parameters[keyRotationInterval]
+	if !exist {
+		return nil, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid %s value [%s], it must be a positive duration likes '720h'",
+			keyRotationInterval, value)
+	}
+
+	rotation := &keyRotation{interval: interval, gracePeriod: defaultKeyRotationGracePeriod}
+	if value, exist = parameters[keyRotationGracePeriod]; exist {
+		rotation.gracePeriod, err = time.ParseDuration(value)
+		if err != nil || rotation.gracePeriod < 0 {
+			return nil, fmt.Errorf("invalid %s value [%s], it must be a non-negative duration likes '1h'",
+				keyRotationGracePeriod, value)
+		}
+	}
+
+	if rotation.gracePeriod >= rotation.interval {
+		return nil, fmt.Errorf("%s [%v] must be less than %s [%v]", keyRotationGracePeriod, rotation.gracePeriod,
+			keyRotationInterval, rotation.interval)
+	}
+
+	return rotation, nil
+}
+
+func checkKeyRotationParameters(parameters map[string]string) error {
+	_, err := parseKeyRotation(parameters)
+	return err
+}
+
+// rotateAccessKeys rotates the access keys of BucketAccesses according to the schedule of their bucketAccessClasses
+func (s *provisionerServer) rotateAccessKeys(ctx context.Context) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		log.AddContext(ctx).Errorf("list bucketAccesses failed, error is [%v]", err)
+		return
+	}
+
+	classes := make(map[string]*v1alpha1.BucketAccessClass)
+	for i := range list.Items {
+		ba := &list.Items[i]
+		if !ba.Status.AccessGranted || ba.Status.AccountID == "" || ba.DeletionTimestamp != nil {
+			continue
+		}
+
+		// the access keys of expired BucketAccess are deleted, they must not be issued again
+		if _, expired := ba.Annotations[accessExpiredAnnotation]; expired {
+			continue
+		}
+
+		// a new access key is active, it must not be issued while the access is suspended
+		if accessSuspended(ba) {
+			continue
+		}
+
+		bac, exist := classes[ba.Spec.BucketAccessClassName]
+		if !exist {
+			bac, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccessClasses().
+				Get(ctx, ba.Spec.BucketAccessClassName, metaV1.GetOptions{})
+			if err != nil {
+				log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], get bucketAccessClass [%s] failed, "+
+					"error is [%v]", ba.Namespace, ba.Name, ba.Spec.BucketAccessClassName, err)
+				continue
+			}
+			classes[ba.Spec.BucketAccessClassName] = bac
+		}
+
+		if bac.DriverName != s.Provisioner {
+			continue
+		}
+
+		rotation, err := parseKeyRotation(bac.Parameters)
+		if err != nil {
+			log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], error is [%v]", ba.Namespace, ba.Name, err)
+			continue
+		}
+
+		if rotation == nil {
+			continue
+		}
+
+		err = s.rotateAccessKey(ctx, ba, rotation)
+		if err != nil {
+			log.AddContext(ctx).Errorf("rotate access key of bucketAccess [%s/%s] failed, error is [%v]",
+				ba.Namespace, ba.Name, err)
+		}
+	}
+}
+
+// rotateAccessKey retires the old access keys after the grace period, or issues a new access key when it is due
+func (s *provisionerServer) rotateAccessKey(ctx context.Context, ba *v1alpha1.BucketAccess,
+	rotation *keyRotation) error {
+	rotatedAt := ba.CreationTimestamp.Time
+	if value, exist := ba.Annotations[keyRotatedAtAnnotation]; exist {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid annotation [%s] value [%s]", keyRotatedAtAnnotation, value)
+		}
+		rotatedAt = parsed
+	}
+
+	if retiring := ba.Annotations[retiringAccessKeysAnnotation]; retiring != "" {
+		if time.Now().Before(rotatedAt.Add(rotation.gracePeriod)) {
+			return nil
+		}
+		return s.retireAccessKeys(ctx, ba, strings.Split(retiring, ","))
+	}
+
+	if time.Now().Before(rotatedAt.Add(rotation.interval)) {
+		return nil
+	}
+
+	return s.issueAccessKey(ctx, ba)
+}
+
+// issueAccessKey creates a new access key for the user of BucketAccess and writes it into the credentials secret,
+// the old access key in the credentials secret is recorded to be retired after the grace period
+func (s *provisionerServer) issueAccessKey(ctx context.Context, ba *v1alpha1.BucketAccess) error {
+	accountIdData, bacAccountSecret, err := fetchDataFromResourceId(ba.Status.AccountID, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", ba.Status.AccountID, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	userName := accountIdData.resourceName
+	listUserAksResp, err := userClient.ListUserAccessKeys(ctx, &api.ListUserAccessKeysInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("list user [%s] access keys failed, error is [%v]", userName, err)
+	}
+
+	// only the access key delivered by the driver is retired, the ones created by others are left untouched
+	deliveredKey, err := s.deliveredAccessKey(ctx, ba)
+	if err != nil {
+		return err
+	}
+	var retiringKeys []string
+	if slices.Contains(listUserAksResp.AccessKeys, deliveredKey) {
+		retiringKeys = append(retiringKeys, deliveredKey)
+	}
+
+	// The issuing is recorded before the access key is created, so that the user gc never deletes
+	// the new access key before it is delivered, or the old ones before they are recorded to be retired.
+	ba, err = s.markIssuingAccessKey(ctx, ba, true)
+	if err != nil {
+		return err
+	}
+
+	// the marked BucketAccess is the latest one, the suspension may be requested after it is listed
+	accessResp, err := createAccessKey(ctx, userClient, userName, newAccessKeyStatus(ba))
+	if err != nil {
+		s.unmarkIssuingAccessKey(ctx, ba)
+		return err
+	}
+
+	err = s.updateCredentialsSecret(ctx, ba, accessResp.AccessKeyId, accessResp.SecretAccessKey)
+	if err != nil {
+		// the new access key is never delivered, delete it so that the old ones are still the only keys
+		_, deleteErr := userClient.DeleteUserAccess(ctx, &api.DeleteUserAccessInput{UserName: userName,
+			AccessKeyId: accessResp.AccessKeyId})
+		if deleteErr != nil {
+			log.AddContext(ctx).Warningf("delete undelivered access key [%s] of user [%s] failed, error is [%v]",
+				accessResp.AccessKeyId, userName, deleteErr)
+		}
+		s.unmarkIssuingAccessKey(ctx, ba)
+		return err
+	}
+
+	ba.Annotations[keyRotatedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	ba.Annotations[retiringAccessKeysAnnotation] = strings.Join(retiringKeys, ",")
+	delete(ba.Annotations, issuingAccessKeyAnnotation)
+	_, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, ba, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("record key rotation of bucketAccess [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Name, err)
+	}
+
+	log.AddContext(ctx).Infof("issue new access key [%s] of user [%s] for bucketAccess [%s/%s] successfully",
+		accessResp.AccessKeyId, userName, ba.Namespace, ba.Name)
+	return nil
+}
+
+// markIssuingAccessKey records whether a new access key is being issued for BucketAccess
+func (s *provisionerServer) markIssuingAccessKey(ctx context.Context, ba *v1alpha1.BucketAccess,
+	issuing bool) (*v1alpha1.BucketAccess, error) {
+	ba = ba.DeepCopy()
+	if ba.Annotations == nil {
+		ba.Annotations = map[string]string{}
+	}
+	if issuing {
+		ba.Annotations[issuingAccessKeyAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(ba.Annotations, issuingAccessKeyAnnotation)
+	}
+
+	updated, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, ba, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("record issuing access key of bucketAccess [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Name, err)
+	}
+
+	return updated, nil
+}
+
+// unmarkIssuingAccessKey removes the issuing record after the issuing fails,
+// the access keys of BucketAccess are not collected until it is removed
+func (s *provisionerServer) unmarkIssuingAccessKey(ctx context.Context, ba *v1alpha1.BucketAccess) {
+	_, err := s.markIssuingAccessKey(ctx, ba, false)
+	if err != nil {
+		log.AddContext(ctx).Warningf("remove issuing record of bucketAccess [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Name, err)
+	}
+}
+
+// deliveredAccessKey returns the access key in the bucket info of the credentials secret of BucketAccess,
+// which is issued by the driver when the access is granted or the access key is rotated
+func (s *provisionerServer) deliveredAccessKey(ctx context.Context, ba *v1alpha1.BucketAccess) (string, error) {
+	secret, err := s.K8sClient.CoreV1().Secrets(ba.Namespace).
+		Get(ctx, ba.Spec.CredentialsSecretName, metaV1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get credentials secret [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Spec.CredentialsSecretName, err)
+	}
+
+	var info cosiapi.BucketInfo
+	err = json.Unmarshal(secret.Data[bucketInfoSecretKey], &info)
+	if err != nil || info.Spec.S3 == nil {
+		return "", fmt.Errorf("credentials secret [%s/%s] has no s3 bucket info", secret.Namespace, secret.Name)
+	}
+
+	return info.Spec.S3.AccessKeyID, nil
+}
+
+// updateCredentialsSecret writes the access key into the bucket info of the credentials secret of BucketAccess
+func (s *provisionerServer) updateCredentialsSecret(ctx context.Context, ba *v1alpha1.BucketAccess,
+	accessKeyId, secretAccessKey string) error {
+	secret, err := s.K8sClient.CoreV1().Secrets(ba.Namespace).
+		Get(ctx, ba.Spec.CredentialsSecretName, metaV1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get credentials secret [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Spec.CredentialsSecretName, err)
+	}
+
+	var info cosiapi.BucketInfo
+	err = json.Unmarshal(secret.Data[bucketInfoSecretKey], &info)
+	if err != nil || info.Spec.S3 == nil {
+		return fmt.Errorf("credentials secret [%s/%s] has no s3 bucket info", secret.Namespace, secret.Name)
+	}
+
+	info.Spec.S3.AccessKeyID = accessKeyId
+	info.Spec.S3.AccessSecretKey = secretAccessKey
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal bucket info failed, error is [%v]", err)
+	}
+
+	secret.Data[bucketInfoSecretKey] = data
+	_, err = s.K8sClient.CoreV1().Secrets(ba.Namespace).Update(ctx, secret, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("update credentials secret [%s/%s] failed, error is [%v]",
+			secret.Namespace, secret.Name, err)
+	}
+
+	return nil
+}
+
+// retireAccessKeys deletes the old access keys of the user of BucketAccess after the grace period,
+// the ones which are not exist any more are already retired
+func (s *provisionerServer) retireAccessKeys(ctx context.Context, ba *v1alpha1.BucketAccess,
+	accessKeys []string) error {
+	accountIdData, bacAccountSecret, err := fetchDataFromResourceId(ba.Status.AccountID, s.K8sClient)
+	if err != nil {
+		return fmt.Errorf("fetch data from resourceId [%s] failed, error is [%v]", ba.Status.AccountID, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	userName := accountIdData.resourceName
+	listUserAksResp, err := userClient.ListUserAccessKeys(ctx, &api.ListUserAccessKeysInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("list user [%s] access keys failed, error is [%v]", userName, err)
+	}
+
+	for _, accessKey := range accessKeys {
+		if !slices.Contains(listUserAksResp.AccessKeys, accessKey) {
+			log.AddContext(ctx).Infof("access key [%s] of user [%s] is not exist, it is already retired",
+				accessKey, userName)
+			continue
+		}
+
+		_, err = userClient.DeleteUserAccess(ctx, &api.DeleteUserAccessInput{UserName: userName,
+			AccessKeyId: accessKey})
+		if err != nil {
+			return fmt.Errorf("delete user [%s] access key [%s] failed, error is [%v]", userName, accessKey, err)
+		}
+	}
+
+	delete(ba.Annotations, retiringAccessKeysAnnotation)
+	ba.Annotations[keysRetiredAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	_, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Update(ctx, ba, metaV1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("record key retirement of bucketAccess [%s/%s] failed, error is [%v]",
+			ba.Namespace, ba.Name, err)
+	}
+
+	log.AddContext(ctx).Infof("retire access keys %v of user [%s] for bucketAccess [%s/%s] successfully",
+		accessKeys, userName, ba.Namespace, ba.Name)
+	return nil
+}
@@ -0,0 +1,279 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	cosiapi "sigs.k8s.io/container-object-storage-interface-api/apis"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+)
+
+func Test_ParseKeyRotation(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name         string
+		parameters   map[string]string
+		wantRotation *keyRotation
+		wantErr      bool
+	}{
+		{name: "not configured", parameters: map[string]string{}},
+		{name: "default grace period", parameters: map[string]string{keyRotationInterval: "720h"},
+			wantRotation: &keyRotation{interval: 720 * time.Hour, gracePeriod: time.Hour}},
+		{name: "custom grace period",
+			parameters:   map[string]string{keyRotationInterval: "24h", keyRotationGracePeriod: "10m"},
+			wantRotation: &keyRotation{interval: 24 * time.Hour, gracePeriod: 10 * time.Minute}},
+		{name: "invalid interval", parameters: map[string]string{keyRotationInterval: "0"}, wantErr: true},
+		{name: "grace period too long",
+			parameters: map[string]string{keyRotationInterval: "1h", keyRotationGracePeriod: "2h"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotRotation, gotErr := parseKeyRotation(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+			assert.Equal(t, c.wantRotation, gotRotation)
+		})
+	}
+}
+
+func newRotationBucketAccess(annotations map[string]string, created time.Time) *v1alpha1.BucketAccess {
+	return &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", Annotations: annotations,
+			CreationTimestamp: metaV1.NewTime(created)},
+		Spec: v1alpha1.BucketAccessSpec{CredentialsSecretName: "cred-demo"},
+		Status: v1alpha1.BucketAccessStatus{AccessGranted: true,
+			AccountID: "default/bac-secret/ba-uid-1"},
+	}
+}
+
+func Test_ProvisionerServer_RotateAccessKey_Issue(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	info, _ := json.Marshal(cosiapi.BucketInfo{Spec: cosiapi.BucketInfoSpec{BucketName: "bucket-demo",
+		S3: &cosiapi.SecretS3{Endpoint: "https://endpoint", AccessKeyID: "ak-old", AccessSecretKey: "sk-old"}}})
+	cred := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "cred-demo", Namespace: "app"},
+		Data: map[string][]byte{bucketInfoSecretKey: info}}
+	ba := newRotationBucketAccess(nil, time.Now().Add(-2*time.Hour))
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(cred), BucketClient: cosifake.NewSimpleClientset(ba)}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-uid-1"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "ListUserAccessKeys",
+		&api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-foreign", "ak-old"}}, nil)
+	mock.ApplyMethodReturn(c, "CreateUserAccess",
+		&api.CreateUserAccessOutput{AccessKeyId: "ak-new", SecretAccessKey: "sk-new"}, nil)
+
+	// act
+	gotErr := s.rotateAccessKey(ctx, ba, &keyRotation{interval: time.Hour, gracePeriod: time.Minute})
+
+	// assert
+	assert.NoError(t, gotErr)
+	gotCred, err := s.K8sClient.CoreV1().Secrets("app").Get(ctx, "cred-demo", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	var gotInfo cosiapi.BucketInfo
+	assert.NoError(t, json.Unmarshal(gotCred.Data[bucketInfoSecretKey], &gotInfo))
+	assert.Equal(t, &cosiapi.SecretS3{Endpoint: "https://endpoint", AccessKeyID: "ak-new",
+		AccessSecretKey: "sk-new"}, gotInfo.Spec.S3)
+	gotBa, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+		Get(ctx, "ba-demo", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ak-old", gotBa.Annotations[retiringAccessKeysAnnotation])
+	assert.Contains(t, gotBa.Annotations, keyRotatedAtAnnotation)
+	assert.NotContains(t, gotBa.Annotations, issuingAccessKeyAnnotation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_IssueAccessKey_SecretUpdateFailed(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	ba := newRotationBucketAccess(nil, time.Now().Add(-2*time.Hour))
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(), BucketClient: cosifake.NewSimpleClientset(ba)}
+	var issuingRecorded bool
+	var deleted []string
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-uid-1"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "ListUserAccessKeys", &api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-old"}}, nil)
+	mock.ApplyPrivateMethod(s, "deliveredAccessKey",
+		func(_ *provisionerServer, _ context.Context, _ *v1alpha1.BucketAccess) (string, error) {
+			return "ak-old", nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "CreateUserAccess",
+		func(_ *poe.Client, _ context.Context, _ *api.CreateUserAccessInput) (*api.CreateUserAccessOutput, error) {
+			got, _ := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+				Get(ctx, "ba-demo", metaV1.GetOptions{})
+			_, issuingRecorded = got.Annotations[issuingAccessKeyAnnotation]
+			return &api.CreateUserAccessOutput{AccessKeyId: "ak-new", SecretAccessKey: "sk-new"}, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+		func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+			deleted = append(deleted, input.AccessKeyId)
+			return &api.DeleteUserAccessOutput{}, nil
+		})
+
+	// act
+	gotErr := s.issueAccessKey(ctx, ba)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "get credentials secret [app/cred-demo] failed")
+	assert.True(t, issuingRecorded)
+	assert.Equal(t, []string{"ak-new"}, deleted)
+	gotBa, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+		Get(ctx, "ba-demo", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, gotBa.Annotations, issuingAccessKeyAnnotation)
+	assert.NotContains(t, gotBa.Annotations, retiringAccessKeysAnnotation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_RotateAccessKey_Retire(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	ba := newRotationBucketAccess(map[string]string{
+		keyRotatedAtAnnotation:       time.Now().Add(-2 * time.Minute).UTC().Format(time.RFC3339),
+		retiringAccessKeysAnnotation: "ak-old",
+	}, time.Now().Add(-2*time.Hour))
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(ba)}
+	var deleted []string
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-uid-1"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "ListUserAccessKeys",
+		&api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-new", "ak-old"}}, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+		func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+			deleted = append(deleted, input.AccessKeyId)
+			return &api.DeleteUserAccessOutput{}, nil
+		})
+
+	// act
+	gotErr := s.rotateAccessKey(ctx, ba, &keyRotation{interval: time.Hour, gracePeriod: time.Minute})
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"ak-old"}, deleted)
+	gotBa, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+		Get(ctx, "ba-demo", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, gotBa.Annotations, retiringAccessKeysAnnotation)
+	assert.Contains(t, gotBa.Annotations, keysRetiredAtAnnotation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_RetireAccessKeys_AlreadyRetired(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	ba := newRotationBucketAccess(map[string]string{retiringAccessKeysAnnotation: "ak-old"},
+		time.Now().Add(-2*time.Hour))
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(ba)}
+	var deleted []string
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(fetchDataFromResourceId,
+		&resourceIdInfo{resourceName: "ba-uid-1"}, &coreV1.Secret{}, nil)
+	mock.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "ListUserAccessKeys", &api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-new"}}, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+		func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+			deleted = append(deleted, input.AccessKeyId)
+			return &api.DeleteUserAccessOutput{}, nil
+		})
+
+	// act
+	gotErr := s.retireAccessKeys(ctx, ba, []string{"ak-old"})
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Empty(t, deleted)
+	gotBa, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").
+		Get(ctx, "ba-demo", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, gotBa.Annotations, retiringAccessKeysAnnotation)
+	assert.Contains(t, gotBa.Annotations, keysRetiredAtAnnotation)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_RotateAccessKey_NotDue(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	ba := newRotationBucketAccess(nil, time.Now())
+	s := &provisionerServer{}
+
+	// mock
+	mock := gomonkey.ApplyPrivateMethod(s, "issueAccessKey",
+		func(_ *provisionerServer, _ context.Context, _ *v1alpha1.BucketAccess) error {
+			t.Errorf("access key is issued before it is due")
+			return nil
+		})
+
+	// act
+	gotErr := s.rotateAccessKey(ctx, ba, &keyRotation{interval: time.Hour, gracePeriod: time.Minute})
+
+	// assert
+	assert.NoError(t, gotErr)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,134 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// Clusters sharing one backend may generate the same names, e.g. the account names of BucketAccesses.
+// The cluster-prefix naming strategy prefixes the names with the cluster id to keep them apart.
+// The names exceeding the length limit of backend are truncated and suffixed with the hash of the full name,
+// so the same name is always generated for the same BucketAccess or bucket.
+// The generated names are kept in AccountId and BucketId, revoking and deleting use them as they are,
+// so the resources named before the strategy changes are still found.
+
+var (
+	userNameStrategy = flag.String("user-name-strategy", namingStrategyVerbatim,
+		"the naming strategy of backend users, verbatim uses the account name of BucketAccess, "+
+			"cluster-prefix prefixes it with the cluster-id")
+	bucketNameStrategy = flag.String("bucket-name-strategy", namingStrategyVerbatim,
+		"the naming strategy of buckets, verbatim uses the bucket name generated by cosi, "+
+			"cluster-prefix prefixes it with the cluster-id")
+)
+
+const (
+	namingStrategyVerbatim      = "verbatim"
+	namingStrategyClusterPrefix = "cluster-prefix"
+
+	defaultMaxUserNameLength = 64
+	maxBucketNameLength      = 63
+	nameHashLength           = 8
+)
+
+func checkNamingStrategies() error {
+	for _, strategy := range []string{*userNameStrategy, *bucketNameStrategy} {
+		if strategy != namingStrategyVerbatim && strategy != namingStrategyClusterPrefix {
+			return fmt.Errorf("invalid naming strategy [%s], it must be %s or %s", strategy,
+				namingStrategyVerbatim, namingStrategyClusterPrefix)
+		}
+	}
+
+	return nil
+}
+
+// backendUserName generates the name of backend user for the account name of BucketAccess,
+// the length limit is the maxUserNameLength of account secret
+func backendUserName(accountName string, accountSecret *coreV1.Secret) string {
+	maxLength := defaultMaxUserNameLength
+	if accountSecret != nil {
+		if value, err := strconv.Atoi(string(accountSecret.Data[maxUserNameLength])); err == nil &&
+			value > nameHashLength+1 {
+			maxLength = value
+		}
+	}
+
+	return limitNameLength(prefixUserName(accountName), maxLength)
+}
+
+func prefixUserName(accountName string) string {
+	if *userNameStrategy != namingStrategyClusterPrefix {
+		return accountName
+	}
+
+	return *clusterId + "-" + accountName
+}
+
+// backendBucketName generates the name of bucket for the bucket name generated by cosi
+func backendBucketName(name string) string {
+	if *bucketNameStrategy == namingStrategyClusterPrefix {
+		name = bucketNameClusterPrefix(*clusterId) + "-" + name
+	}
+
+	return limitNameLength(name, maxBucketNameLength)
+}
+
+// bucketNameClusterPrefix converts the cluster id into lowercase letters, digits and hyphens allowed in bucket names
+func bucketNameClusterPrefix(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, id)
+}
+
+// limitNameLength truncates the name exceeding max length, and suffixes it with the hash of the full name.
+// The truncated name is exactly max length, which tells how it is generated.
+func limitNameLength(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	return name[:maxLength-nameHashLength-1] + "-" + hex.EncodeToString(sum[:])[:nameHashLength]
+}
+
+// userNameMatches checks whether the backend user name is generated for the account name,
+// by the current naming strategy or verbatim
+func userNameMatches(accountName, userName string) bool {
+	if userName == accountName {
+		return true
+	}
+
+	full := prefixUserName(accountName)
+	if userName == full {
+		return true
+	}
+
+	return len(full) > len(userName) && len(userName) > nameHashLength+1 &&
+		limitNameLength(full, len(userName)) == userName
+}
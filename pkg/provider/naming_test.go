@@ -0,0 +1,141 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+)
+
+func Test_BackendUserName(t *testing.T) {
+	// arrange
+	accountName := "ba-0f6b2c3e-8a9d-4c1e-b7f0-5d2a9e3c4b1a"
+	cases := []struct {
+		name      string
+		strategy  string
+		secret    *coreV1.Secret
+		wantName  string
+		wantLimit int
+	}{
+		{name: "verbatim", strategy: namingStrategyVerbatim, wantName: accountName},
+		{name: "cluster prefix", strategy: namingStrategyClusterPrefix, wantName: "prod-" + accountName},
+		{name: "hashed by backend limit", strategy: namingStrategyClusterPrefix,
+			secret:    &coreV1.Secret{Data: map[string][]byte{maxUserNameLength: []byte("32")}},
+			wantLimit: 32},
+		{name: "invalid backend limit", strategy: namingStrategyClusterPrefix,
+			secret:   &coreV1.Secret{Data: map[string][]byte{maxUserNameLength: []byte("abc")}},
+			wantName: "prod-" + accountName},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// mock
+			mock := gomonkey.ApplyGlobalVar(userNameStrategy, c.strategy)
+			mock.ApplyGlobalVar(clusterId, "prod")
+
+			// act
+			gotName := backendUserName(accountName, c.secret)
+
+			// assert
+			if c.wantLimit > 0 {
+				assert.Len(t, gotName, c.wantLimit)
+				assert.True(t, strings.HasPrefix(gotName, "prod-ba-0f6b2c3e-8a9d-4-"))
+				assert.Equal(t, gotName, backendUserName(accountName, c.secret))
+				assert.True(t, userNameMatches(accountName, gotName))
+			} else {
+				assert.Equal(t, c.wantName, gotName)
+			}
+
+			// cleanup
+			t.Cleanup(func() {
+				mock.Reset()
+			})
+		})
+	}
+}
+
+func Test_BackendBucketName(t *testing.T) {
+	// arrange
+	name := "bc-0f6b2c3e-8a9d-4c1e-b7f0-5d2a9e3c4b1a"
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketNameStrategy, namingStrategyClusterPrefix)
+	mock.ApplyGlobalVar(clusterId, "Prod_East")
+
+	// act
+	gotName := backendBucketName(name)
+	gotLongName := backendBucketName(name + "-" + name)
+
+	// assert
+	assert.Equal(t, "prod-east-"+name, gotName)
+	assert.Len(t, gotLongName, maxBucketNameLength)
+	assert.Equal(t, gotLongName, backendBucketName(name+"-"+name))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_UserNameMatches(t *testing.T) {
+	// mock
+	mock := gomonkey.ApplyGlobalVar(userNameStrategy, namingStrategyClusterPrefix)
+	mock.ApplyGlobalVar(clusterId, "prod")
+
+	// act & assert
+	assert.True(t, userNameMatches("ba-uid", "ba-uid"))
+	assert.True(t, userNameMatches("ba-uid", "prod-ba-uid"))
+	assert.False(t, userNameMatches("ba-uid", "test-ba-uid"))
+	assert.False(t, userNameMatches("ba-uid", "ba-uid-2"))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_BucketAccessOwnsUser(t *testing.T) {
+	// arrange
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{UID: "uid-1"},
+		Status: v1alpha1.BucketAccessStatus{AccountID: "default/bac-secret/legacy-name"}}
+
+	// act & assert
+	assert.True(t, bucketAccessOwnsUser(ba, "ba-uid-1"))
+	assert.True(t, bucketAccessOwnsUser(ba, "legacy-name"))
+	assert.False(t, bucketAccessOwnsUser(ba, "ba-uid-2"))
+}
+
+func Test_CheckNamingStrategies(t *testing.T) {
+	// mock
+	mock := gomonkey.ApplyGlobalVar(bucketNameStrategy, "random")
+
+	// act
+	gotErr := checkNamingStrategies()
+
+	// assert
+	assert.ErrorContains(t, gotErr, "invalid naming strategy [random]")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,157 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/huawei/cosi-driver/pkg/utils"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	operationResultRetention = flag.Duration("operation-result-retention", defaultOperationResultRetention,
+		"how long the result of a finished long-running operation is kept for the next call to pick up")
+)
+
+const (
+	defaultOperationResultRetention = time.Hour
+)
+
+// operationFunc is the long-running work of an operation, it reports the progress in a human-readable message
+type operationFunc func(ctx context.Context, report func(progress string)) error
+
+// operation is a long-running work started in the background
+type operation struct {
+	name string
+
+	mutex      sync.Mutex
+	progress   string
+	done       bool
+	err        error
+	finishedAt time.Time
+}
+
+func (o *operation) report(progress string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.progress = progress
+}
+
+func (o *operation) finish(err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.done = true
+	o.err = err
+	o.finishedAt = time.Now()
+}
+
+func (o *operation) status() (string, bool, error, time.Time) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.progress, o.done, o.err, o.finishedAt
+}
+
+// operationTracker runs the long-running operations in the background keyed by bucket id, so they go on when the
+// caller gives up waiting, and the repeated calls of the caller pick up the progress or the final result
+type operationTracker struct {
+	operations sync.Map
+}
+
+// run starts the operation of the key if it is not running, an unavailable error with the progress is returned
+// until it is done, then the result is returned once and the operation is forgotten
+func (t *operationTracker) run(ctx context.Context, key, name string, work operationFunc) error {
+	t.dropExpired(ctx)
+
+	value, loaded := t.operations.LoadOrStore(key, &operation{name: name})
+	op, ok := value.(*operation)
+	if !ok {
+		return fmt.Errorf("invalid operation [%T] of [%s]", value, key)
+	}
+
+	if op.name != name {
+		return utilErrors.NewUnavailableErr(fmt.Sprintf("operation [%s] of [%s] is in progress, [%s] has to wait",
+			op.name, key, name))
+	}
+
+	if !loaded {
+		go runOperation(key, op, work)
+	}
+
+	progress, done, err, _ := op.status()
+	if !done {
+		if progress == "" {
+			progress = "started"
+		}
+		return utilErrors.NewUnavailableErr(fmt.Sprintf("operation [%s] of [%s] is in progress, %s",
+			name, key, progress))
+	}
+
+	t.operations.CompareAndDelete(key, op)
+	if err != nil {
+		return fmt.Errorf("operation [%s] of [%s] failed, error is [%v]", name, key, err)
+	}
+
+	log.AddContext(ctx).Infof("operation [%s] of [%s] finished", name, key)
+	return nil
+}
+
+// dropExpired forgets the results which are not picked up in time, the caller has given up on them
+func (t *operationTracker) dropExpired(ctx context.Context) {
+	t.operations.Range(func(key, value any) bool {
+		op, ok := value.(*operation)
+		if !ok {
+			return true
+		}
+
+		_, done, _, finishedAt := op.status()
+		if done && time.Since(finishedAt) > *operationResultRetention {
+			log.AddContext(ctx).Warningf("drop the result of operation [%s] of [%v] finished at [%v]",
+				op.name, key, finishedAt)
+			t.operations.CompareAndDelete(key, op)
+		}
+		return true
+	})
+}
+
+// runOperation runs the work without the context of the caller, so it is not cancelled when the caller disconnects
+func runOperation(key string, op *operation, work operationFunc) {
+	ctx, err := log.SetRequestInfo(context.Background())
+	if err != nil {
+		ctx = context.Background()
+	}
+
+	// workErr is kept as it is if the work panics, so the panic is reported as a failure
+	workErr := fmt.Errorf("operation panicked")
+	defer func() {
+		op.finish(workErr)
+	}()
+	defer utils.RecoverPanic(ctx)
+
+	log.AddContext(ctx).Infof("start operation [%s] of [%s]", op.name, key)
+	workErr = work(ctx, op.report)
+	if workErr != nil {
+		log.AddContext(ctx).Errorf("operation [%s] of [%s] failed, error is [%v]", op.name, key, workErr)
+		return
+	}
+
+	log.AddContext(ctx).Infof("operation [%s] of [%s] is done", op.name, key)
+}
@@ -0,0 +1,132 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func Test_OperationTracker_Run_ProgressAndResult(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	tracker := &operationTracker{}
+	release := make(chan struct{})
+	reported := make(chan struct{})
+	runs := 0
+	work := func(_ context.Context, report func(string)) error {
+		runs++
+		report("copied [1/2] objects")
+		close(reported)
+		<-release
+		return nil
+	}
+
+	// act
+	gotStartErr := tracker.run(ctx, "bucket-id", "clone", work)
+	<-reported
+	gotRunningErr := tracker.run(ctx, "bucket-id", "clone", work)
+	close(release)
+	assert.Eventually(t, func() bool {
+		return !utilErrors.IsUnavailableErr(tracker.run(ctx, "bucket-id", "clone", work))
+	}, time.Second, 10*time.Millisecond)
+
+	// assert
+	assert.True(t, utilErrors.IsUnavailableErr(gotStartErr))
+	assert.True(t, utilErrors.IsUnavailableErr(gotRunningErr))
+	assert.ErrorContains(t, gotRunningErr, "copied [1/2] objects")
+	assert.Equal(t, 1, runs)
+	_, exist := tracker.operations.Load("bucket-id")
+	assert.False(t, exist)
+}
+
+func Test_OperationTracker_Run_Failed(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	tracker := &operationTracker{}
+	tracker.operations.Store("bucket-id", &operation{name: "clone", done: true, err: errors.New("copy failed"),
+		finishedAt: time.Now()})
+
+	// act
+	gotErr := tracker.run(ctx, "bucket-id", "clone", nil)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "copy failed")
+	assert.False(t, utilErrors.IsUnavailableErr(gotErr))
+	_, exist := tracker.operations.Load("bucket-id")
+	assert.False(t, exist)
+}
+
+func Test_OperationTracker_Run_OtherOperationInProgress(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	tracker := &operationTracker{}
+	tracker.operations.Store("bucket-id", &operation{name: "clone"})
+
+	// act
+	gotErr := tracker.run(ctx, "bucket-id", "empty", nil)
+
+	// assert
+	assert.True(t, utilErrors.IsUnavailableErr(gotErr))
+	assert.ErrorContains(t, gotErr, "operation [clone]")
+}
+
+func Test_OperationTracker_Run_DropExpiredResult(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	tracker := &operationTracker{}
+	tracker.operations.Store("other-bucket-id", &operation{name: "clone", done: true,
+		finishedAt: time.Now().Add(-2 * time.Hour)})
+	tracker.operations.Store("bucket-id", &operation{name: "clone", done: true, finishedAt: time.Now()})
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(operationResultRetention, time.Hour)
+
+	// act
+	gotErr := tracker.run(ctx, "bucket-id", "clone", nil)
+
+	// assert
+	assert.NoError(t, gotErr)
+	_, exist := tracker.operations.Load("other-bucket-id")
+	assert.False(t, exist)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RunOperation_Panic(t *testing.T) {
+	// arrange
+	op := &operation{name: "clone"}
+
+	// act
+	runOperation("bucket-id", op, func(_ context.Context, _ func(string)) error {
+		panic("unexpected")
+	})
+
+	// assert
+	_, gotDone, gotErr, _ := op.status()
+	assert.True(t, gotDone)
+	assert.Error(t, gotErr)
+}
@@ -0,0 +1,270 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	placementRoundRobin       = "roundRobin"
+	placementLeastBuckets     = "leastBuckets"
+	placementMostFreeCapacity = "mostFreeCapacity"
+
+	// placedAccountSecretAnnotation of Bucket records the account secret chosen for it, likes '{namespace}/{name}',
+	// so the retried create request is placed on the same backend
+	placedAccountSecretAnnotation = "cosi.huawei.com/account-secret"
+)
+
+// multiBackendPlacement returns whether the bucket is placed among several account secrets
+func multiBackendPlacement(parameters map[string]string) bool {
+	return parameters[accountSecretNames] != "" || parameters[accountSecretSelector] != ""
+}
+
+func checkPlacementParameters(parameters map[string]string) error {
+	names, selector := parameters[accountSecretNames], parameters[accountSecretSelector]
+	if names != "" && selector != "" {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("%s and %s can not be set at the same time",
+			accountSecretNames, accountSecretSelector))
+	}
+
+	if selector != "" {
+		if _, err := labels.Parse(selector); err != nil {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], error is [%v]",
+				accountSecretSelector, selector, err))
+		}
+		if parameters[accountSecretNamespace] == "" {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("%s is required by %s", accountSecretNamespace,
+				accountSecretSelector))
+		}
+	}
+
+	if names != "" {
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s], empty name",
+					accountSecretNames, names))
+			}
+			if !strings.Contains(name, "/") && parameters[accountSecretNamespace] == "" {
+				return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("%s is required by the name [%s] of %s",
+					accountSecretNamespace, name, accountSecretNames))
+			}
+		}
+	}
+
+	strategy, exist := parameters[placementStrategy]
+	if exist && strategy != placementRoundRobin && strategy != placementLeastBuckets &&
+		strategy != placementMostFreeCapacity {
+		return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("invalid %s [%s]", placementStrategy, strategy))
+	}
+
+	return nil
+}
+
+// placeBucket chooses the account secret of the bucket by the placement strategy if several account secrets are
+// set, and returns the parameters referring to the chosen one
+func (s *provisionerServer) placeBucket(ctx context.Context,
+	req *cosispec.DriverCreateBucketRequest) (map[string]string, error) {
+	parameters := req.GetParameters()
+	if !multiBackendPlacement(parameters) {
+		return parameters, nil
+	}
+
+	bucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, req.GetName(), metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get bucket [%s] failed, error is [%v]", req.GetName(), err)
+	}
+
+	chosen := bucket.Annotations[placedAccountSecretAnnotation]
+	if chosen == "" {
+		candidates, err := s.listPlacementCandidates(ctx, parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		chosen, err = s.choosePlacement(ctx, parameters[placementStrategy], candidates)
+		if err != nil {
+			return nil, err
+		}
+
+		if bucket.Annotations == nil {
+			bucket.Annotations = map[string]string{}
+		}
+		bucket.Annotations[placedAccountSecretAnnotation] = chosen
+		_, err = s.BucketClient.ObjectstorageV1alpha1().Buckets().Update(ctx, bucket, metaV1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("record placement of bucket [%s] failed, error is [%v]", req.GetName(), err)
+		}
+		log.AddContext(ctx).Infof("place bucket [%s] on account secret [%s] among %v", req.GetName(), chosen,
+			candidates)
+	}
+
+	namespace, name, found := strings.Cut(chosen, "/")
+	if !found {
+		return nil, fmt.Errorf("invalid placement [%s] of bucket [%s]", chosen, req.GetName())
+	}
+
+	placed := maps.Clone(parameters)
+	placed[accountSecretNamespace] = namespace
+	placed[accountSecretName] = name
+	return placed, nil
+}
+
+// listPlacementCandidates lists the account secrets likes '{namespace}/{name}' in order
+func (s *provisionerServer) listPlacementCandidates(ctx context.Context, parameters map[string]string) ([]string,
+	error) {
+	var candidates []string
+	if selector := parameters[accountSecretSelector]; selector != "" {
+		namespace := parameters[accountSecretNamespace]
+		list, err := s.K8sClient.CoreV1().Secrets(namespace).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("list secrets of namespace [%s] by selector [%s] failed, error is [%v]",
+				namespace, selector, err)
+		}
+		for _, secret := range list.Items {
+			candidates = append(candidates, secret.Namespace+"/"+secret.Name)
+		}
+	} else {
+		for _, name := range strings.Split(parameters[accountSecretNames], ",") {
+			name = strings.TrimSpace(name)
+			if !strings.Contains(name, "/") {
+				name = parameters[accountSecretNamespace] + "/" + name
+			}
+			candidates = append(candidates, name)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no account secret to place the bucket")
+	}
+
+	slices.Sort(candidates)
+	return slices.Compact(candidates), nil
+}
+
+func (s *provisionerServer) choosePlacement(ctx context.Context, strategy string, candidates []string) (string,
+	error) {
+	switch strategy {
+	case placementLeastBuckets:
+		return s.placeOnLeastBuckets(ctx, candidates)
+	case placementMostFreeCapacity:
+		return s.placeOnMostFreeCapacity(ctx, candidates)
+	default:
+		return s.placeRoundRobin(candidates), nil
+	}
+}
+
+// placeRoundRobin takes turns among the candidates, the turn is kept for each set of candidates
+func (s *provisionerServer) placeRoundRobin(candidates []string) string {
+	value, _ := s.placementCursors.LoadOrStore(strings.Join(candidates, ","), &atomic.Uint64{})
+	cursor, ok := value.(*atomic.Uint64)
+	if !ok {
+		return candidates[0]
+	}
+
+	return candidates[(cursor.Add(1)-1)%uint64(len(candidates))]
+}
+
+// placeOnLeastBuckets chooses the candidate having the least buckets provisioned by the driver
+func (s *provisionerServer) placeOnLeastBuckets(ctx context.Context, candidates []string) (string, error) {
+	buckets, err := s.listDriverBuckets(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	counts := make(map[string]int, len(candidates))
+	for _, bucket := range buckets {
+		bucketIdData, err := disassembleResourceId(bucket.Status.BucketID)
+		if err != nil {
+			continue
+		}
+		counts[bucketIdData.acSecretNameSpace+"/"+bucketIdData.acSecretName]++
+	}
+
+	chosen := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if counts[candidate] < counts[chosen] {
+			chosen = candidate
+		}
+	}
+
+	return chosen, nil
+}
+
+// placeOnMostFreeCapacity chooses the candidate whose storage backend has the most free capacity,
+// which is supported by the centralized storage only
+func (s *provisionerServer) placeOnMostFreeCapacity(ctx context.Context, candidates []string) (string, error) {
+	chosen, most := "", int64(-1)
+	for _, candidate := range candidates {
+		free, err := s.freeCapacityOf(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if free > most {
+			chosen, most = candidate, free
+		}
+	}
+
+	return chosen, nil
+}
+
+func (s *provisionerServer) freeCapacityOf(ctx context.Context, candidate string) (int64, error) {
+	namespace, name, _ := strings.Cut(candidate, "/")
+	secret, err := s.K8sClient.CoreV1().Secrets(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("get account secret [%s] failed, error is [%v]", candidate, err)
+	}
+
+	return queryFreeCapacity(ctx, secret)
+}
+
+func queryFreeCapacity(ctx context.Context, secret *coreV1.Secret) (int64, error) {
+	userClient, err := buildClientFromSecret(ctx, secret)
+	if err != nil {
+		return 0, fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	capacityClient, ok := userClient.(api.CapacityAPI)
+	if !ok {
+		return 0, fmt.Errorf("the storage of account secret [%s/%s] does not provide capacity, %s requires "+
+			"the centralized storage", secret.Namespace, secret.Name, placementMostFreeCapacity)
+	}
+
+	output, err := capacityClient.GetCapacity(ctx, &api.GetCapacityInput{})
+	if err != nil {
+		return 0, fmt.Errorf("get capacity of account secret [%s/%s] failed, error is [%v]",
+			secret.Namespace, secret.Name, err)
+	}
+
+	return output.FreeCapacity, nil
+}
@@ -0,0 +1,207 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+	cosispec "sigs.k8s.io/container-object-storage-interface-spec"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/centralized"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func Test_CheckPlacementParameters(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name       string
+		parameters map[string]string
+		wantErr    bool
+	}{
+		{name: "names", parameters: map[string]string{accountSecretNames: "array-a, other/array-b",
+			accountSecretNamespace: "huawei-cosi", placementStrategy: placementLeastBuckets}},
+		{name: "selector", parameters: map[string]string{accountSecretSelector: "tier=gold",
+			accountSecretNamespace: "huawei-cosi"}},
+		{name: "both", parameters: map[string]string{accountSecretNames: "array-a",
+			accountSecretSelector: "tier=gold", accountSecretNamespace: "huawei-cosi"}, wantErr: true},
+		{name: "name without namespace", parameters: map[string]string{accountSecretNames: "array-a"},
+			wantErr: true},
+		{name: "empty name", parameters: map[string]string{accountSecretNames: "ns/array-a,,ns/array-b"},
+			wantErr: true},
+		{name: "invalid selector", parameters: map[string]string{accountSecretSelector: "tier in (",
+			accountSecretNamespace: "huawei-cosi"}, wantErr: true},
+		{name: "invalid strategy", parameters: map[string]string{accountSecretNames: "ns/array-a",
+			placementStrategy: "random"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotErr := checkPlacementParameters(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+			assert.Equal(t, c.wantErr, utilErrors.IsInvalidArgumentErr(gotErr))
+		})
+	}
+}
+
+func Test_ProvisionerServer_PlaceBucket_RoundRobin(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	newBucket := func(name string) *v1alpha1.Bucket {
+		return &v1alpha1.Bucket{ObjectMeta: metaV1.ObjectMeta{Name: name}}
+	}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(newBucket("bucket-1"), newBucket("bucket-2"),
+		newBucket("bucket-3"))}
+	parameters := map[string]string{accountSecretNames: "array-b,array-a", accountSecretNamespace: "huawei-cosi"}
+
+	// act
+	var got []string
+	for _, name := range []string{"bucket-1", "bucket-2", "bucket-3"} {
+		placed, err := s.placeBucket(ctx, &cosispec.DriverCreateBucketRequest{Name: name, Parameters: parameters})
+		assert.NoError(t, err)
+		got = append(got, placed[accountSecretNamespace]+"/"+placed[accountSecretName])
+	}
+
+	// assert
+	assert.Equal(t, []string{"huawei-cosi/array-a", "huawei-cosi/array-b", "huawei-cosi/array-a"}, got)
+	gotBucket, err := s.BucketClient.ObjectstorageV1alpha1().Buckets().Get(ctx, "bucket-2", metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "huawei-cosi/array-b", gotBucket.Annotations[placedAccountSecretAnnotation])
+}
+
+func Test_ProvisionerServer_PlaceBucket_Retried(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucket := &v1alpha1.Bucket{ObjectMeta: metaV1.ObjectMeta{Name: "bucket-1",
+		Annotations: map[string]string{placedAccountSecretAnnotation: "huawei-cosi/array-b"}}}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(bucket)}
+	req := &cosispec.DriverCreateBucketRequest{Name: "bucket-1", Parameters: map[string]string{
+		accountSecretNames: "array-a,array-b", accountSecretNamespace: "huawei-cosi"}}
+
+	// act
+	placed, err := s.placeBucket(ctx, req)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "array-b", placed[accountSecretName])
+	assert.Equal(t, "array-a,array-b", req.GetParameters()[accountSecretNames])
+	assert.Empty(t, req.GetParameters()[accountSecretName])
+}
+
+func Test_ProvisionerServer_PlaceOnLeastBuckets(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	newBucket := func(name, bucketId string) *v1alpha1.Bucket {
+		return &v1alpha1.Bucket{ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:   v1alpha1.BucketSpec{DriverName: "cosi.huawei.com"},
+			Status: v1alpha1.BucketStatus{BucketID: bucketId}}
+	}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(
+		newBucket("bucket-1", "huawei-cosi/array-a/bucket-1"),
+		newBucket("bucket-2", "huawei-cosi/array-a/bucket-2"),
+		newBucket("bucket-3", "huawei-cosi/array-b/bucket-3"))}
+
+	// act
+	got, err := s.placeOnLeastBuckets(ctx, []string{"huawei-cosi/array-a", "huawei-cosi/array-b",
+		"huawei-cosi/array-c"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "huawei-cosi/array-c", got)
+}
+
+func Test_ProvisionerServer_PlaceOnMostFreeCapacity(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	newSecret := func(name string) *coreV1.Secret {
+		return &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "huawei-cosi"}}
+	}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(newSecret("array-a"), newSecret("array-b"))}
+	free := map[string]int64{"array-a": 100, "array-b": 300}
+
+	// mock
+	mock := gomonkey.ApplyFunc(queryFreeCapacity, func(_ context.Context, secret *coreV1.Secret) (int64, error) {
+		return free[secret.Name], nil
+	})
+
+	// act
+	got, err := s.placeOnMostFreeCapacity(ctx, []string{"huawei-cosi/array-a", "huawei-cosi/array-b"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "huawei-cosi/array-b", got)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_QueryFreeCapacity(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &centralized.Client{}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethodReturn(c, "GetCapacity", &api.GetCapacityOutput{TotalCapacity: 1000, FreeCapacity: 400}, nil)
+
+	// act
+	got, err := queryFreeCapacity(ctx, &coreV1.Secret{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(400), got)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_QueryFreeCapacity_NotSupported(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+
+	// act
+	_, err := queryFreeCapacity(ctx, &coreV1.Secret{})
+
+	// assert
+	assert.ErrorContains(t, err, "does not provide capacity")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
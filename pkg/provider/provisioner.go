@@ -17,6 +17,7 @@ package provider
 
 import (
 	"fmt"
+	"sync"
 
 	"k8s.io/client-go/kubernetes"
 	cosiclientset "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned"
@@ -31,12 +32,41 @@ type provisionerServer struct {
 	K8sClient    kubernetes.Interface
 	BucketClient cosiclientset.Interface
 	keyLock      *keylock.KeyMutexLock
+
+	// operations is the long-running operations keyed by bucket id
+	operations operationTracker
+
+	// placementCursors is the round robin turns of bucket placement keyed by the account secrets
+	placementCursors sync.Map
+
+	// adoptionCheckedBuckets is the bucket ids which need no adoption check, they are provisioned by the driver
+	// or the static Buckets passed the check, the bucket id is dropped when the bucket is deleted
+	adoptionCheckedBuckets sync.Map
+
+	// quotaCheckedBuckets is the names of Buckets which passed the bucket quota check or are created before,
+	// the names are dropped when their buckets are deleted
+	quotaCheckedBuckets sync.Map
 }
 
 var _ cosispec.ProvisionerServer = &provisionerServer{}
 
 // NewProvisionerServer return a new cosi ProvisionerServer
 func NewProvisionerServer(provisioner, kubeConfigPath string) (cosispec.ProvisionerServer, error) {
+	err := checkNamingStrategies()
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkResourceIdVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBucketPolicyOverflow()
+	if err != nil {
+		return nil, err
+	}
+
 	kubeConfig, err := utils.GetKubeConfig(kubeConfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("get kube config failed, error is [%v]", err)
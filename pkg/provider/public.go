@@ -46,12 +46,22 @@ func assembleResourceId(accountSecretNameSpace, accountSecretName, resourceName
 }
 
 type resourceIdInfo struct {
-	acSecretNameSpace string
-	acSecretName      string
-	resourceName      string
+	acSecretNameSpace   string
+	acSecretName        string
+	resourceName        string
+	secretUID           string
+	endpointFingerprint string
 }
 
 func disassembleResourceId(resourceId string) (*resourceIdInfo, error) {
+	if strings.HasPrefix(resourceId, resourceIdV2Prefix) {
+		return decodeResourceIdV2(resourceId)
+	}
+
+	if strings.HasPrefix(resourceId, resourceIdMarker) {
+		return nil, fmt.Errorf("unsupported version of input [%s]", resourceId)
+	}
+
 	list := strings.Split(resourceId, assembleSymbol)
 	if len(list) != disassembleLength {
 		return nil, fmt.Errorf("invalid format of input [%s]", resourceId)
@@ -78,13 +88,19 @@ func fetchDataFromResourceId(resourceId string, client kubernetes.Interface) (*r
 		return nil, nil, fmt.Errorf("disassemble resourceId failed, error is [%v]", err)
 	}
 
-	secret, err := client.CoreV1().Secrets(resourceIdData.acSecretNameSpace).
-		Get(context.TODO(), resourceIdData.acSecretName, metaV1.GetOptions{})
+	ctx := context.TODO()
+	namespace, name, redirected, err := redirectAccountSecret(ctx, client, resourceIdData.acSecretNameSpace,
+		resourceIdData.acSecretName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metaV1.GetOptions{})
 	if err != nil {
-		return nil, nil, fmt.Errorf("get account secret [%s/%s] failed, error is [%v]",
-			resourceIdData.acSecretNameSpace, resourceIdData.acSecretName, err)
+		return nil, nil, fmt.Errorf("get account secret [%s/%s] failed, error is [%v]", namespace, name, err)
 	}
 
+	verifyAccountSecret(ctx, resourceIdData, secret, redirected)
 	return resourceIdData, secret, nil
 }
 
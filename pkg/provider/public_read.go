@@ -0,0 +1,202 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	enablePublicRead = flag.Bool("enable-public-read", false,
+		"allow bucketAccessClasses to grant everyone to read objects of buckets, it must be enabled explicitly")
+)
+
+// publicReadGrant is the public read requested by bucketAccessClass
+type publicReadGrant struct {
+	// prefix limits the objects everyone can read, empty means all objects of the bucket
+	prefix string
+}
+
+// parsePublicRead parses the public read of bucketAccessClass parameters, returns nil if it is not requested
+func parsePublicRead(parameters map[string]string) (*publicReadGrant, error) {
+	value, exist := parameters[publicRead]
+	if !exist {
+		return nil, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value [%s]", publicRead, value)
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	return &publicReadGrant{prefix: parameters[publicReadPrefix]}, nil
+}
+
+func checkPublicReadParameters(parameters map[string]string) error {
+	public, err := parsePublicRead(parameters)
+	if err != nil {
+		return err
+	}
+
+	if public == nil {
+		return nil
+	}
+
+	if !*enablePublicRead {
+		return fmt.Errorf("public read is disabled by the driver")
+	}
+
+	if !grantsByBucketPolicy(parameters) {
+		return fmt.Errorf("public read can only be granted by bucket policy")
+	}
+
+	return nil
+}
+
+// revokePublicRead updates the public read statement of bucket according to the public BucketAccesses left,
+// the statement is removed when the last public BucketAccess of the bucket goes away
+func (s *provisionerServer) revokePublicRead(ctx context.Context, bucketId string, bcAccountSecret *coreV1.Secret,
+	bucketName, userName string) error {
+	s3Agent, err := agent.NewS3Agent(
+		agent.Config{
+			SecretKey: string(bcAccountSecret.Data[sk]),
+			AccessKey: string(bcAccountSecret.Data[ak]),
+			Endpoint:  string(bcAccountSecret.Data[endpoint]),
+			RootCA:    bcAccountSecret.Data[rootCA],
+		})
+	if err != nil {
+		return fmt.Errorf("new s3 agent failed, error is [%v]", err)
+	}
+
+	// public read is never granted on the backends rejecting the bucket policy apis
+	bp, err := s3Agent.GetBucketPolicy(ctx, bucketName, errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket,
+		errors.ErrNoSuchBucketPolicy, errors.ErrNotImplemented, errors.ErrMethodNotAllowed))
+	if err != nil {
+		return fmt.Errorf("get bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	if bp == nil || bp.PublicReadPrefixes(bucketName) == nil {
+		log.AddContext(ctx).Debugf("bucket [%s] has no public read, skip revoke public read", bucketName)
+		return nil
+	}
+
+	prefixes, err := s.listPublicReadPrefixes(ctx, bucketId, userName)
+	if err != nil {
+		return err
+	}
+
+	editedBp := bp.SetPublicRead(bucketName, prefixes)
+	if reflect.DeepEqual(editedBp.PublicReadPrefixes(bucketName), bp.PublicReadPrefixes(bucketName)) {
+		log.AddContext(ctx).Infof("bucket [%s] public read is still required by other bucketAccesses", bucketName)
+		return nil
+	}
+
+	err = s.backupBucketPolicy(ctx, bcAccountSecret, bucketName,
+		fmt.Sprintf("revoke public read of user [%s]", userName), bp)
+	if err != nil {
+		return fmt.Errorf("backup bucket [%s] policy failed, error is [%v]", bucketName, err)
+	}
+
+	if len(editedBp.Statement) > 0 {
+		err = s3Agent.PutBucketPolicy(ctx, bucketName, editedBp,
+			errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket, errors.ErrNoSuchBucketPolicy))
+	} else {
+		err = s3Agent.DeleteBucketPolicy(ctx, bucketName,
+			errors.NewExceptionalErrCodes(errors.ErrNoSuchBucket, errors.ErrNoSuchBucketPolicy))
+	}
+	if err != nil {
+		return fmt.Errorf("update bucket [%s] public read failed, error is [%v]", bucketName, err)
+	}
+
+	log.AddContext(ctx).Infof("update bucket [%s] public read prefixes to %v successfully", bucketName, prefixes)
+	return nil
+}
+
+// listPublicReadPrefixes lists the public read prefixes of the granted BucketAccesses of bucket,
+// except the BucketAccess of the excluded account and the ones being deleted.
+// The BucketAccesses whose BucketClaim, Bucket or bucketAccessClass is not found are skipped,
+// the other failures fail the listing.
+func (s *provisionerServer) listPublicReadPrefixes(ctx context.Context, bucketId,
+	excludedAccount string) ([]string, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list bucketAccesses failed, error is [%v]", err)
+	}
+
+	var prefixes []string
+	classes := make(map[string]*v1alpha1.BucketAccessClass)
+	for i := range list.Items {
+		ba := &list.Items[i]
+		if bucketAccessOwnsUser(ba, excludedAccount) || !ba.Status.AccessGranted || ba.DeletionTimestamp != nil {
+			continue
+		}
+
+		// a transient failure must not drop the prefix of BucketAccess, or the public read is revoked wrongly
+		bucket, err := s.getBucketOfBucketAccess(ctx, ba)
+		if apiErrors.IsNotFound(err) {
+			log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], error is [%v]", ba.Namespace, ba.Name, err)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("get bucket of bucketAccess [%s/%s] failed, error is [%v]",
+				ba.Namespace, ba.Name, err)
+		}
+
+		if bucket.Status.BucketID != bucketId {
+			continue
+		}
+
+		bac, exist := classes[ba.Spec.BucketAccessClassName]
+		if !exist {
+			bac, err = s.BucketClient.ObjectstorageV1alpha1().BucketAccessClasses().
+				Get(ctx, ba.Spec.BucketAccessClassName, metaV1.GetOptions{})
+			if apiErrors.IsNotFound(err) {
+				log.AddContext(ctx).Warningf("skip bucketAccess [%s/%s], bucketAccessClass [%s] is not found",
+					ba.Namespace, ba.Name, ba.Spec.BucketAccessClassName)
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("get bucketAccessClass [%s] failed, error is [%v]",
+					ba.Spec.BucketAccessClassName, err)
+			}
+			classes[ba.Spec.BucketAccessClassName] = bac
+		}
+
+		public, err := parsePublicRead(bac.Parameters)
+		if err != nil || public == nil {
+			continue
+		}
+		prefixes = append(prefixes, public.prefix)
+	}
+
+	return prefixes, nil
+}
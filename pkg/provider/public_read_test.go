@@ -0,0 +1,206 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/s3/agent"
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+)
+
+func Test_CheckPublicReadParameters(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name       string
+		enabled    bool
+		parameters map[string]string
+		wantErr    bool
+	}{
+		{name: "not requested", parameters: map[string]string{}},
+		{name: "requested false", parameters: map[string]string{publicRead: "false"}},
+		{name: "invalid value", enabled: true, parameters: map[string]string{publicRead: "yes"}, wantErr: true},
+		{name: "driver disabled", parameters: map[string]string{publicRead: "true"}, wantErr: true},
+		{name: "not bucket policy", enabled: true,
+			parameters: map[string]string{publicRead: "true", grantMode: grantModeUserPolicy}, wantErr: true},
+		{name: "requested", enabled: true, parameters: map[string]string{publicRead: "true"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// mock
+			mock := gomonkey.ApplyGlobalVar(enablePublicRead, c.enabled)
+			defer mock.Reset()
+
+			// act
+			gotErr := checkPublicReadParameters(c.parameters)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+		})
+	}
+}
+
+func Test_ProvisionerServer_ListPublicReadPrefixes(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	bucket := &v1alpha1.Bucket{
+		ObjectMeta: metaV1.ObjectMeta{Name: "bucket-object"},
+		Status:     v1alpha1.BucketStatus{BucketID: "default/bc-secret/bucket-demo"},
+	}
+	claim := &v1alpha1.BucketClaim{
+		ObjectMeta: metaV1.ObjectMeta{Name: "claim-demo", Namespace: "app"},
+		Status:     v1alpha1.BucketClaimStatus{BucketName: "bucket-object"},
+	}
+	publicClass := &v1alpha1.BucketAccessClass{ObjectMeta: metaV1.ObjectMeta{Name: "public"},
+		Parameters: map[string]string{publicRead: "true", publicReadPrefix: "assets/"}}
+	privateClass := &v1alpha1.BucketAccessClass{ObjectMeta: metaV1.ObjectMeta{Name: "private"}}
+	newBucketAccess := func(name, uid, class string) *v1alpha1.BucketAccess {
+		return &v1alpha1.BucketAccess{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "app", UID: types.UID(uid)},
+			Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-demo", BucketAccessClassName: class},
+			Status:     v1alpha1.BucketAccessStatus{AccessGranted: true},
+		}
+	}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(bucket, claim, publicClass, privateClass,
+		newBucketAccess("ba-revoked", "uid-1", "public"), newBucketAccess("ba-public", "uid-2", "public"),
+		newBucketAccess("ba-private", "uid-3", "private"), newBucketAccess("ba-classless", "uid-4", "deleted"))}
+
+	// act
+	gotPrefixes, gotErr := s.listPublicReadPrefixes(ctx, "default/bc-secret/bucket-demo", "ba-uid-1")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"assets/"}, gotPrefixes)
+}
+
+func Test_ProvisionerServer_ListPublicReadPrefixes_GetBucketFailed(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	claim := &v1alpha1.BucketClaim{
+		ObjectMeta: metaV1.ObjectMeta{Name: "claim-demo", Namespace: "app"},
+		Status:     v1alpha1.BucketClaimStatus{BucketName: "bucket-object"},
+	}
+	ba := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-public", Namespace: "app", UID: "uid-2"},
+		Spec:       v1alpha1.BucketAccessSpec{BucketClaimName: "claim-demo", BucketAccessClassName: "public"},
+		Status:     v1alpha1.BucketAccessStatus{AccessGranted: true},
+	}
+	s := &provisionerServer{BucketClient: cosifake.NewSimpleClientset(claim, ba)}
+
+	// mock
+	s.BucketClient.(*cosifake.Clientset).PrependReactor("get", "buckets",
+		func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apiErrors.NewServiceUnavailable("etcd is unavailable")
+		})
+
+	// act
+	gotPrefixes, gotErr := s.listPublicReadPrefixes(ctx, "default/bc-secret/bucket-demo", "ba-uid-1")
+
+	// assert
+	assert.ErrorContains(t, gotErr, "get bucket of bucketAccess [app/ba-public] failed")
+	assert.Nil(t, gotPrefixes)
+}
+
+func Test_ProvisionerServer_RevokePublicRead_LastPublicAccess(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	current := policy.NewBucketPolicy(*buildGrantStatement("ba-1"),
+		*policy.NewPublicReadStatement("bucket-demo", "assets/"))
+	var updated *policy.BucketPolicy
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", current, nil)
+	mock.ApplyPrivateMethod(s, "listPublicReadPrefixes",
+		func(_ *provisionerServer, _ context.Context, _, _ string) ([]string, error) {
+			return nil, nil
+		})
+	mock.ApplyPrivateMethod(s, "backupBucketPolicy",
+		func(_ *provisionerServer, _ context.Context, _ *coreV1.Secret, _, _ string, _ *policy.BucketPolicy) error {
+			return nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, bp *policy.BucketPolicy, _ []string) error {
+			updated = bp
+			return nil
+		})
+
+	// act
+	gotErr := s.revokePublicRead(ctx, "default/bc-secret/bucket-demo", bcSecret, "bucket-demo", "ba-2")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Nil(t, updated.PublicReadPrefixes("bucket-demo"))
+	assert.Equal(t, []string{testUserArnPrefix + "ba-1"}, updated.Principals())
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_RevokePublicRead_StillRequired(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &agent.S3Agent{}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset()}
+	bcSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bc-secret", Namespace: "default"}}
+	current := policy.NewBucketPolicy(*policy.NewPublicReadStatement("bucket-demo", "assets/"))
+	putCalled := false
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(agent.NewS3Agent, c, nil)
+	mock.ApplyMethodReturn(c, "GetBucketPolicy", current, nil)
+	mock.ApplyPrivateMethod(s, "listPublicReadPrefixes",
+		func(_ *provisionerServer, _ context.Context, _, _ string) ([]string, error) {
+			return []string{"assets/"}, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "PutBucketPolicy",
+		func(_ *agent.S3Agent, _ context.Context, _ string, _ *policy.BucketPolicy, _ []string) error {
+			putCalled = true
+			return nil
+		})
+
+	// act
+	gotErr := s.revokePublicRead(ctx, "default/bc-secret/bucket-demo", bcSecret, "bucket-demo", "ba-2")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.False(t, putCalled)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,169 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// The v1 resourceId is '{secret namespace}/{secret name}/{resource name}', it can not represent the resource names
+// containing '/', and nothing tells whether the account secret is still the one creating the resource.
+// The v2 resourceId is 'cosi:v2:{secret namespace}/{secret name}/{resource name}/{secret uid}/{endpoint fingerprint}'
+// with each segment path escaped, the secret uid and endpoint fingerprint are optional.
+// Both are accepted, the version of new resourceIds is selected by resource-id-version.
+//
+// The resources of an account secret are re-pointed to another account secret by the configMap
+// 'cosi-account-secret-redirect' of the driver namespace, whose data key is '{old namespace}.{old name}'
+// and value is '{new namespace}/{new name}', e.g. after the account secret is renamed or moved.
+// A warning is logged if the account secret connects to an endpoint other than the one in the resourceId,
+// the resource is still handled with it, e.g. after the storage is accessed by a new address.
+
+var (
+	resourceIdVersion = flag.String("resource-id-version", resourceIdVersionV1,
+		"the version of BucketIds and AccountIds generated by the driver, v1 or v2, both are accepted")
+)
+
+const (
+	resourceIdVersionV1 = "v1"
+	resourceIdVersionV2 = "v2"
+
+	resourceIdMarker       = "cosi:"
+	resourceIdV2Prefix     = resourceIdMarker + resourceIdVersionV2 + ":"
+	resourceIdV2Segments   = 5
+	endpointFingerprintLen = 16
+
+	accountSecretRedirectConfigMapName = "cosi-account-secret-redirect"
+)
+
+func checkResourceIdVersion() error {
+	if *resourceIdVersion != resourceIdVersionV1 && *resourceIdVersion != resourceIdVersionV2 {
+		return fmt.Errorf("invalid resource id version [%s], it must be %s or %s", *resourceIdVersion,
+			resourceIdVersionV1, resourceIdVersionV2)
+	}
+
+	return nil
+}
+
+// encodeResourceId generates the resourceId of the resource created with account secret
+func encodeResourceId(accountSecret *coreV1.Secret, resourceName string) string {
+	if *resourceIdVersion == resourceIdVersionV1 {
+		return assembleResourceId(accountSecret.Namespace, accountSecret.Name, resourceName)
+	}
+
+	segments := []string{
+		url.PathEscape(accountSecret.Namespace),
+		url.PathEscape(accountSecret.Name),
+		url.PathEscape(resourceName),
+		url.PathEscape(string(accountSecret.UID)),
+		endpointFingerprint(accountSecret),
+	}
+	return resourceIdV2Prefix + strings.Join(segments, assembleSymbol)
+}
+
+// endpointFingerprint is the short hash of the endpoint of account secret, it is empty if there is no endpoint
+func endpointFingerprint(accountSecret *coreV1.Secret) string {
+	value := strings.TrimRight(strings.ToLower(strings.TrimSpace(string(accountSecret.Data[endpoint]))), "/")
+	if value == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:endpointFingerprintLen]
+}
+
+func decodeResourceIdV2(resourceId string) (*resourceIdInfo, error) {
+	list := strings.Split(strings.TrimPrefix(resourceId, resourceIdV2Prefix), assembleSymbol)
+	if len(list) != resourceIdV2Segments {
+		return nil, fmt.Errorf("invalid format of input [%s]", resourceId)
+	}
+
+	for i := range list {
+		value, err := url.PathUnescape(list[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid escaping of input [%s], error is [%v]", resourceId, err)
+		}
+		list[i] = value
+	}
+
+	if list[0] == "" || list[1] == "" || list[2] == "" {
+		return nil, fmt.Errorf("invalid value of input [%s]", resourceId)
+	}
+
+	return &resourceIdInfo{
+		acSecretNameSpace:   list[0],
+		acSecretName:        list[1],
+		resourceName:        list[2],
+		secretUID:           list[3],
+		endpointFingerprint: list[4],
+	}, nil
+}
+
+// redirectAccountSecret returns the account secret which the resources of the account secret are re-pointed to,
+// or the account secret itself if it is not redirected
+func redirectAccountSecret(ctx context.Context, client kubernetes.Interface, namespace, name string) (string,
+	string, bool, error) {
+	cm, err := client.CoreV1().ConfigMaps(driverNamespace()).
+		Get(ctx, accountSecretRedirectConfigMapName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return namespace, name, false, nil
+	} else if err != nil {
+		return "", "", false, fmt.Errorf("get configMap [%s/%s] failed, error is [%v]", driverNamespace(),
+			accountSecretRedirectConfigMapName, err)
+	}
+
+	target, exist := cm.Data[namespace+"."+name]
+	if !exist {
+		return namespace, name, false, nil
+	}
+
+	newNamespace, newName, found := strings.Cut(target, "/")
+	if !found || newNamespace == "" || newName == "" {
+		return "", "", false, fmt.Errorf("invalid redirect [%s] of account secret [%s/%s], the format must be "+
+			"{namespace}/{name}", target, namespace, name)
+	}
+
+	return newNamespace, newName, true, nil
+}
+
+// verifyAccountSecret warns if the account secret no longer connects to the endpoint which the resource is
+// created on, or is recreated since then. Neither fails the request, because the endpoint of storage may be
+// changed on purpose, and a recreated secret is still valid.
+func verifyAccountSecret(ctx context.Context, resourceIdData *resourceIdInfo, accountSecret *coreV1.Secret,
+	redirected bool) {
+	if resourceIdData.endpointFingerprint != "" && resourceIdData.endpointFingerprint !=
+		endpointFingerprint(accountSecret) {
+		log.AddContext(ctx).Warningf("account secret [%s/%s] connects to an endpoint other than the one "+
+			"resource [%s] is created on", accountSecret.Namespace, accountSecret.Name, resourceIdData.resourceName)
+	}
+
+	if !redirected && resourceIdData.secretUID != "" && resourceIdData.secretUID != string(accountSecret.UID) {
+		log.AddContext(ctx).Warningf("account secret [%s/%s] is recreated since resource [%s] is created",
+			accountSecret.Namespace, accountSecret.Name, resourceIdData.resourceName)
+	}
+}
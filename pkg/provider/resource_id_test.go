@@ -0,0 +1,183 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newResourceIdSecret(namespace, name, endpointValue string) *coreV1.Secret {
+	return &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name, UID: "uid-" + types.UID(name)},
+		Data:       map[string][]byte{endpoint: []byte(endpointValue)},
+	}
+}
+
+func Test_EncodeResourceId_V2RoundTrip(t *testing.T) {
+	// arrange
+	secret := newResourceIdSecret("huawei-cosi", "account-secret", "https://xxxx.com:8088/")
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(resourceIdVersion, resourceIdVersionV2)
+
+	// act
+	gotId := encodeResourceId(secret, "logs/2026")
+	gotData, gotErr := disassembleResourceId(gotId)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, "cosi:v2:huawei-cosi/account-secret/logs%2F2026/uid-account-secret/"+endpointFingerprint(secret),
+		gotId)
+	assert.Equal(t, &resourceIdInfo{acSecretNameSpace: "huawei-cosi", acSecretName: "account-secret",
+		resourceName: "logs/2026", secretUID: "uid-account-secret",
+		endpointFingerprint: endpointFingerprint(secret)}, gotData)
+	assert.Equal(t, endpointFingerprint(secret),
+		endpointFingerprint(newResourceIdSecret("", "", "HTTPS://xxxx.com:8088")))
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_EncodeResourceId_V1(t *testing.T) {
+	// arrange
+	secret := newResourceIdSecret("huawei-cosi", "account-secret", "https://xxxx.com:8088")
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(resourceIdVersion, resourceIdVersionV1)
+
+	// act
+	gotId := encodeResourceId(secret, "bucket-name")
+
+	// assert
+	assert.Equal(t, "huawei-cosi/account-secret/bucket-name", gotId)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_DisassembleResourceId_Versions(t *testing.T) {
+	// arrange
+	cases := []struct {
+		name     string
+		id       string
+		wantData *resourceIdInfo
+		wantErr  bool
+	}{
+		{name: "legacy", id: "ns/secret/bucket",
+			wantData: &resourceIdInfo{acSecretNameSpace: "ns", acSecretName: "secret", resourceName: "bucket"}},
+		{name: "v2 without optional segments", id: "cosi:v2:ns/secret/bucket//",
+			wantData: &resourceIdInfo{acSecretNameSpace: "ns", acSecretName: "secret", resourceName: "bucket"}},
+		{name: "v2 missing segments", id: "cosi:v2:ns/secret/bucket", wantErr: true},
+		{name: "v2 invalid escaping", id: "cosi:v2:ns/secret/bucket%zz//", wantErr: true},
+		{name: "unsupported version", id: "cosi:v3:ns/secret/bucket", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			gotData, gotErr := disassembleResourceId(c.id)
+
+			// assert
+			assert.Equal(t, c.wantErr, gotErr != nil)
+			assert.Equal(t, c.wantData, gotData)
+		})
+	}
+}
+
+func Test_FetchDataFromResourceId_Redirect(t *testing.T) {
+	// arrange
+	oldSecret := newResourceIdSecret("old-ns", "old-secret", "https://xxxx.com:8088")
+	newSecret := newResourceIdSecret("new-ns", "new-secret", "https://xxxx.com:8088")
+	redirect := &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: accountSecretRedirectConfigMapName, Namespace: driverNamespace()},
+		Data:       map[string]string{"old-ns.old-secret": "new-ns/new-secret"},
+	}
+	client := fake.NewSimpleClientset(newSecret, redirect)
+
+	// act
+	gotData, gotSecret, gotErr := fetchDataFromResourceId(encodeResourceId(oldSecret, "bucket-name"), client)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, "bucket-name", gotData.resourceName)
+	assert.Equal(t, newSecret, gotSecret)
+}
+
+func Test_FetchDataFromResourceId_EndpointChanged(t *testing.T) {
+	// arrange
+	secret := newResourceIdSecret("huawei-cosi", "account-secret", "https://xxxx.com:8088")
+	changed := newResourceIdSecret("huawei-cosi", "account-secret", "https://yyyy.com:8088")
+	client := fake.NewSimpleClientset(changed)
+
+	// mock
+	mock := gomonkey.ApplyGlobalVar(resourceIdVersion, resourceIdVersionV2)
+
+	// act
+	gotData, gotSecret, gotErr := fetchDataFromResourceId(encodeResourceId(secret, "bucket-name"), client)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, "bucket-name", gotData.resourceName)
+	assert.Equal(t, changed, gotSecret)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_RedirectAccountSecret_InvalidTarget(t *testing.T) {
+	// arrange
+	redirect := &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: accountSecretRedirectConfigMapName, Namespace: driverNamespace()},
+		Data:       map[string]string{"old-ns.old-secret": "new-secret"},
+	}
+	client := fake.NewSimpleClientset(redirect)
+
+	// act
+	_, _, _, gotErr := redirectAccountSecret(context.TODO(), client, "old-ns", "old-secret")
+
+	// assert
+	assert.ErrorContains(t, gotErr, "invalid redirect [new-secret] of account secret [old-ns/old-secret]")
+}
+
+func Test_CheckResourceIdVersion(t *testing.T) {
+	// mock
+	mock := gomonkey.ApplyGlobalVar(resourceIdVersion, "v3")
+
+	// act
+	gotErr := checkResourceIdVersion()
+
+	// assert
+	assert.ErrorContains(t, gotErr, "invalid resource id version [v3]")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
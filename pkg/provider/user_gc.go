@@ -0,0 +1,469 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// The user garbage collector finds the backend users and access keys created by the driver of this cluster,
+// which no BucketAccess uses any more, e.g. left by crashes and failed revokes.
+// Orphans are only reported by default, they are deleted if user-gc-dry-run is disabled.
+// Besides the schedule, a run is requested by annotating the configMap 'cosi-user-gc' of the driver namespace
+// with 'cosi.huawei.com/user-gc-request=dry-run' or 'cosi.huawei.com/user-gc-request=delete',
+// the result is reported in the annotation 'cosi.huawei.com/user-gc-result' and the orphans in its data.
+
+var (
+	userGcInterval = flag.Duration("user-gc-interval", defaultUserGcInterval,
+		"the interval of collecting the orphaned backend users and access keys, 0 means disabled")
+	userGcDryRun = flag.Bool("user-gc-dry-run", true,
+		"only report the orphaned backend users and access keys found by the scheduled collection")
+	userGcRequestInterval = flag.Duration("user-gc-request-interval", defaultUserGcRequestInterval,
+		"the interval of handling on demand user gc requests, 0 means disabled")
+)
+
+const (
+	defaultUserGcInterval        = time.Hour
+	defaultUserGcRequestInterval = 30 * time.Second
+
+	userGcConfigMapName     = "cosi-user-gc"
+	userGcReportKey         = "orphans"
+	userGcRequestAnnotation = "cosi.huawei.com/user-gc-request"
+	userGcResultAnnotation  = "cosi.huawei.com/user-gc-result"
+	userGcRequestDryRun     = "dry-run"
+	userGcRequestDelete     = "delete"
+
+	orphanKindUser      = "User"
+	orphanKindAccessKey = "AccessKey"
+)
+
+// userGcOrphan is a backend user or access key which no BucketAccess uses
+type userGcOrphan struct {
+	// Kind is either User or AccessKey
+	Kind string `json:"kind"`
+
+	// AccountSecret is the account secret of the backend, the format is {namespace}/{name}
+	AccountSecret string `json:"accountSecret"`
+
+	// UserName is the orphaned user, or the user of the orphaned access key
+	UserName string `json:"userName"`
+
+	// AccessKeyId is the orphaned access key, it is empty if the kind is User
+	AccessKeyId string `json:"accessKeyId,omitempty"`
+
+	// Reason describes why it is orphaned
+	Reason string `json:"reason"`
+
+	// Deleted is true if it is deleted by the collection
+	Deleted bool `json:"deleted"`
+}
+
+// collectUserGarbage is the scheduled collection of orphaned backend users and access keys
+func (s *provisionerServer) collectUserGarbage(ctx context.Context) {
+	orphans, err := s.runUserGc(ctx, *userGcDryRun)
+	if err != nil {
+		log.AddContext(ctx).Errorf("collect orphaned users failed, error is [%v]", err)
+		return
+	}
+
+	log.AddContext(ctx).Infof("collect orphaned users finished: %s", summarizeUserGc(orphans, *userGcDryRun))
+}
+
+// handleUserGcRequests runs the collection requested by the annotation of user gc configMap
+func (s *provisionerServer) handleUserGcRequests(ctx context.Context) {
+	namespace := driverNamespace()
+	cm, err := s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, userGcConfigMapName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		log.AddContext(ctx).Errorf("get configMap [%s/%s] failed, error is [%v]", namespace,
+			userGcConfigMapName, err)
+		return
+	}
+
+	request, exist := cm.Annotations[userGcRequestAnnotation]
+	if !exist {
+		return
+	}
+
+	var result string
+	var orphans []userGcOrphan
+	if request != userGcRequestDryRun && request != userGcRequestDelete {
+		result = fmt.Sprintf("invalid request [%s], it must be %s or %s", request, userGcRequestDryRun,
+			userGcRequestDelete)
+	} else if orphans, err = s.runUserGc(ctx, request == userGcRequestDryRun); err != nil {
+		result = fmt.Sprintf("%s failed at %s, error is [%v]", request, time.Now().UTC().Format(time.RFC3339), err)
+	} else {
+		result = fmt.Sprintf("%s finished at %s, %s", request, time.Now().UTC().Format(time.RFC3339),
+			summarizeUserGc(orphans, request == userGcRequestDryRun))
+	}
+
+	log.AddContext(ctx).Infof("handle user gc request of configMap [%s/%s]: %s", namespace, userGcConfigMapName,
+		result)
+	if err = s.finishUserGcRequest(ctx, result, orphans); err != nil {
+		log.AddContext(ctx).Errorf("finish user gc request of configMap [%s/%s] failed, error is [%v]",
+			namespace, userGcConfigMapName, err)
+	}
+}
+
+// finishUserGcRequest removes the request annotation and reports the result together with the orphans
+func (s *provisionerServer) finishUserGcRequest(ctx context.Context, result string, orphans []userGcOrphan) error {
+	report, err := json.Marshal(orphans)
+	if err != nil {
+		return fmt.Errorf("marshal orphans failed, error is [%v]", err)
+	}
+
+	namespace := driverNamespace()
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.K8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, userGcConfigMapName, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		delete(cm.Annotations, userGcRequestAnnotation)
+		cm.Annotations[userGcResultAnnotation] = result
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[userGcReportKey] = string(report)
+		_, err = s.K8sClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metaV1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("update configMap [%s/%s] failed, error is [%v]", namespace, userGcConfigMapName, err)
+	}
+
+	return nil
+}
+
+func summarizeUserGc(orphans []userGcOrphan, dryRun bool) string {
+	var users, accessKeys, deleted int
+	for _, orphan := range orphans {
+		if orphan.Kind == orphanKindUser {
+			users++
+		} else {
+			accessKeys++
+		}
+		if orphan.Deleted {
+			deleted++
+		}
+	}
+
+	if dryRun {
+		return fmt.Sprintf("found [%d] orphaned users and [%d] orphaned access keys, dry run", users, accessKeys)
+	}
+	return fmt.Sprintf("found [%d] orphaned users and [%d] orphaned access keys, [%d] deleted",
+		users, accessKeys, deleted)
+}
+
+// runUserGc finds the orphans on the backend of each account secret used by the driver, and deletes them
+// unless it is dry run. The failures of a backend are logged and do not stop the others.
+func (s *provisionerServer) runUserGc(ctx context.Context, dryRun bool) ([]userGcOrphan, error) {
+	accountSecrets, err := s.listAccountSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []userGcOrphan
+	for _, accountSecret := range accountSecrets {
+		found, err := s.collectAccountOrphans(ctx, accountSecret, dryRun)
+		if err != nil {
+			log.AddContext(ctx).Errorf("collect orphans of account secret [%s] failed, error is [%v]",
+				accountSecret, err)
+		}
+		orphans = append(orphans, found...)
+	}
+
+	return orphans, nil
+}
+
+// listAccountSecrets lists the account secrets which the users are created with, they are referred to
+// by the bucketAccessClasses of the driver and the AccountId of granted BucketAccesses.
+// The format of returned secret is {namespace}/{name}.
+func (s *provisionerServer) listAccountSecrets(ctx context.Context) ([]string, error) {
+	secrets := make(map[string]bool)
+	classes, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccessClasses().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list bucketAccessClasses failed, error is [%v]", err)
+	}
+
+	for _, bac := range classes.Items {
+		if bac.DriverName != s.Provisioner || bac.Parameters[accountSecretNamespace] == "" ||
+			bac.Parameters[accountSecretName] == "" {
+			continue
+		}
+		secrets[bac.Parameters[accountSecretNamespace]+"/"+bac.Parameters[accountSecretName]] = true
+	}
+
+	accesses, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list bucketAccesses failed, error is [%v]", err)
+	}
+
+	for _, ba := range accesses.Items {
+		accountIdData, err := disassembleResourceId(ba.Status.AccountID)
+		if err != nil {
+			continue
+		}
+
+		namespace, name, _, err := redirectAccountSecret(ctx, s.K8sClient, accountIdData.acSecretNameSpace,
+			accountIdData.acSecretName)
+		if err != nil {
+			return nil, err
+		}
+		secrets[namespace+"/"+name] = true
+	}
+
+	result := make([]string, 0, len(secrets))
+	for secret := range secrets {
+		result = append(result, secret)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// collectAccountOrphans finds the orphans on the backend of account secret.
+// A user created by the driver is orphaned if no BucketAccess generates its name, or the BucketAccess is granted
+// by another account. An access key of a granted user is orphaned if it is neither in the credentials secret
+// nor retiring.
+func (s *provisionerServer) collectAccountOrphans(ctx context.Context, accountSecret string,
+	dryRun bool) ([]userGcOrphan, error) {
+	namespace, name, _ := strings.Cut(accountSecret, "/")
+	secret, err := s.K8sClient.CoreV1().Secrets(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get account secret [%s] failed, error is [%v]", accountSecret, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, secret)
+	if err != nil {
+		return nil, fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	listUsersResp, err := userClient.ListUsers(ctx, &api.ListUsersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("list users failed, error is [%v]", err)
+	}
+
+	// BucketAccesses are listed after users, so that the BucketAccess of a user being created is always found
+	accesses, err := s.listBucketAccessesByUser(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []userGcOrphan
+	for _, u := range listUsersResp.Users {
+		if !ownsUser(u.Ownership, s.Provisioner) {
+			continue
+		}
+
+		ba, exist := accesses[u.UserName]
+		if !exist {
+			orphans = append(orphans, s.collectOrphanedUser(ctx, secret, u.UserName,
+				"no bucketAccess refers to the user", dryRun))
+			continue
+		}
+
+		// the access of BucketAccess is being granted or revoked
+		if !ba.Status.AccessGranted || ba.Status.AccountID == "" || ba.DeletionTimestamp != nil {
+			continue
+		}
+
+		refers, err := s.accountIdRefersTo(ctx, ba.Status.AccountID, namespace, name, u.UserName)
+		if err != nil {
+			log.AddContext(ctx).Warningf("skip user [%s], error is [%v]", u.UserName, err)
+			continue
+		}
+
+		if !refers {
+			orphans = append(orphans, s.collectOrphanedUser(ctx, secret, u.UserName,
+				fmt.Sprintf("bucketAccess [%s/%s] is granted by account [%s]", ba.Namespace, ba.Name,
+					ba.Status.AccountID), dryRun))
+			continue
+		}
+
+		found, err := s.collectOrphanedAccessKeys(ctx, userClient, accountSecret, u.UserName, ba, dryRun)
+		if err != nil {
+			log.AddContext(ctx).Warningf("collect orphaned access keys of user [%s] failed, error is [%v]",
+				u.UserName, err)
+		}
+		orphans = append(orphans, found...)
+	}
+
+	return orphans, nil
+}
+
+// accountIdRefersTo checks whether the AccountId refers to the user of account secret,
+// the account secret in AccountId may be redirected
+func (s *provisionerServer) accountIdRefersTo(ctx context.Context, accountId, namespace, name,
+	userName string) (bool, error) {
+	accountIdData, err := disassembleResourceId(accountId)
+	if err != nil {
+		return false, fmt.Errorf("disassemble accountId [%s] failed, error is [%v]", accountId, err)
+	}
+
+	idNamespace, idName, _, err := redirectAccountSecret(ctx, s.K8sClient, accountIdData.acSecretNameSpace,
+		accountIdData.acSecretName)
+	if err != nil {
+		return false, err
+	}
+
+	return idNamespace == namespace && idName == name && accountIdData.resourceName == userName, nil
+}
+
+// listBucketAccessesByUser lists BucketAccesses by the names of their backend users on the backend of account
+// secret, which are the ones generated by the naming strategy, in the AccountId, and the legacy account names
+func (s *provisionerServer) listBucketAccessesByUser(ctx context.Context, accountSecret *coreV1.Secret) (
+	map[string]*v1alpha1.BucketAccess, error) {
+	list, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(metaV1.NamespaceAll).
+		List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list bucketAccesses failed, error is [%v]", err)
+	}
+
+	accesses := make(map[string]*v1alpha1.BucketAccess, len(list.Items))
+	for i := range list.Items {
+		ba := &list.Items[i]
+		accountName := bucketAccessAccountName(ba)
+		accesses[accountName] = ba
+		accesses[backendUserName(accountName, accountSecret)] = ba
+		if accountIdData, err := disassembleResourceId(ba.Status.AccountID); err == nil {
+			accesses[accountIdData.resourceName] = ba
+		}
+	}
+
+	return accesses, nil
+}
+
+func (s *provisionerServer) collectOrphanedUser(ctx context.Context, accountSecret *coreV1.Secret, userName,
+	reason string, dryRun bool) userGcOrphan {
+	orphan := userGcOrphan{Kind: orphanKindUser, AccountSecret: accountSecret.Namespace + "/" + accountSecret.Name,
+		UserName: userName, Reason: reason}
+	log.AddContext(ctx).Infof("found orphaned user [%s] of account secret [%s], %s", userName,
+		orphan.AccountSecret, reason)
+	if dryRun {
+		return orphan
+	}
+
+	err := removeUser(ctx, accountSecret, userName, s.Provisioner)
+	if err != nil {
+		log.AddContext(ctx).Errorf("delete orphaned user [%s] failed, error is [%v]", userName, err)
+		return orphan
+	}
+
+	log.AddContext(ctx).Infof("delete orphaned user [%s] successfully", userName)
+	orphan.Deleted = true
+	return orphan
+}
+
+func (s *provisionerServer) collectOrphanedAccessKeys(ctx context.Context, userClient api.UserAPI,
+	accountSecret, userName string, ba *v1alpha1.BucketAccess, dryRun bool) ([]userGcOrphan, error) {
+	listUserAksResp, err := userClient.ListUserAccessKeys(ctx, &api.ListUserAccessKeysInput{UserName: userName})
+	if err != nil {
+		return nil, fmt.Errorf("list user [%s] access keys failed, error is [%v]", userName, err)
+	}
+
+	// the expected access keys are read after listing, so that a key being issued is always expected
+	expected, err := s.expectedAccessKeys(ctx, ba)
+	if err != nil {
+		return nil, err
+	}
+	if expected == nil {
+		log.AddContext(ctx).Infof("bucketAccess [%s/%s] is issuing a new access key, skip collecting "+
+			"access keys of user [%s]", ba.Namespace, ba.Name, userName)
+		return nil, nil
+	}
+
+	var orphans []userGcOrphan
+	for _, accessKey := range listUserAksResp.AccessKeys {
+		if expected[accessKey] {
+			continue
+		}
+
+		orphan := userGcOrphan{Kind: orphanKindAccessKey, AccountSecret: accountSecret, UserName: userName,
+			AccessKeyId: accessKey, Reason: fmt.Sprintf("bucketAccess [%s/%s] does not use the access key",
+				ba.Namespace, ba.Name)}
+		log.AddContext(ctx).Infof("found orphaned access key [%s] of user [%s], %s", accessKey, userName,
+			orphan.Reason)
+		if !dryRun {
+			_, err = userClient.DeleteUserAccess(ctx, &api.DeleteUserAccessInput{UserName: userName,
+				AccessKeyId: accessKey})
+			if err != nil {
+				log.AddContext(ctx).Errorf("delete orphaned access key [%s] of user [%s] failed, error is [%v]",
+					accessKey, userName, err)
+			} else {
+				log.AddContext(ctx).Infof("delete orphaned access key [%s] of user [%s] successfully",
+					accessKey, userName)
+				orphan.Deleted = true
+			}
+		}
+		orphans = append(orphans, orphan)
+	}
+
+	return orphans, nil
+}
+
+// expectedAccessKeys returns the access keys used by BucketAccess: the one in its credentials secret and
+// the retiring ones. The access keys of expired BucketAccess are all deleted, none is expected.
+// It returns nil if a new access key is being issued, all access keys are kept until the rotation is recorded.
+func (s *provisionerServer) expectedAccessKeys(ctx context.Context, ba *v1alpha1.BucketAccess) (map[string]bool,
+	error) {
+	deliveredKey, err := s.deliveredAccessKey(ctx, ba)
+	if err != nil {
+		return nil, err
+	}
+
+	// the annotations are read after the credentials secret, which is updated first when a key is issued
+	latest, err := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses(ba.Namespace).
+		Get(ctx, ba.Name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get bucketAccess [%s/%s] failed, error is [%v]", ba.Namespace, ba.Name, err)
+	}
+
+	if _, issuing := latest.Annotations[issuingAccessKeyAnnotation]; issuing {
+		return nil, nil
+	}
+
+	expected := make(map[string]bool)
+	if _, expired := latest.Annotations[accessExpiredAnnotation]; expired {
+		return expected, nil
+	}
+
+	expected[deliveredKey] = true
+	if retiring := latest.Annotations[retiringAccessKeysAnnotation]; retiring != "" {
+		for _, accessKey := range strings.Split(retiring, ",") {
+			expected[accessKey] = true
+		}
+	}
+
+	return expected, nil
+}
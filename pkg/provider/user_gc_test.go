@@ -0,0 +1,259 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	cosiapi "sigs.k8s.io/container-object-storage-interface-api/apis"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+)
+
+func newUserGcServer() *provisionerServer {
+	info, _ := json.Marshal(cosiapi.BucketInfo{Spec: cosiapi.BucketInfoSpec{BucketName: "bucket-demo",
+		S3: &cosiapi.SecretS3{AccessKeyID: "ak-1", AccessSecretKey: "sk-1"}}})
+	cred := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "cred-demo", Namespace: "app"},
+		Data: map[string][]byte{bucketInfoSecretKey: info}}
+	accountSecret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "bac-secret", Namespace: "default"}}
+	bac := &v1alpha1.BucketAccessClass{ObjectMeta: metaV1.ObjectMeta{Name: "bac-demo"},
+		DriverName: "cosi.huawei.com",
+		Parameters: map[string]string{accountSecretNamespace: "default", accountSecretName: "bac-secret"}}
+	ba := &v1alpha1.BucketAccess{
+		ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1",
+			Annotations: map[string]string{retiringAccessKeysAnnotation: "ak-2"}},
+		Spec: v1alpha1.BucketAccessSpec{BucketAccessClassName: "bac-demo", CredentialsSecretName: "cred-demo"},
+		Status: v1alpha1.BucketAccessStatus{AccessGranted: true,
+			AccountID: "default/bac-secret/ba-uid-1"},
+	}
+	return &provisionerServer{Provisioner: "cosi.huawei.com", K8sClient: fake.NewSimpleClientset(cred, accountSecret),
+		BucketClient: cosifake.NewSimpleClientset(bac, ba)}
+}
+
+func mockUserGcBackend(c *poe.Client) *gomonkey.Patches {
+	owned := &api.UserOwnership{ClusterID: defaultClusterId, DriverName: "cosi.huawei.com"}
+	return gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil).
+		ApplyMethodReturn(c, "Close", nil).
+		ApplyMethodReturn(c, "ListUsers", &api.ListUsersOutput{Users: []api.User{
+			{UserName: "ba-uid-1", Ownership: owned},
+			{UserName: "ba-uid-9", Ownership: owned},
+			{UserName: "legacy-user"},
+		}}, nil).
+		ApplyMethodReturn(c, "ListUserAccessKeys",
+			&api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-1", "ak-2", "ak-3"}}, nil)
+}
+
+func Test_ProvisionerServer_RunUserGc_DryRun(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	s := newUserGcServer()
+	deleted := false
+
+	// mock
+	mock := mockUserGcBackend(c).
+		ApplyFunc(removeUser, func(context.Context, *coreV1.Secret, string, string) error {
+			deleted = true
+			return nil
+		}).
+		ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+			func(*poe.Client, context.Context, *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput, error) {
+				deleted = true
+				return &api.DeleteUserAccessOutput{}, nil
+			})
+
+	// act
+	gotOrphans, gotErr := s.runUserGc(ctx, true)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.False(t, deleted)
+	assert.Equal(t, []userGcOrphan{
+		{Kind: orphanKindAccessKey, AccountSecret: "default/bac-secret", UserName: "ba-uid-1", AccessKeyId: "ak-3",
+			Reason: "bucketAccess [app/ba-demo] does not use the access key"},
+		{Kind: orphanKindUser, AccountSecret: "default/bac-secret", UserName: "ba-uid-9",
+			Reason: "no bucketAccess refers to the user"},
+	}, gotOrphans)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_RunUserGc_Delete(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	s := newUserGcServer()
+	var removedUsers, deletedKeys []string
+
+	// mock
+	mock := mockUserGcBackend(c).
+		ApplyFunc(removeUser, func(_ context.Context, _ *coreV1.Secret, userName, _ string) error {
+			removedUsers = append(removedUsers, userName)
+			return nil
+		}).
+		ApplyMethod(reflect.TypeOf(c), "DeleteUserAccess",
+			func(_ *poe.Client, _ context.Context, input *api.DeleteUserAccessInput) (*api.DeleteUserAccessOutput,
+				error) {
+				deletedKeys = append(deletedKeys, input.AccessKeyId)
+				return &api.DeleteUserAccessOutput{}, nil
+			})
+
+	// act
+	gotOrphans, gotErr := s.runUserGc(ctx, false)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"ba-uid-9"}, removedUsers)
+	assert.Equal(t, []string{"ak-3"}, deletedKeys)
+	assert.Len(t, gotOrphans, 2)
+	assert.True(t, gotOrphans[0].Deleted)
+	assert.True(t, gotOrphans[1].Deleted)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CollectAccountOrphans_GrantedByOtherAccount(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	s := newUserGcServer()
+	ba, _ := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").Get(ctx, "ba-demo", metaV1.GetOptions{})
+	ba.Status.AccountID = "default/other-secret/ba-uid-1"
+	_, _ = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").Update(ctx, ba, metaV1.UpdateOptions{})
+
+	// mock
+	mock := mockUserGcBackend(c)
+
+	// act
+	gotOrphans, gotErr := s.collectAccountOrphans(ctx, "default/bac-secret", true)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []userGcOrphan{
+		{Kind: orphanKindUser, AccountSecret: "default/bac-secret", UserName: "ba-uid-1",
+			Reason: "bucketAccess [app/ba-demo] is granted by account [default/other-secret/ba-uid-1]"},
+		{Kind: orphanKindUser, AccountSecret: "default/bac-secret", UserName: "ba-uid-9",
+			Reason: "no bucketAccess refers to the user"},
+	}, gotOrphans)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_CollectAccountOrphans_IssuingAccessKey(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	s := newUserGcServer()
+	ba, _ := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").Get(ctx, "ba-demo", metaV1.GetOptions{})
+	ba.Annotations[issuingAccessKeyAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	_, _ = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").Update(ctx, ba, metaV1.UpdateOptions{})
+
+	// mock
+	mock := mockUserGcBackend(c)
+
+	// act
+	gotOrphans, gotErr := s.collectAccountOrphans(ctx, "default/bac-secret", true)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []userGcOrphan{
+		{Kind: orphanKindUser, AccountSecret: "default/bac-secret", UserName: "ba-uid-9",
+			Reason: "no bucketAccess refers to the user"},
+	}, gotOrphans)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_ProvisionerServer_ListAccountSecrets(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	s := newUserGcServer()
+	ba, _ := s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").Get(ctx, "ba-demo", metaV1.GetOptions{})
+	ba.Status.AccountID = "default/old-secret/ba-uid-1"
+	_, _ = s.BucketClient.ObjectstorageV1alpha1().BucketAccesses("app").Update(ctx, ba, metaV1.UpdateOptions{})
+
+	// act
+	gotSecrets, gotErr := s.listAccountSecrets(ctx)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"default/bac-secret", "default/old-secret"}, gotSecrets)
+}
+
+func Test_ProvisionerServer_HandleUserGcRequests(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	cm := &coreV1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{
+		Name:        userGcConfigMapName,
+		Namespace:   driverNamespace(),
+		Annotations: map[string]string{userGcRequestAnnotation: userGcRequestDryRun},
+	}}
+	s := &provisionerServer{K8sClient: fake.NewSimpleClientset(cm)}
+	orphans := []userGcOrphan{{Kind: orphanKindUser, AccountSecret: "default/bac-secret", UserName: "ba-uid-9",
+		Reason: "no bucketAccess refers to the user"}}
+	var gotDryRun bool
+
+	// mock
+	mock := gomonkey.ApplyPrivateMethod(s, "runUserGc",
+		func(_ *provisionerServer, _ context.Context, dryRun bool) ([]userGcOrphan, error) {
+			gotDryRun = dryRun
+			return orphans, nil
+		})
+
+	// act
+	s.handleUserGcRequests(ctx)
+
+	// assert
+	assert.True(t, gotDryRun)
+	gotCm, err := s.K8sClient.CoreV1().ConfigMaps(driverNamespace()).
+		Get(ctx, userGcConfigMapName, metaV1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, gotCm.Annotations, userGcRequestAnnotation)
+	assert.Contains(t, gotCm.Annotations[userGcResultAnnotation],
+		"found [1] orphaned users and [0] orphaned access keys, dry run")
+	var gotOrphans []userGcOrphan
+	assert.NoError(t, json.Unmarshal([]byte(gotCm.Data[userGcReportKey]), &gotOrphans))
+	assert.Equal(t, orphans, gotOrphans)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,95 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// parseGroups parses the comma separated groups of bucketAccessClass parameters, empty items are ignored
+func parseGroups(parameters map[string]string) []string {
+	var groupNames []string
+	for _, name := range strings.Split(parameters[groups], ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			groupNames = append(groupNames, name)
+		}
+	}
+
+	return groupNames
+}
+
+// joinGroups adds the user to the pre-defined groups, the groups are never created by the driver,
+// because their policies are controlled by the administrator
+func joinGroups(ctx context.Context, bacAccountSecret *coreV1.Secret, userName string, groupNames []string) error {
+	if len(groupNames) == 0 {
+		return nil
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	for _, groupName := range groupNames {
+		getGroupResp, err := userClient.GetGroup(ctx, &api.GetGroupInput{GroupName: groupName})
+		if err != nil {
+			return fmt.Errorf("get group [%s] failed, error is [%v]", groupName, err)
+		}
+
+		if getGroupResp == nil {
+			return utilErrors.NewInvalidArgumentErr(fmt.Sprintf("group [%s] not exist", groupName))
+		}
+
+		_, err = userClient.AddUserToGroup(ctx, &api.AddUserToGroupInput{GroupName: groupName, UserName: userName})
+		if err != nil {
+			return fmt.Errorf("add user [%s] to group [%s] failed, error is [%v]", userName, groupName, err)
+		}
+
+		log.AddContext(ctx).Infof("add user [%s] to group [%s] successfully", userName, groupName)
+	}
+
+	return nil
+}
+
+// leaveGroups removes the user from all groups it belongs to
+func leaveGroups(ctx context.Context, userClient api.UserAPI, userName string) error {
+	listGroupsResp, err := userClient.ListGroupsForUser(ctx, &api.ListGroupsForUserInput{UserName: userName})
+	if err != nil {
+		return fmt.Errorf("list groups of user [%s] failed, error is [%v]", userName, err)
+	}
+
+	for _, groupName := range listGroupsResp.GroupNames {
+		_, err = userClient.RemoveUserFromGroup(ctx,
+			&api.RemoveUserFromGroupInput{GroupName: groupName, UserName: userName})
+		if err != nil {
+			return fmt.Errorf("remove user [%s] from group [%s] failed, error is [%v]", userName, groupName, err)
+		}
+
+		log.AddContext(ctx).Infof("remove user [%s] from group [%s] successfully", userName, groupName)
+	}
+
+	return nil
+}
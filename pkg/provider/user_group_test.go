@@ -0,0 +1,100 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+	utilErrors "github.com/huawei/cosi-driver/pkg/utils/errors"
+)
+
+func Test_ParseGroups(t *testing.T) {
+	// arrange
+	parameters := map[string]string{groups: " group-1, ,group-2 "}
+
+	// act
+	got := parseGroups(parameters)
+
+	// assert
+	assert.Equal(t, []string{"group-1", "group-2"}, got)
+}
+
+func Test_JoinGroups_GroupNotExist(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+
+	// mock
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "GetGroup",
+		func(_ *poe.Client, _ context.Context, input *api.GetGroupInput) (*api.GetGroupOutput, error) {
+			if input.GroupName == "group-missing" {
+				return nil, nil
+			}
+			return &api.GetGroupOutput{GroupName: input.GroupName}, nil
+		})
+	mock.ApplyMethodReturn(c, "AddUserToGroup", &api.AddUserToGroupOutput{}, nil)
+
+	// act
+	gotErr := joinGroups(ctx, &coreV1.Secret{}, "user-demo", []string{"group-1", "group-missing"})
+
+	// assert
+	assert.True(t, utilErrors.IsInvalidArgumentErr(gotErr))
+	assert.ErrorContains(t, gotErr, "group [group-missing] not exist")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_LeaveGroups_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	var removed []string
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(c, "ListGroupsForUser",
+		&api.ListGroupsForUserOutput{GroupNames: []string{"group-1", "group-2"}}, nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "RemoveUserFromGroup",
+		func(_ *poe.Client, _ context.Context,
+			input *api.RemoveUserFromGroupInput) (*api.RemoveUserFromGroupOutput, error) {
+			removed = append(removed, input.GroupName)
+			return &api.RemoveUserFromGroupOutput{}, nil
+		})
+
+	// act
+	gotErr := leaveGroups(ctx, c, "user-demo")
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, []string{"group-1", "group-2"}, removed)
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,69 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+var (
+	clusterId = flag.String("cluster-id", defaultClusterId,
+		"the id of the kubernetes cluster, it is recorded in the backend users created by the driver")
+	removeUnownedUsers = flag.Bool("remove-unowned-users", false,
+		"remove the backend users not created by the driver of this cluster when revoking bucket access, "+
+			"e.g. the users created by the drivers of other clusters")
+)
+
+const (
+	defaultClusterId = "default"
+
+	// unknownBucketAccess is recorded when the BucketAccess of user can not be found,
+	// '-' is neither a valid namespace nor a valid name, so it never refers to a real BucketAccess
+	unknownBucketAccess = "-/-"
+)
+
+// userOwnership builds the ownership recorded in the backend user created for the account of BucketAccess.
+// The BucketAccess reference is unknownBucketAccess if the BucketAccess can not be found,
+// so that the recorded ownership is still complete and can be parsed.
+func (s *provisionerServer) userOwnership(ctx context.Context, userName string) *api.UserOwnership {
+	ownership := &api.UserOwnership{ClusterID: *clusterId, DriverName: s.Provisioner}
+	ba, err := s.getBucketAccessByAccountName(ctx, userName)
+	if err != nil {
+		log.AddContext(ctx).Warningf("get bucketAccess of user [%s] failed, the ownership has unknown bucketAccess, "+
+			"error is [%v]", userName, err)
+		ownership.BucketAccess = unknownBucketAccess
+		return ownership
+	}
+
+	ownership.BucketAccess = ba.Namespace + "/" + ba.Name
+	return ownership
+}
+
+// ownsUser checks whether the user is created by the driver of this cluster
+func ownsUser(ownership *api.UserOwnership, driverName string) bool {
+	return ownership != nil && ownership.DriverName == driverName && ownership.ClusterID == *clusterId
+}
+
+// legacyUser checks whether the user is created by the driver before the ownership is recorded,
+// such users have no ownership and are named after the account of BucketAccess
+func legacyUser(ownership *api.UserOwnership, userName string) bool {
+	return ownership == nil && strings.HasPrefix(userName, bucketAccessAccountPrefix)
+}
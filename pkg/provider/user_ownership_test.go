@@ -0,0 +1,58 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	cosifake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func Test_ProvisionerServer_UserOwnership(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	ba := &v1alpha1.BucketAccess{ObjectMeta: metaV1.ObjectMeta{Name: "ba-demo", Namespace: "app", UID: "uid-1"}}
+	s := &provisionerServer{Provisioner: "cosi.huawei.com", BucketClient: cosifake.NewSimpleClientset(ba)}
+
+	// act
+	gotFound := s.userOwnership(ctx, "ba-uid-1")
+	gotMissing := s.userOwnership(ctx, "ba-uid-2")
+
+	// assert
+	assert.Equal(t, &api.UserOwnership{ClusterID: defaultClusterId, DriverName: "cosi.huawei.com",
+		BucketAccess: "app/ba-demo"}, gotFound)
+	assert.Equal(t, &api.UserOwnership{ClusterID: defaultClusterId, DriverName: "cosi.huawei.com",
+		BucketAccess: unknownBucketAccess}, gotMissing)
+}
+
+func Test_OwnsUser(t *testing.T) {
+	// arrange
+	owned := &api.UserOwnership{ClusterID: defaultClusterId, DriverName: "cosi.huawei.com"}
+	otherCluster := &api.UserOwnership{ClusterID: "other", DriverName: "cosi.huawei.com"}
+	otherDriver := &api.UserOwnership{ClusterID: defaultClusterId, DriverName: "other.driver"}
+
+	// act & assert
+	assert.True(t, ownsUser(owned, "cosi.huawei.com"))
+	assert.False(t, ownsUser(nil, "cosi.huawei.com"))
+	assert.False(t, ownsUser(otherCluster, "cosi.huawei.com"))
+	assert.False(t, ownsUser(otherDriver, "cosi.huawei.com"))
+}
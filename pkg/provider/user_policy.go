@@ -0,0 +1,98 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	// userPolicyName is the name of the inline policy attached to the user of BucketAccess,
+	// each BucketAccess has its own user, so one policy is enough
+	userPolicyName = "cosi-bucket-access"
+)
+
+// putUserPolicy attaches the access profile of statement to the user as an inline policy.
+// The user policy only takes effect when the user and the bucket belong to the same account.
+func putUserPolicy(ctx context.Context, bacAccountSecret *coreV1.Secret, bucketName, userName, userArn string,
+	statement *policy.Statement) error {
+	up := policy.NewUserPolicy(*statement)
+	findings := up.Lint(policy.LintOptions{BucketName: bucketName})
+	logLintFindings(ctx, bucketName, findings)
+	if policy.HasLintError(findings) {
+		return fmt.Errorf("user [%s] policy is rejected by lint", userName)
+	}
+
+	document, err := up.ToJsonString()
+	if err != nil {
+		return fmt.Errorf("marshal user [%s] policy failed, error is [%v]", userName, err)
+	}
+
+	userClient, err := buildClientFromSecret(ctx, bacAccountSecret)
+	if err != nil {
+		return fmt.Errorf("build client from secret failed, error is [%v]", err)
+	}
+	defer userClient.Close(ctx)
+
+	_, err = userClient.PutUserPolicy(ctx, &api.PutUserPolicyInput{
+		UserName:       userName,
+		PolicyName:     userPolicyName,
+		PolicyDocument: document,
+	})
+	if err != nil {
+		return fmt.Errorf("put user [%s] policy failed, error is [%v]", userName, err)
+	}
+
+	return verifyUserPolicy(ctx, userClient, userName, userArn, statement)
+}
+
+// verifyUserPolicy reads back the user policy and evaluates it,
+// to make sure that the user actually has the intended rights.
+func verifyUserPolicy(ctx context.Context, userClient api.UserAPI, userName, userArn string,
+	statement *policy.Statement) error {
+	resp, err := userClient.GetUserPolicy(ctx, &api.GetUserPolicyInput{UserName: userName, PolicyName: userPolicyName})
+	if err != nil {
+		return fmt.Errorf("get user [%s] policy for verification failed, error is [%v]", userName, err)
+	}
+
+	if resp == nil {
+		return fmt.Errorf("user [%s] policy not found after putting it", userName)
+	}
+
+	up, err := policy.ParsePolicy(resp.PolicyDocument)
+	if err != nil {
+		return fmt.Errorf("parse user [%s] policy failed, error is [%v]", userName, err)
+	}
+
+	// the user policy applies to the user itself, so it is evaluated as the user is the principal
+	expected := *statement
+	expected.Principal = map[string][]string{}
+	expected.WithPrincipals(userArn)
+	err = checkStatementGranted(up.AttachTo(userArn), &expected)
+	if err != nil {
+		return fmt.Errorf("verify user [%s] policy failed, error is [%v]", userName, err)
+	}
+
+	log.AddContext(ctx).Infof("verify user [%s] policy successfully", userName)
+	return nil
+}
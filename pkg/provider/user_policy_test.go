@@ -0,0 +1,94 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package provider providers cosi standard interface
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/huawei/cosi-driver/pkg/s3/policy"
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/user/clientset/poe"
+)
+
+func Test_PutUserPolicy_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	userArn := testUserArnPrefix + "user-demo"
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals(userArn).WithActions(policy.AllowedReadActions).
+		WithResources("bucket-demo").WithSubResources("bucket-demo").Build()
+
+	// mock
+	var document string
+	mock := gomonkey.ApplyFuncReturn(buildClientFromSecret, c, nil)
+	mock.ApplyMethodReturn(c, "Close", nil)
+	mock.ApplyMethod(reflect.TypeOf(c), "PutUserPolicy",
+		func(_ *poe.Client, _ context.Context, input *api.PutUserPolicyInput) (*api.PutUserPolicyOutput, error) {
+			document = input.PolicyDocument
+			return &api.PutUserPolicyOutput{}, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(c), "GetUserPolicy",
+		func(_ *poe.Client, _ context.Context, input *api.GetUserPolicyInput) (*api.GetUserPolicyOutput, error) {
+			return &api.GetUserPolicyOutput{UserName: input.UserName, PolicyName: input.PolicyName,
+				PolicyDocument: document}, nil
+		})
+
+	// act
+	gotErr := putUserPolicy(ctx, &coreV1.Secret{}, "bucket-demo", "user-demo", userArn, statement)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.NotContains(t, document, "Principal")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_VerifyUserPolicy_NotGranted(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &poe.Client{}
+	userArn := testUserArnPrefix + "user-demo"
+	statement := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithPrincipals(userArn).WithActions(policy.AllowedReadWriteActions).WithResources("bucket-demo").Build()
+	readOnly := policy.NewStatementBuilder().WithSID("user-demo").WithEffect(policy.EffectAllow).
+		WithActions(policy.AllowedReadActions).WithResources("bucket-demo").Build()
+	document, err := policy.NewUserPolicy(*readOnly).ToJsonString()
+	assert.NoError(t, err)
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(c, "GetUserPolicy", &api.GetUserPolicyOutput{PolicyDocument: document}, nil)
+
+	// act
+	gotErr := verifyUserPolicy(ctx, c, "user-demo", userArn, statement)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "verify user [user-demo] policy failed")
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
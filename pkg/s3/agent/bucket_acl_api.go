@@ -0,0 +1,148 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package agent provides s3 agent and its apis
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/huawei/cosi-driver/pkg/utils"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+// PutBucketAcl applies the access control policy to the bucket
+func (s *S3Agent) PutBucketAcl(ctx context.Context, bucketName string, acp *s3.AccessControlPolicy,
+	exceptionalErrCodes []string) error {
+	log.AddContext(ctx).Infof("start to put bucket [%s] acl [%s]", bucketName, acp)
+
+	input := &s3.PutBucketAclInput{
+		Bucket:              aws.String(bucketName),
+		AccessControlPolicy: acp,
+	}
+
+	_, err := s.Client.PutBucketAcl(input)
+	if err != nil {
+		var awsErr awserr.Error
+		if !errors.As(err, &awsErr) {
+			return fmt.Errorf("convert err to aws err failed, origin err is [%v]", err)
+		}
+
+		if !utils.ContainsElement(exceptionalErrCodes, awsErr.Code()) {
+			return fmt.Errorf("put bucket acl failed, error is [%v]", err)
+		} else {
+			msg := fmt.Sprintf("exceptional case about putting bucket acl, message is [%s]", awsErr)
+			log.AddContext(ctx).Infof(msg)
+			return nil
+		}
+	}
+
+	log.AddContext(ctx).Infof("put bucket [%s] acl successfully", bucketName)
+	return nil
+}
+
+// GetBucketAcl get the access control policy of bucket
+func (s *S3Agent) GetBucketAcl(ctx context.Context, bucketName string,
+	exceptionalErrCodes []string) (*s3.AccessControlPolicy, error) {
+	log.AddContext(ctx).Infof("start to get bucket [%s] acl", bucketName)
+
+	out, err := s.Client.GetBucketAcl(&s3.GetBucketAclInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		var awsErr awserr.Error
+		if !errors.As(err, &awsErr) {
+			return nil, fmt.Errorf("convert err to aws err failed, origin err is [%v]", err)
+		}
+
+		if !utils.ContainsElement(exceptionalErrCodes, awsErr.Code()) {
+			return nil, fmt.Errorf("get bucket acl failed, error is [%v]", err)
+		} else {
+			msg := fmt.Sprintf("exceptional case about getting bucket acl, message is [%s]", awsErr)
+			log.AddContext(ctx).Infof(msg)
+			return nil, nil
+		}
+	}
+
+	log.AddContext(ctx).Infof("get bucket [%s] acl successfully", bucketName)
+	return &s3.AccessControlPolicy{Owner: out.Owner, Grants: out.Grants}, nil
+}
+
+// SetCanonicalUserGrants replaces the grants of the canonical user with the given permissions,
+// grants of other grantees are kept unchanged
+func SetCanonicalUserGrants(acp *s3.AccessControlPolicy, canonicalId string,
+	permissions ...string) *s3.AccessControlPolicy {
+	newAcp, _ := RemoveCanonicalUserGrants(acp, canonicalId)
+	for _, permission := range permissions {
+		newAcp.Grants = append(newAcp.Grants, &s3.Grant{
+			Grantee: &s3.Grantee{
+				ID:   aws.String(canonicalId),
+				Type: aws.String(s3.TypeCanonicalUser),
+			},
+			Permission: aws.String(permission),
+		})
+	}
+
+	return newAcp
+}
+
+// RemoveCanonicalUserGrants removes all grants of the canonical user,
+// and reports whether any grant is removed
+func RemoveCanonicalUserGrants(acp *s3.AccessControlPolicy, canonicalId string) (*s3.AccessControlPolicy, bool) {
+	newAcp := &s3.AccessControlPolicy{Owner: acp.Owner}
+	var removed bool
+	for _, grant := range acp.Grants {
+		if isCanonicalUserGrant(grant, canonicalId) {
+			removed = true
+			continue
+		}
+		newAcp.Grants = append(newAcp.Grants, grant)
+	}
+
+	return newAcp, removed
+}
+
+// CanonicalUserPermissions returns the permissions granted to the canonical user
+func CanonicalUserPermissions(acp *s3.AccessControlPolicy, canonicalId string) []string {
+	var permissions []string
+	for _, grant := range acp.Grants {
+		if isCanonicalUserGrant(grant, canonicalId) {
+			permissions = append(permissions, aws.StringValue(grant.Permission))
+		}
+	}
+
+	return permissions
+}
+
+// CanonicalUserIds returns the ids of the canonical users granted in the access control policy
+func CanonicalUserIds(acp *s3.AccessControlPolicy) []string {
+	var ids []string
+	for _, grant := range acp.Grants {
+		if grant != nil && grant.Grantee != nil && aws.StringValue(grant.Grantee.Type) == s3.TypeCanonicalUser {
+			ids = append(ids, aws.StringValue(grant.Grantee.ID))
+		}
+	}
+
+	return ids
+}
+
+func isCanonicalUserGrant(grant *s3.Grant, canonicalId string) bool {
+	return grant != nil && grant.Grantee != nil &&
+		aws.StringValue(grant.Grantee.Type) == s3.TypeCanonicalUser &&
+		aws.StringValue(grant.Grantee.ID) == canonicalId
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package agent provides s3 agent and its apis
+package agent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huawei/cosi-driver/pkg/s3/errors"
+)
+
+func Test_S3Agent_GetBucketAcl_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	ctx := context.TODO()
+	owner := &s3.Owner{ID: aws.String("owner-id")}
+	grants := []*s3.Grant{{Grantee: &s3.Grantee{ID: aws.String("owner-id"), Type: aws.String(s3.TypeCanonicalUser)},
+		Permission: aws.String(s3.PermissionFullControl)}}
+	want := &s3.AccessControlPolicy{Owner: owner, Grants: grants}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "GetBucketAcl",
+		func(_ *s3.S3, input *s3.GetBucketAclInput) (*s3.GetBucketAclOutput, error) {
+			return &s3.GetBucketAclOutput{Owner: owner, Grants: grants}, nil
+		})
+
+	// act
+	got, gotErr := s3Agent.GetBucketAcl(ctx, "bucket-demo", errors.EmptyExceptionalErrCodes)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("Test_S3Agent_GetBucketAcl_Success failed, got= [%v], want= [%v], gotErr= [%v], wantErr= nil",
+			got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_PutBucketAcl_Failed(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	ctx := context.TODO()
+	clientErr := awserr.New("NotImplemented", "not implemented", fmt.Errorf("s3 client error"))
+	wantErr := fmt.Errorf("put bucket acl failed, error is [%v]", clientErr)
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "PutBucketAcl",
+		func(_ *s3.S3, input *s3.PutBucketAclInput) (*s3.PutBucketAclOutput, error) {
+			return nil, clientErr
+		})
+
+	// act
+	gotErr := s3Agent.PutBucketAcl(ctx, "bucket-demo", &s3.AccessControlPolicy{}, errors.EmptyExceptionalErrCodes)
+
+	// assert
+	if !reflect.DeepEqual(wantErr, gotErr) {
+		t.Errorf("Test_S3Agent_PutBucketAcl_Failed failed, gotErr= [%v], wantErr= [%v]", gotErr, wantErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_SetAndRemoveCanonicalUserGrants(t *testing.T) {
+	// arrange
+	ownerGrant := &s3.Grant{Grantee: &s3.Grantee{ID: aws.String("owner-id"), Type: aws.String(s3.TypeCanonicalUser)},
+		Permission: aws.String(s3.PermissionFullControl)}
+	acp := &s3.AccessControlPolicy{Owner: &s3.Owner{ID: aws.String("owner-id")}, Grants: []*s3.Grant{ownerGrant}}
+
+	// act
+	granted := SetCanonicalUserGrants(acp, "user-id", s3.PermissionRead)
+	granted = SetCanonicalUserGrants(granted, "user-id", s3.PermissionRead, s3.PermissionWrite)
+	removed, gotRemoved := RemoveCanonicalUserGrants(granted, "user-id")
+	_, gotRemovedAgain := RemoveCanonicalUserGrants(removed, "user-id")
+
+	// assert
+	assert.Equal(t, []string{s3.PermissionRead, s3.PermissionWrite}, CanonicalUserPermissions(granted, "user-id"))
+	assert.True(t, gotRemoved)
+	assert.False(t, gotRemovedAgain)
+	assert.Equal(t, []*s3.Grant{ownerGrant}, removed.Grants)
+	assert.Equal(t, []*s3.Grant{ownerGrant}, acp.Grants)
+}
+
+func Test_CanonicalUserIds(t *testing.T) {
+	// arrange
+	acp := &s3.AccessControlPolicy{Grants: []*s3.Grant{
+		{Grantee: &s3.Grantee{ID: aws.String("owner-id"), Type: aws.String(s3.TypeCanonicalUser)},
+			Permission: aws.String(s3.PermissionFullControl)},
+		{Grantee: &s3.Grantee{URI: aws.String("http://acs.amazonaws.com/groups/global/AllUsers"),
+			Type: aws.String(s3.TypeGroup)}, Permission: aws.String(s3.PermissionRead)},
+		{Grantee: &s3.Grantee{ID: aws.String("user-id"), Type: aws.String(s3.TypeCanonicalUser)},
+			Permission: aws.String(s3.PermissionRead)},
+	}}
+
+	// act
+	gotIds := CanonicalUserIds(acp)
+
+	// assert
+	assert.Equal(t, []string{"owner-id", "user-id"}, gotIds)
+}
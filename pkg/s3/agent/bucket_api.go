@@ -29,6 +29,13 @@ import (
 
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucket(ctx context.Context, bucketName, acl, location string) error {
+	_, err := s.CreateBucketIfAbsent(ctx, bucketName, acl, location)
+	return err
+}
+
+// CreateBucketIfAbsent creates a bucket with the given name,
+// created is false if the bucket already exists and is owned by the account
+func (s *S3Agent) CreateBucketIfAbsent(ctx context.Context, bucketName, acl, location string) (bool, error) {
 	log.AddContext(ctx).Infof("start to create bucket, the bucketName is [%s], "+
 		"acl is [%s], location is [%s]", bucketName, acl, location)
 
@@ -48,13 +55,14 @@ func (s *S3Agent) CreateBucket(ctx context.Context, bucketName, acl, location st
 		var awsErr awserr.Error
 		if errors.As(err, &awsErr) && (awsErr.Code() == s3.ErrCodeBucketAlreadyOwnedByYou) {
 			log.AddContext(ctx).Infof("bucket [%s] already exists, reason [%s]", bucketName, err.Error())
+			return false, nil
 		} else {
-			return fmt.Errorf("create bucket failed, error is [%v]", err)
+			return false, fmt.Errorf("create bucket failed, error is [%v]", err)
 		}
 	}
 
 	log.AddContext(ctx).Infof("create bucket [%s] successfully", bucketName)
-	return nil
+	return true, nil
 }
 
 // DeleteBucket function deletes a bucket with the given name
@@ -92,3 +100,22 @@ func (s *S3Agent) CheckBucketExist(ctx context.Context, bucketName string) error
 	log.AddContext(ctx).Infof("check bucket [%s] existence successfully", bucketName)
 	return nil
 }
+
+// CheckBucketOwned function check whether the bucket is owned by the account of the agent
+func (s *S3Agent) CheckBucketOwned(ctx context.Context, bucketName string) error {
+	log.AddContext(ctx).Infof("start to check bucket [%s] ownership", bucketName)
+
+	output, err := s.Client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return fmt.Errorf("list buckets failed, error is [%v]", err)
+	}
+
+	for _, bucket := range output.Buckets {
+		if aws.StringValue(bucket.Name) == bucketName {
+			log.AddContext(ctx).Infof("check bucket [%s] ownership successfully", bucketName)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("bucket [%s] is not owned by the account", bucketName)
+}
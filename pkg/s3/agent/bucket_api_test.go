@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
@@ -216,3 +217,85 @@ func Test_S3Agent_CheckBucketExist_Failed(t *testing.T) {
 		mock.Reset()
 	})
 }
+
+func Test_S3Agent_CreateBucketIfAbsent_AlreadyOwned(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	bucketName := "test-bucket"
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "CreateBucket",
+		func(_ *s3.S3, input *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+			return nil, awserr.New(s3.ErrCodeBucketAlreadyOwnedByYou, "already owned", nil)
+		})
+
+	// act
+	created, gotErr := s3Agent.CreateBucketIfAbsent(context.TODO(), bucketName, "", "")
+
+	// assert
+	if gotErr != nil || created {
+		t.Errorf("Test_S3Agent_CreateBucketIfAbsent_AlreadyOwned failed, created= [%v], gotErr= [%v], "+
+			"want created= false, wantErr= nil", created, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_CheckBucketOwned_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	bucketName := "test-bucket"
+	output := &s3.ListBucketsOutput{Buckets: []*s3.Bucket{{Name: aws.String("other")}, {Name: aws.String(bucketName)}}}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "ListBuckets",
+		func(_ *s3.S3, input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+			return output, nil
+		})
+
+	// act
+	gotErr := s3Agent.CheckBucketOwned(context.TODO(), bucketName)
+
+	// assert
+	if gotErr != nil {
+		t.Errorf("Test_S3Agent_CheckBucketOwned_Success failed, gotErr= [%v], wantErr= nil", gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_CheckBucketOwned_NotOwned(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	bucketName := "test-bucket"
+	output := &s3.ListBucketsOutput{Buckets: []*s3.Bucket{{Name: aws.String("other")}}}
+	wantErr := fmt.Errorf("bucket [%s] is not owned by the account", bucketName)
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "ListBuckets",
+		func(_ *s3.S3, input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+			return output, nil
+		})
+
+	// act
+	gotErr := s3Agent.CheckBucketOwned(context.TODO(), bucketName)
+
+	// assert
+	if !reflect.DeepEqual(gotErr, wantErr) {
+		t.Errorf("Test_S3Agent_CheckBucketOwned_NotOwned failed, gotErr= [%v], wantErr= [%v]", gotErr, wantErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
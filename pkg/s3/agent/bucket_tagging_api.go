@@ -0,0 +1,77 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package agent provides s3 agent and its apis
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	// errCodeNoSuchTagSet is returned when the bucket has no tag
+	errCodeNoSuchTagSet = "NoSuchTagSet"
+)
+
+// GetBucketTags returns the tags of the bucket, it is empty if the bucket has no tag
+func (s *S3Agent) GetBucketTags(ctx context.Context, bucketName string) (map[string]string, error) {
+	log.AddContext(ctx).Infof("start to get bucket [%s] tags", bucketName)
+
+	tags := make(map[string]string)
+	output, err := s.Client.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == errCodeNoSuchTagSet {
+			log.AddContext(ctx).Infof("bucket [%s] has no tag", bucketName)
+			return tags, nil
+		}
+		return nil, fmt.Errorf("get bucket tagging failed, error is [%v]", err)
+	}
+
+	for _, tag := range output.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	log.AddContext(ctx).Infof("get bucket [%s] tags successfully", bucketName)
+	return tags, nil
+}
+
+// PutBucketTags replaces the tags of the bucket
+func (s *S3Agent) PutBucketTags(ctx context.Context, bucketName string, tags map[string]string) error {
+	log.AddContext(ctx).Infof("start to put bucket [%s] tags [%v]", bucketName, tags)
+
+	tagSet := make([]*s3.Tag, 0, len(tags))
+	for key, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	_, err := s.Client.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucketName),
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket tagging failed, error is [%v]", err)
+	}
+
+	log.AddContext(ctx).Infof("put bucket [%s] tags successfully", bucketName)
+	return nil
+}
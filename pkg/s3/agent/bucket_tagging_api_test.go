@@ -0,0 +1,112 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026-2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package agent provides s3 agent and its apis
+package agent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_S3Agent_GetBucketTags_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	bucketName := "test-bucket"
+	output := &s3.GetBucketTaggingOutput{TagSet: []*s3.Tag{{Key: aws.String("owner"), Value: aws.String("team-a")}}}
+	want := map[string]string{"owner": "team-a"}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "GetBucketTagging",
+		func(_ *s3.S3, input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+			return output, nil
+		})
+
+	// act
+	got, gotErr := s3Agent.GetBucketTags(context.TODO(), bucketName)
+
+	// assert
+	if gotErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Test_S3Agent_GetBucketTags_Success failed, got= [%v], gotErr= [%v], want= [%v]",
+			got, gotErr, want)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_GetBucketTags_NoTagSet(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	bucketName := "test-bucket"
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "GetBucketTagging",
+		func(_ *s3.S3, input *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+			return nil, awserr.New(errCodeNoSuchTagSet, "no tag", nil)
+		})
+
+	// act
+	got, gotErr := s3Agent.GetBucketTags(context.TODO(), bucketName)
+
+	// assert
+	if gotErr != nil || len(got) != 0 {
+		t.Errorf("Test_S3Agent_GetBucketTags_NoTagSet failed, got= [%v], gotErr= [%v], want empty tags",
+			got, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_PutBucketTags_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	bucketName := "test-bucket"
+	var gotInput *s3.PutBucketTaggingInput
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "PutBucketTagging",
+		func(_ *s3.S3, input *s3.PutBucketTaggingInput) (*s3.PutBucketTaggingOutput, error) {
+			gotInput = input
+			return nil, nil
+		})
+
+	// act
+	gotErr := s3Agent.PutBucketTags(context.TODO(), bucketName, map[string]string{"owner": "team-a"})
+
+	// assert
+	if gotErr != nil || len(gotInput.Tagging.TagSet) != 1 ||
+		aws.StringValue(gotInput.Tagging.TagSet[0].Key) != "owner" {
+		t.Errorf("Test_S3Agent_PutBucketTags_Success failed, gotInput= [%v], gotErr= [%v]", gotInput, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
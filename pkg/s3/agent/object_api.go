@@ -0,0 +1,227 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package agent provides s3 agent and its apis
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	// objects larger than maxCopyObjectSize are copied by parts, which is the size limit of CopyObject
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	copyPartSize      = 512 * 1024 * 1024
+)
+
+// ObjectInfo is the key and size of an object
+type ObjectInfo struct {
+	Key  string
+	Size int64
+}
+
+// CopyProgress is the progress of copying the objects of a bucket,
+// the totals only count the objects listed so far until Listed is true
+type CopyProgress struct {
+	CopiedObjects int
+	TotalObjects  int
+	CopiedBytes   int64
+	TotalBytes    int64
+	Listed        bool
+}
+
+// ListObjectPages lists the objects of the bucket page by page, handle is called with each page
+// and the listing stops if it returns false
+func (s *S3Agent) ListObjectPages(ctx context.Context, bucketName string,
+	handle func(objects []ObjectInfo) bool) error {
+	log.AddContext(ctx).Infof("start to list objects of bucket [%s]", bucketName)
+
+	var listed int
+	err := s.Client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{Bucket: aws.String(bucketName)},
+		func(output *s3.ListObjectsV2Output, _ bool) bool {
+			objects := make([]ObjectInfo, 0, len(output.Contents))
+			for _, object := range output.Contents {
+				objects = append(objects, ObjectInfo{Key: aws.StringValue(object.Key),
+					Size: aws.Int64Value(object.Size)})
+			}
+			listed += len(objects)
+			return handle(objects)
+		})
+	if err != nil {
+		return fmt.Errorf("list objects of bucket [%s] failed, error is [%v]", bucketName, err)
+	}
+
+	log.AddContext(ctx).Infof("list [%d] objects of bucket [%s] successfully", listed, bucketName)
+	return nil
+}
+
+// CopyObject copies the object to the destination bucket on the server side,
+// the object larger than the limit of CopyObject is copied by parts
+func (s *S3Agent) CopyObject(ctx context.Context, srcBucket, dstBucket string, object ObjectInfo) error {
+	if object.Size > maxCopyObjectSize {
+		return s.copyObjectByParts(ctx, srcBucket, dstBucket, object)
+	}
+
+	_, err := s.Client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(object.Key),
+		CopySource: aws.String(copySource(srcBucket, object.Key)),
+	})
+	if err != nil {
+		return fmt.Errorf("copy object [%s] from bucket [%s] to bucket [%s] failed, error is [%v]",
+			object.Key, srcBucket, dstBucket, err)
+	}
+
+	return nil
+}
+
+func (s *S3Agent) copyObjectByParts(ctx context.Context, srcBucket, dstBucket string, object ObjectInfo) error {
+	upload, err := s.Client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(dstBucket),
+		Key:    aws.String(object.Key),
+	})
+	if err != nil {
+		return fmt.Errorf("create multipart upload of object [%s] in bucket [%s] failed, error is [%v]",
+			object.Key, dstBucket, err)
+	}
+
+	var parts []*s3.CompletedPart
+	for offset, number := int64(0), int64(1); offset < object.Size; offset, number = offset+copyPartSize, number+1 {
+		end := min(offset+copyPartSize, object.Size) - 1
+		output, err := s.Client.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(dstBucket),
+			Key:             aws.String(object.Key),
+			UploadId:        upload.UploadId,
+			PartNumber:      aws.Int64(number),
+			CopySource:      aws.String(copySource(srcBucket, object.Key)),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", offset, end)),
+		})
+		if err != nil {
+			s.abortMultipartUpload(ctx, dstBucket, object.Key, upload.UploadId)
+			return fmt.Errorf("copy part [%d] of object [%s] from bucket [%s] failed, error is [%v]",
+				number, object.Key, srcBucket, err)
+		}
+		parts = append(parts, &s3.CompletedPart{ETag: output.CopyPartResult.ETag, PartNumber: aws.Int64(number)})
+	}
+
+	_, err = s.Client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(dstBucket),
+		Key:             aws.String(object.Key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.abortMultipartUpload(ctx, dstBucket, object.Key, upload.UploadId)
+		return fmt.Errorf("complete multipart upload of object [%s] in bucket [%s] failed, error is [%v]",
+			object.Key, dstBucket, err)
+	}
+
+	return nil
+}
+
+func (s *S3Agent) abortMultipartUpload(ctx context.Context, bucketName, key string, uploadId *string) {
+	_, err := s.Client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(key),
+		UploadId: uploadId,
+	})
+	if err != nil {
+		log.AddContext(ctx).Warningf("abort multipart upload of object [%s] in bucket [%s] failed, error is [%v]",
+			key, bucketName, err)
+	}
+}
+
+// CopyBucketObjects copies all objects of the source bucket to the destination bucket on the server side,
+// at most parallelism objects are copied at the same time, and report is called after each object is copied.
+// The objects are copied while the pages are listed, so the objects of the bucket are never all kept in memory.
+func (s *S3Agent) CopyBucketObjects(ctx context.Context, srcBucket, dstBucket string, parallelism int,
+	report func(progress CopyProgress)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+		progress CopyProgress
+	)
+	queue := make(chan ObjectInfo)
+	for i := 0; i < max(parallelism, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for object := range queue {
+				err := s.CopyObject(ctx, srcBucket, dstBucket, object)
+
+				mutex.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				} else if err == nil {
+					progress.CopiedObjects++
+					progress.CopiedBytes += object.Size
+					report(progress)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	listErr := s.ListObjectPages(ctx, srcBucket, func(objects []ObjectInfo) bool {
+		mutex.Lock()
+		for _, object := range objects {
+			progress.TotalObjects++
+			progress.TotalBytes += object.Size
+		}
+		report(progress)
+		mutex.Unlock()
+
+		for _, object := range objects {
+			select {
+			case queue <- object:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	})
+	if listErr == nil && ctx.Err() == nil {
+		mutex.Lock()
+		progress.Listed = true
+		report(progress)
+		mutex.Unlock()
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if listErr != nil {
+		return listErr
+	}
+	return ctx.Err()
+}
+
+func copySource(bucketName, key string) string {
+	return (&url.URL{Path: bucketName + "/" + key}).EscapedPath()
+}
@@ -0,0 +1,217 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026-2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package agent provides s3 agent and its apis
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_S3Agent_CopyObject_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	var gotInput *s3.CopyObjectInput
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "CopyObjectWithContext",
+		func(_ *s3.S3, _ aws.Context, input *s3.CopyObjectInput, _ ...request.Option) (*s3.CopyObjectOutput,
+			error) {
+			gotInput = input
+			return &s3.CopyObjectOutput{}, nil
+		})
+
+	// act
+	gotErr := s3Agent.CopyObject(context.TODO(), "src-bucket", "dst-bucket", ObjectInfo{Key: "dir/a b.txt"})
+
+	// assert
+	if gotErr != nil || aws.StringValue(gotInput.CopySource) != "src-bucket/dir/a%20b.txt" ||
+		aws.StringValue(gotInput.Bucket) != "dst-bucket" {
+		t.Errorf("Test_S3Agent_CopyObject_Success failed, gotInput= [%v], gotErr= [%v]", gotInput, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_CopyObject_LargeObjectByParts(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	object := ObjectInfo{Key: "large", Size: maxCopyObjectSize + 1}
+	var gotRanges []string
+	var gotParts int
+
+	// mock
+	mock := gomonkey.ApplyMethodReturn(s3Client, "CreateMultipartUploadWithContext",
+		&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-id")}, nil)
+	mock.ApplyMethod(reflect.TypeOf(s3Client), "UploadPartCopyWithContext",
+		func(_ *s3.S3, _ aws.Context, input *s3.UploadPartCopyInput, _ ...request.Option) (*s3.UploadPartCopyOutput,
+			error) {
+			gotRanges = append(gotRanges, aws.StringValue(input.CopySourceRange))
+			return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("etag")}}, nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(s3Client), "CompleteMultipartUploadWithContext",
+		func(_ *s3.S3, _ aws.Context, input *s3.CompleteMultipartUploadInput,
+			_ ...request.Option) (*s3.CompleteMultipartUploadOutput, error) {
+			gotParts = len(input.MultipartUpload.Parts)
+			return &s3.CompleteMultipartUploadOutput{}, nil
+		})
+
+	// act
+	gotErr := s3Agent.CopyObject(context.TODO(), "src-bucket", "dst-bucket", object)
+
+	// assert
+	wantParts := int(maxCopyObjectSize/copyPartSize) + 1
+	if gotErr != nil || gotParts != wantParts || len(gotRanges) != wantParts ||
+		gotRanges[wantParts-1] != "bytes=5368709120-5368709120" {
+		t.Errorf("Test_S3Agent_CopyObject_LargeObjectByParts failed, gotParts= [%d], gotRanges= [%v], "+
+			"gotErr= [%v], wantParts= [%d]", gotParts, gotRanges, gotErr, wantParts)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_ListObjectPages_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := S3Agent{Client: s3Client}
+	outputs := []*s3.ListObjectsV2Output{
+		{Contents: []*s3.Object{{Key: aws.String("a"), Size: aws.Int64(1)}}},
+		{Contents: []*s3.Object{{Key: aws.String("b"), Size: aws.Int64(2)}}},
+	}
+	var gotPages [][]ObjectInfo
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Client), "ListObjectsV2PagesWithContext",
+		func(_ *s3.S3, _ aws.Context, _ *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool,
+			_ ...request.Option) error {
+			for i, output := range outputs {
+				if !fn(output, i == len(outputs)-1) {
+					break
+				}
+			}
+			return nil
+		})
+
+	// act
+	gotErr := s3Agent.ListObjectPages(context.TODO(), "src-bucket", func(objects []ObjectInfo) bool {
+		gotPages = append(gotPages, objects)
+		return len(gotPages) < 1
+	})
+
+	// assert
+	want := [][]ObjectInfo{{{Key: "a", Size: 1}}}
+	if gotErr != nil || !reflect.DeepEqual(gotPages, want) {
+		t.Errorf("Test_S3Agent_ListObjectPages_Success failed, gotPages= [%v], gotErr= [%v]", gotPages, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_CopyBucketObjects_Success(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := &S3Agent{Client: s3Client}
+	pages := [][]ObjectInfo{{{Key: "a", Size: 1}, {Key: "b", Size: 2}}, {{Key: "c", Size: 3}}}
+	var mutex sync.Mutex
+	var copied []string
+	var last CopyProgress
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Agent), "ListObjectPages",
+		func(_ *S3Agent, _ context.Context, _ string, handle func([]ObjectInfo) bool) error {
+			for _, page := range pages {
+				if !handle(page) {
+					break
+				}
+			}
+			return nil
+		})
+	mock.ApplyMethod(reflect.TypeOf(s3Agent), "CopyObject",
+		func(_ *S3Agent, _ context.Context, _, _ string, object ObjectInfo) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			copied = append(copied, object.Key)
+			return nil
+		})
+
+	// act
+	gotErr := s3Agent.CopyBucketObjects(context.TODO(), "src-bucket", "dst-bucket", 2,
+		func(progress CopyProgress) { last = progress })
+
+	// assert
+	want := CopyProgress{CopiedObjects: 3, TotalObjects: 3, CopiedBytes: 6, TotalBytes: 6, Listed: true}
+	if gotErr != nil || len(copied) != 3 || last != want {
+		t.Errorf("Test_S3Agent_CopyBucketObjects_Success failed, copied= [%v], last= [%+v], gotErr= [%v]",
+			copied, last, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func Test_S3Agent_CopyBucketObjects_CopyFailed(t *testing.T) {
+	// arrange
+	s3Client := &s3.S3{}
+	s3Agent := &S3Agent{Client: s3Client}
+	pages := [][]ObjectInfo{{{Key: "a", Size: 1}, {Key: "b", Size: 2}}}
+	copyErr := errors.New("copy failed")
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(s3Agent), "ListObjectPages",
+		func(_ *S3Agent, _ context.Context, _ string, handle func([]ObjectInfo) bool) error {
+			for _, page := range pages {
+				if !handle(page) {
+					break
+				}
+			}
+			return nil
+		})
+	mock.ApplyMethodReturn(s3Agent, "CopyObject", copyErr)
+
+	// act
+	gotErr := s3Agent.CopyBucketObjects(context.TODO(), "src-bucket", "dst-bucket", 1,
+		func(progress CopyProgress) {})
+
+	// assert
+	if !reflect.DeepEqual(gotErr, copyErr) {
+		t.Errorf("Test_S3Agent_CopyBucketObjects_CopyFailed failed, gotErr= [%v], wantErr= [%v]", gotErr, copyErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
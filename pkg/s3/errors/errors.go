@@ -23,6 +23,12 @@ const (
 
 	// ErrNoSuchBucket is the s3 err about bucket not exist
 	ErrNoSuchBucket = s3.ErrCodeNoSuchBucket
+
+	// ErrNotImplemented is the s3 err about the api not supported by the backend
+	ErrNotImplemented = "NotImplemented"
+
+	// ErrMethodNotAllowed is the s3 err about the api not allowed on the backend
+	ErrMethodNotAllowed = "MethodNotAllowed"
 )
 
 var (
@@ -0,0 +1,61 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ConditionDateLessThan is the condition operator satisfied by the dates before the condition value
+	ConditionDateLessThan = "DateLessThan"
+)
+
+// Condition is the condition block of a statement,
+// it maps condition operator to condition keys and their values
+type Condition map[string]map[string]ConditionValues
+
+// ConditionValues is the values of a condition key.
+// A single value may be written as a plain json string, number or bool.
+type ConditionValues []string
+
+// UnmarshalJSON accepts both the single value and the list value of condition
+func (cv *ConditionValues) UnmarshalJSON(data []byte) error {
+	var list []interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		var single interface{}
+		if err = json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		list = []interface{}{single}
+	}
+
+	values := make(ConditionValues, 0, len(list))
+	for _, v := range list {
+		switch value := v.(type) {
+		case string:
+			values = append(values, value)
+		case bool, float64:
+			values = append(values, fmt.Sprint(value))
+		default:
+			return fmt.Errorf("unsupported condition value [%v]", v)
+		}
+	}
+
+	*cv = values
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	// ConsolidatedSidPrefix is the sid prefix of statements shared by all principals with the same access profile,
+	// the sid is the prefix followed by the access profile hash, likes 'cosi-profile-0123456789abcdef'
+	ConsolidatedSidPrefix = "cosi-profile-"
+
+	// profileHashLength is the length of access profile hash used in consolidated sid
+	profileHashLength = 16
+
+	// userArnSeparator separates the account and the user name in user arn,
+	// arn format likes 'arn:aws:iam::{accountId}:user/{userName}'
+	userArnSeparator = ":user/"
+)
+
+// IsConsolidatedSid checks whether the sid belongs to a consolidated statement, which is the prefix followed by
+// exactly the access profile hash, so the hand-written statements are not mistaken for the driver-owned ones
+func IsConsolidatedSid(sid string) bool {
+	hash, found := strings.CutPrefix(sid, ConsolidatedSidPrefix)
+	if !found || len(hash) != profileHashLength {
+		return false
+	}
+
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UserNameFromArn returns the user name of arn format user, returns empty string if arn is not a user arn.
+// The arn may contain the IAM path of user, e.g. 'arn:aws:iam::account:user/cosi/{path}/{userName}'.
+func UserNameFromArn(userArn string) string {
+	if !strings.Contains(userArn, userArnSeparator) {
+		return ""
+	}
+
+	return userArn[strings.LastIndex(userArn, "/")+1:]
+}
+
+// ProfileSid returns the consolidated sid of statement,
+// which is determined by the effect, actions, resources and condition of the statement.
+func (ps *Statement) ProfileSid() string {
+	actions := make([]string, 0, len(ps.Action))
+	for _, a := range ps.Action {
+		actions = append(actions, string(a))
+	}
+	sort.Strings(actions)
+
+	resources := append([]string{}, ps.Resource...)
+	sort.Strings(resources)
+
+	// json marshal of map is ordered by key, so the profile is stable
+	profile, _ := json.Marshal(struct {
+		Effect    effect
+		Action    []string
+		Resource  []string
+		Condition Condition
+	}{ps.Effect, actions, resources, ps.Condition})
+
+	sum := sha256.Sum256(profile)
+	return ConsolidatedSidPrefix + hex.EncodeToString(sum[:])[:profileHashLength]
+}
+
+// ConsolidateStatement adds the principals of statement to the consolidated statement with the same access profile.
+// The principals are removed from other consolidated statements first, and the legacy statement whose sid is
+// the same as the statement is removed, so that each principal is granted by exactly one statement.
+// Return a new bucket policy.
+func (bp *BucketPolicy) ConsolidateStatement(statement Statement) *BucketPolicy {
+	principals := statement.Principal[awsPrinciple]
+	newBp := bp.RemoveStatement(statement.Sid).removePrincipals(func(principal string) bool {
+		for _, p := range principals {
+			if p == principal {
+				return true
+			}
+		}
+		return false
+	})
+
+	sid := statement.ProfileSid()
+	for i, ps := range newBp.Statement {
+		if ps.Sid == sid {
+			newBp.Statement[i] = ps.withPrincipals(principals...)
+			return newBp
+		}
+	}
+
+	consolidated := statement.withPrincipals()
+	consolidated.Sid = sid
+	newBp.Statement = append(newBp.Statement, consolidated)
+	return newBp
+}
+
+// RemoveUser removes all statements granting the user, including the legacy statement whose sid is the user name
+// and the user principal in consolidated statements. Consolidated statements without principal are removed.
+// Return a new bucket policy.
+func (bp *BucketPolicy) RemoveUser(userName string) *BucketPolicy {
+	return bp.RemoveStatement(userName).removePrincipals(func(principal string) bool {
+		return UserNameFromArn(principal) == userName
+	})
+}
+
+// removePrincipals removes the matched principals from consolidated statements and returns a new bucket policy
+func (bp *BucketPolicy) removePrincipals(match func(principal string) bool) *BucketPolicy {
+	newBp := bp.withoutStatements()
+	for _, ps := range bp.Statement {
+		if !IsConsolidatedSid(ps.Sid) {
+			newBp.Statement = append(newBp.Statement, ps)
+			continue
+		}
+
+		var remained []string
+		for _, principal := range ps.Principal[awsPrinciple] {
+			if !match(principal) {
+				remained = append(remained, principal)
+			}
+		}
+
+		if len(remained) == len(ps.Principal[awsPrinciple]) {
+			newBp.Statement = append(newBp.Statement, ps)
+		} else if len(remained) > 0 {
+			edited := ps.withPrincipals()
+			edited.Principal[awsPrinciple] = remained
+			newBp.Statement = append(newBp.Statement, edited)
+		}
+	}
+
+	return newBp
+}
+
+// withPrincipals returns a copy of statement with additional principals, duplicated principals are ignored
+func (ps Statement) withPrincipals(userArns ...string) Statement {
+	principal := make(map[string][]string, len(ps.Principal))
+	for k, v := range ps.Principal {
+		principal[k] = append([]string{}, v...)
+	}
+
+	for _, u := range userArns {
+		exist := false
+		for _, p := range principal[awsPrinciple] {
+			if p == u {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			principal[awsPrinciple] = append(principal[awsPrinciple], u)
+		}
+	}
+
+	ps.Principal = principal
+	return ps
+}
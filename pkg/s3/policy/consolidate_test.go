@@ -0,0 +1,106 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildUserStatement(userName string, actions []action) *Statement {
+	return NewStatementBuilder().
+		WithSID(userName).
+		WithEffect(EffectAllow).
+		WithPrincipals("arn:aws:iam::domain-id:user/" + userName).
+		WithActions(actions).
+		WithResources("bucket-name").
+		WithSubResources("bucket-name").
+		Build()
+}
+
+func Test_BucketPolicy_ConsolidateStatement_SameProfile(t *testing.T) {
+	// arrange
+	bp := NewBucketPolicy()
+
+	// act
+	bp = bp.ConsolidateStatement(*buildUserStatement("user-1", AllowedReadActions))
+	bp = bp.ConsolidateStatement(*buildUserStatement("user-2", AllowedReadActions))
+
+	// assert
+	assert.Len(t, bp.Statement, 1)
+	assert.True(t, IsConsolidatedSid(bp.Statement[0].Sid))
+	assert.Equal(t, []string{"arn:aws:iam::domain-id:user/user-1", "arn:aws:iam::domain-id:user/user-2"},
+		bp.Statement[0].Principal[awsPrinciple])
+}
+
+func Test_BucketPolicy_ConsolidateStatement_ProfileChanged(t *testing.T) {
+	// arrange
+	legacy := buildUserStatement("user-1", AllowedReadWriteActions)
+	bp := NewBucketPolicy(*legacy)
+	bp = bp.ConsolidateStatement(*buildUserStatement("user-2", AllowedReadActions))
+
+	// act
+	bp = bp.ConsolidateStatement(*buildUserStatement("user-1", AllowedReadActions))
+
+	// assert
+	assert.Len(t, bp.Statement, 1)
+	assert.Equal(t, buildUserStatement("user-1", AllowedReadActions).ProfileSid(), bp.Statement[0].Sid)
+	assert.Len(t, bp.Statement[0].Principal[awsPrinciple], 2)
+}
+
+func Test_BucketPolicy_RemoveUser(t *testing.T) {
+	// arrange
+	bp := NewBucketPolicy(*buildUserStatement("user-0", AllowedReadActions))
+	bp = bp.ConsolidateStatement(*buildUserStatement("user-1", AllowedReadActions))
+	bp = bp.ConsolidateStatement(*buildUserStatement("user-2", AllowedReadWriteActions))
+	origin := bp.ConsolidateStatement(*buildUserStatement("user-3", AllowedReadActions))
+
+	// act
+	gotBp := origin.RemoveUser("user-0").RemoveUser("user-2").RemoveUser("user-3")
+
+	// assert
+	assert.Len(t, gotBp.Statement, 1)
+	assert.Equal(t, []string{"arn:aws:iam::domain-id:user/user-1"}, gotBp.Statement[0].Principal[awsPrinciple])
+	assert.Len(t, origin.Statement, 3)
+	assert.Len(t, origin.Statement[1].Principal[awsPrinciple], 2)
+}
+
+func Test_UserNameFromArn(t *testing.T) {
+	assert.Equal(t, "user-name", UserNameFromArn("arn:aws:iam::domain-id:user/user-name"))
+	assert.Equal(t, "ba-uid-1", UserNameFromArn("arn:aws:iam::domain-id:user/cosi/c/d/ns/name/ba-uid-1"))
+	assert.Equal(t, "", UserNameFromArn("*"))
+}
+
+func Test_IsConsolidatedSid(t *testing.T) {
+	assert.True(t, IsConsolidatedSid(buildUserStatement("user-1", AllowedReadActions).ProfileSid()))
+	assert.False(t, IsConsolidatedSid("cosi-readers"))
+	assert.False(t, IsConsolidatedSid("cosi-profile-readers"))
+	assert.False(t, IsConsolidatedSid("cosi-profile-0123456789ABCDEF"))
+	assert.False(t, IsConsolidatedSid("cosi-profile-0123456789abcdef0"))
+}
+
+func Test_BucketPolicy_RemoveUser_HandWrittenStatement(t *testing.T) {
+	// arrange
+	handWritten := buildUserStatement("user-1", AllowedReadActions).WithSID("cosi-readers")
+	bp := NewBucketPolicy(*handWritten)
+
+	// act
+	gotBp := bp.RemoveUser("user-1")
+
+	// assert
+	assert.Equal(t, bp, gotBp)
+}
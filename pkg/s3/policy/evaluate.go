@@ -0,0 +1,323 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decision is the result of evaluating a request against a bucket policy
+type Decision string
+
+const (
+	// DecisionAllow means at least one statement allows the request and no statement denies it
+	DecisionAllow Decision = "Allow"
+
+	// DecisionExplicitDeny means at least one statement denies the request
+	DecisionExplicitDeny Decision = "ExplicitDeny"
+
+	// DecisionImplicitDeny means no statement matches the request
+	DecisionImplicitDeny Decision = "ImplicitDeny"
+
+	// AnonymousPrincipal is the principal matching everyone, including anonymous users
+	AnonymousPrincipal = "*"
+
+	// ConditionKeyCurrentTime is the condition key of request time, the format is RFC3339
+	ConditionKeyCurrentTime = "aws:CurrentTime"
+
+	ifExistsSuffix = "IfExists"
+)
+
+// Request is an access request to be evaluated, e.g.
+// can principal 'arn:aws:iam::{accountId}:user/{userName}' perform 's3:GetObject' on 'arn:aws:s3:::{bucket}/{key}'
+type Request struct {
+	// Principal is the arn of the requester, AnonymousPrincipal means an anonymous requester
+	Principal string
+
+	// Action is the s3 action, likes 's3:GetObject'
+	Action string
+
+	// Resource is the arn of the requested resource, likes 'arn:aws:s3:::{bucket}/{key}'
+	Resource string
+
+	// Context is the condition keys of the request, likes 's3:prefix' or 'aws:SourceIp'.
+	// 'aws:CurrentTime' defaults to now if it is absent.
+	Context map[string]string
+}
+
+// UnmodeledConstructs lists the constructs of the bucket policy which Evaluate does not model,
+// likes 'NotPrincipal', 'NotAction', 'NotResource' and the principals other than 'AWS'.
+// The decision of Evaluate may be wrong if there are any.
+func (bp *BucketPolicy) UnmodeledConstructs() []string {
+	var constructs []string
+	for _, ps := range bp.Statement {
+		for key := range ps.Unknown {
+			constructs = append(constructs, fmt.Sprintf("statement [%s] field [%s]", ps.Sid, key))
+		}
+		for principalType := range ps.Principal {
+			if principalType != awsPrinciple {
+				constructs = append(constructs, fmt.Sprintf("statement [%s] principal [%s]", ps.Sid, principalType))
+			}
+		}
+	}
+	sort.Strings(constructs)
+
+	return constructs
+}
+
+// Evaluate evaluates the request against the bucket policy.
+// An explicit deny always takes precedence over any allow,
+// and the request is implicitly denied if no statement matches.
+// The constructs listed by UnmodeledConstructs are ignored.
+func (bp *BucketPolicy) Evaluate(req Request) Decision {
+	if _, exist := req.Context[ConditionKeyCurrentTime]; !exist {
+		ctx := map[string]string{ConditionKeyCurrentTime: time.Now().UTC().Format(time.RFC3339)}
+		for k, v := range req.Context {
+			ctx[k] = v
+		}
+		req.Context = ctx
+	}
+
+	decision := DecisionImplicitDeny
+	for _, ps := range bp.Statement {
+		if !ps.matches(req) {
+			continue
+		}
+
+		if ps.Effect == EffectDeny {
+			return DecisionExplicitDeny
+		}
+		if ps.Effect == EffectAllow {
+			decision = DecisionAllow
+		}
+	}
+
+	return decision
+}
+
+// IsAllowed checks whether the request is allowed by the bucket policy
+func (bp *BucketPolicy) IsAllowed(req Request) bool {
+	return bp.Evaluate(req) == DecisionAllow
+}
+
+func (ps *Statement) matches(req Request) bool {
+	return ps.matchesPrincipal(req.Principal) &&
+		ps.matchesAction(req.Action) &&
+		ps.matchesResource(req.Resource) &&
+		ps.Condition.matches(req.Context)
+}
+
+func (ps *Statement) matchesPrincipal(principal string) bool {
+	for _, p := range ps.Principal[awsPrinciple] {
+		if p == AnonymousPrincipal || p == principal {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ps *Statement) matchesAction(a string) bool {
+	for _, pattern := range ps.Action {
+		if wildcardMatch(strings.ToLower(string(pattern)), strings.ToLower(a)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ps *Statement) matchesResource(resource string) bool {
+	for _, pattern := range ps.Resource {
+		if wildcardMatch(pattern, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks all condition operators, all of them must be satisfied.
+// Unsupported operators are never satisfied.
+func (c Condition) matches(ctx map[string]string) bool {
+	for operator, keys := range c {
+		ifExists := strings.HasSuffix(operator, ifExistsSuffix)
+		op := strings.TrimSuffix(operator, ifExistsSuffix)
+		for key, values := range keys {
+			actual, exist := ctx[key]
+			if op == "Null" {
+				if !matchConditionValues("Bool", strconv.FormatBool(!exist), values) {
+					return false
+				}
+				continue
+			}
+
+			if !exist {
+				// negated operators and 'IfExists' operators are satisfied by missing keys
+				if ifExists || strings.Contains(op, "Not") {
+					continue
+				}
+				return false
+			}
+
+			if !matchConditionValues(op, actual, values) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchConditionValues checks whether the actual value matches any of condition values
+func matchConditionValues(op, actual string, values ConditionValues) bool {
+	negated := false
+	switch op {
+	case "StringNotEquals", "StringNotEqualsIgnoreCase", "StringNotLike", "NumericNotEquals",
+		"DateNotEquals", "NotIpAddress":
+		negated = true
+		op = strings.Replace(op, "Not", "", 1)
+	}
+
+	var matched bool
+	for _, expected := range values {
+		ok, supported := compareConditionValue(op, actual, expected)
+		if !supported {
+			return false
+		}
+		if ok {
+			matched = true
+			break
+		}
+	}
+
+	return matched != negated
+}
+
+func compareConditionValue(op, actual, expected string) (bool, bool) {
+	switch op {
+	case "StringEquals":
+		return actual == expected, true
+	case "StringEqualsIgnoreCase":
+		return strings.EqualFold(actual, expected), true
+	case "StringLike":
+		return wildcardMatch(expected, actual), true
+	case "Bool":
+		return strings.EqualFold(actual, expected), true
+	case "IpAddress":
+		return matchIpAddress(actual, expected), true
+	}
+
+	if cmp, ok := compareOrdered(op, actual, expected); ok {
+		return cmp, true
+	}
+
+	return false, false
+}
+
+// matchIpAddress checks whether the ip is in the expected CIDR, the expected bare ip likes '10.0.0.1' is
+// regarded as the single address, as AWS does
+func matchIpAddress(actual, expected string) bool {
+	ip := net.ParseIP(actual)
+	if ip == nil {
+		return false
+	}
+
+	if !strings.Contains(expected, "/") {
+		expectedIp := net.ParseIP(expected)
+		return expectedIp != nil && expectedIp.Equal(ip)
+	}
+
+	_, ipNet, err := net.ParseCIDR(expected)
+	if err != nil {
+		return false
+	}
+
+	return ipNet.Contains(ip)
+}
+
+// compareOrdered compares numeric and date condition values
+func compareOrdered(op, actual, expected string) (bool, bool) {
+	var diff float64
+	switch {
+	case strings.HasPrefix(op, "Numeric"):
+		a, errA := strconv.ParseFloat(actual, 64)
+		e, errE := strconv.ParseFloat(expected, 64)
+		if errA != nil || errE != nil {
+			return false, true
+		}
+		diff = a - e
+		op = strings.TrimPrefix(op, "Numeric")
+	case strings.HasPrefix(op, "Date"):
+		a, errA := time.Parse(time.RFC3339, actual)
+		e, errE := time.Parse(time.RFC3339, expected)
+		if errA != nil || errE != nil {
+			return false, true
+		}
+		diff = float64(a.Sub(e))
+		op = strings.TrimPrefix(op, "Date")
+	default:
+		return false, false
+	}
+
+	switch op {
+	case "Equals":
+		return diff == 0, true
+	case "LessThan":
+		return diff < 0, true
+	case "LessThanEquals":
+		return diff <= 0, true
+	case "GreaterThan":
+		return diff > 0, true
+	case "GreaterThanEquals":
+		return diff >= 0, true
+	default:
+		return false, false
+	}
+}
+
+// wildcardMatch matches the value with pattern, '*' matches any sequence and '?' matches any single character
+func wildcardMatch(pattern, value string) bool {
+	p, v := 0, 0
+	starIndex, matchIndex := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			starIndex, matchIndex = p, v
+			p++
+		case starIndex >= 0:
+			p = starIndex + 1
+			matchIndex++
+			v = matchIndex
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
@@ -0,0 +1,136 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testUserArn   = "arn:aws:iam::domain-id:user/user-1"
+	testBucketArn = "arn:aws:s3:::bucket-name"
+)
+
+func Test_BucketPolicy_Evaluate_Allow(t *testing.T) {
+	// arrange
+	bp := NewBucketPolicy(*buildUserStatement("user-1", AllowedReadActions))
+
+	// act
+	gotObject := bp.Evaluate(Request{Principal: testUserArn, Action: "s3:GetObject", Resource: testBucketArn + "/a/b"})
+	gotPut := bp.Evaluate(Request{Principal: testUserArn, Action: "s3:PutObject", Resource: testBucketArn + "/a/b"})
+	gotOther := bp.Evaluate(Request{Principal: "arn:aws:iam::domain-id:user/user-2", Action: "s3:GetObject",
+		Resource: testBucketArn + "/a/b"})
+
+	// assert
+	assert.Equal(t, DecisionAllow, gotObject)
+	assert.Equal(t, DecisionImplicitDeny, gotPut)
+	assert.Equal(t, DecisionImplicitDeny, gotOther)
+}
+
+func Test_BucketPolicy_Evaluate_ExplicitDenyTakesPrecedence(t *testing.T) {
+	// arrange
+	deny := NewStatementBuilder().WithSID("deny").WithEffect(EffectDeny).WithPrincipals(AnonymousPrincipal).
+		WithActions([]action{"s3:Get*"}).WithResources("bucket-name").WithSubResources("bucket-name").Build()
+	bp := NewBucketPolicy(*buildUserStatement("user-1", AllowedReadActions), *deny)
+
+	// act
+	got := bp.Evaluate(Request{Principal: testUserArn, Action: "s3:GetObject", Resource: testBucketArn + "/key"})
+
+	// assert
+	assert.Equal(t, DecisionExplicitDeny, got)
+	assert.False(t, bp.IsAllowed(Request{Principal: testUserArn, Action: "s3:GetObject",
+		Resource: testBucketArn + "/key"}))
+}
+
+func Test_BucketPolicy_Evaluate_Condition(t *testing.T) {
+	// arrange
+	statement := buildUserStatement("user-1", AllowedReadActions)
+	statement.Condition = Condition{
+		"DateLessThan":       {ConditionKeyCurrentTime: {"2030-01-01T00:00:00Z"}},
+		"StringLikeIfExists": {"s3:prefix": {"home/*"}},
+	}
+	bp := NewBucketPolicy(*statement)
+	req := Request{Principal: testUserArn, Action: "s3:ListBucket", Resource: testBucketArn}
+
+	// act
+	gotNoPrefix := bp.Evaluate(req)
+	req.Context = map[string]string{"s3:prefix": "home/user-1/"}
+	gotPrefix := bp.Evaluate(req)
+	req.Context = map[string]string{"s3:prefix": "etc/"}
+	gotOtherPrefix := bp.Evaluate(req)
+	req.Context = map[string]string{ConditionKeyCurrentTime: "2031-01-01T00:00:00Z"}
+	gotExpired := bp.Evaluate(req)
+
+	// assert
+	assert.Equal(t, DecisionAllow, gotNoPrefix)
+	assert.Equal(t, DecisionAllow, gotPrefix)
+	assert.Equal(t, DecisionImplicitDeny, gotOtherPrefix)
+	assert.Equal(t, DecisionImplicitDeny, gotExpired)
+}
+
+func Test_Condition_Matches_NullAndIpAddress(t *testing.T) {
+	// arrange
+	condition := Condition{
+		"Null":         {"s3:x-amz-acl": {"true"}},
+		"NotIpAddress": {"aws:SourceIp": {"10.0.0.0/8"}},
+	}
+
+	// act & assert
+	assert.True(t, condition.matches(map[string]string{"aws:SourceIp": "192.168.1.1"}))
+	assert.False(t, condition.matches(map[string]string{"aws:SourceIp": "10.1.1.1"}))
+	assert.False(t, condition.matches(map[string]string{"s3:x-amz-acl": "public-read"}))
+	assert.False(t, Condition{"UnknownOperator": {"key": {"value"}}}.matches(map[string]string{"key": "value"}))
+}
+
+func Test_MatchIpAddress(t *testing.T) {
+	assert.True(t, matchIpAddress("10.1.1.1", "10.0.0.0/8"))
+	assert.True(t, matchIpAddress("10.1.1.1", "10.1.1.1"))
+	assert.True(t, matchIpAddress("::ffff:10.1.1.1", "10.1.1.1"))
+	assert.False(t, matchIpAddress("10.1.1.2", "10.1.1.1"))
+	assert.False(t, matchIpAddress("10.1.1.1", "invalid"))
+	assert.False(t, matchIpAddress("invalid", "10.0.0.0/8"))
+}
+
+func Test_WildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/a/b"))
+	assert.True(t, wildcardMatch("s3:*object", "s3:getobject"))
+	assert.True(t, wildcardMatch("a?c", "abc"))
+	assert.False(t, wildcardMatch("arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket-other/a"))
+	assert.False(t, wildcardMatch("a*b*c", "aXbY"))
+}
+
+func Test_BucketPolicy_UnmodeledConstructs(t *testing.T) {
+	// arrange
+	bp, err := ParsePolicy(`{"Version":"2012-10-17","Statement":[` +
+		`{"Sid":"user-1","Effect":"Allow","Principal":{"AWS":["` + testUserArn + `"]},"Action":["s3:GetObject"],` +
+		`"Resource":["` + testBucketArn + `/*"]},` +
+		`{"Sid":"deny-others","Effect":"Deny","NotPrincipal":{"AWS":["` + testUserArn + `"]},` +
+		`"NotAction":["s3:GetObject"],"Resource":["` + testBucketArn + `/*"]},` +
+		`{"Sid":"service","Effect":"Allow","Principal":{"Service":["logging.s3.amazonaws.com"]},` +
+		`"Action":["s3:PutObject"],"Resource":["` + testBucketArn + `/*"]}]}`)
+	assert.NoError(t, err)
+
+	// act
+	gotConstructs := bp.UnmodeledConstructs()
+	gotModeled := NewBucketPolicy(bp.Statement[0]).UnmodeledConstructs()
+
+	// assert
+	assert.Equal(t, []string{"statement [deny-others] field [NotAction]", "statement [deny-others] field [NotPrincipal]",
+		"statement [service] principal [Service]"}, gotConstructs)
+	assert.Empty(t, gotModeled)
+}
@@ -0,0 +1,201 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LintSeverity is the severity of a lint finding
+type LintSeverity string
+
+const (
+	// LintSeverityError means the policy must not be written
+	LintSeverityError LintSeverity = "Error"
+
+	// LintSeverityWarning means the policy can be written, but should be looked into
+	LintSeverityWarning LintSeverity = "Warning"
+
+	// allActions is the action matching all actions
+	allActions = "*"
+)
+
+// LintFinding is a problem found in bucket policy
+type LintFinding struct {
+	// Severity is the severity of the finding
+	Severity LintSeverity
+
+	// Sid is the sid of the statement which the finding is about
+	Sid string
+
+	// Message describes the finding
+	Message string
+}
+
+// String returns the readable format of finding
+func (f LintFinding) String() string {
+	return fmt.Sprintf("[%s] statement [%s]: %s", f.Severity, f.Sid, f.Message)
+}
+
+// LintOptions is the options of linting a bucket policy
+type LintOptions struct {
+	// BucketName is the bucket which the policy is attached to
+	BucketName string
+
+	// PrincipalExists checks whether the principal still exists, optional.
+	// Principals are not checked if it is nil.
+	PrincipalExists func(principal string) bool
+
+	// AllowPublicRead allows the wildcard principal of statements which only get objects
+	AllowPublicRead bool
+
+	// ChangedSids are the sids of statements changed by the caller, optional.
+	// The findings of other statements are downgraded to warnings, so the existing statements do not block
+	// the unrelated changes. All statements are linted strictly if it is nil.
+	ChangedSids map[string]bool
+}
+
+// Lint checks the bucket policy for:
+// wildcard principals, all actions, resources out of the bucket, duplicate sids and principals no longer exist.
+// Findings which widen the access of Allow statements are errors, others are warnings.
+func (bp *BucketPolicy) Lint(opts LintOptions) []LintFinding {
+	var findings []LintFinding
+	sids := make(map[string]int, len(bp.Statement))
+	for _, ps := range bp.Statement {
+		if ps.Sid != "" {
+			sids[ps.Sid]++
+			if sids[ps.Sid] == 2 {
+				findings = append(findings, LintFinding{Severity: opts.severityOf(ps.Sid, LintSeverityError),
+					Sid: ps.Sid, Message: "duplicate sid"})
+			}
+		}
+
+		for _, f := range ps.lint(opts) {
+			f.Severity = opts.severityOf(ps.Sid, f.Severity)
+			findings = append(findings, f)
+		}
+	}
+
+	return findings
+}
+
+// severityOf downgrades the severity of findings about the statements not changed
+func (opts LintOptions) severityOf(sid string, severity LintSeverity) LintSeverity {
+	if opts.ChangedSids != nil && !opts.ChangedSids[sid] {
+		return LintSeverityWarning
+	}
+
+	return severity
+}
+
+// ChangedSids returns the sids of statements which are added or changed comparing with the origin policy
+func (bp *BucketPolicy) ChangedSids(origin *BucketPolicy) map[string]bool {
+	changed := make(map[string]bool)
+	for _, ps := range bp.Statement {
+		unchanged := false
+		if origin != nil {
+			for _, originPs := range origin.Statement {
+				if reflect.DeepEqual(ps, originPs) {
+					unchanged = true
+					break
+				}
+			}
+		}
+
+		if !unchanged {
+			changed[ps.Sid] = true
+		}
+	}
+
+	return changed
+}
+
+// HasLintError checks whether any finding is error
+func HasLintError(findings []LintFinding) bool {
+	for _, f := range findings {
+		if f.Severity == LintSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ps *Statement) lint(opts LintOptions) []LintFinding {
+	// widening access is serious only for Allow statements, Deny statements only narrow it
+	severity := LintSeverityWarning
+	if ps.Effect == EffectAllow {
+		severity = LintSeverityError
+	}
+
+	var findings []LintFinding
+	for _, principal := range ps.Principal[awsPrinciple] {
+		if principal == AnonymousPrincipal && ps.Effect == EffectAllow {
+			if opts.AllowPublicRead && ps.isReadObjectOnly() {
+				findings = append(findings, LintFinding{Severity: LintSeverityWarning, Sid: ps.Sid,
+					Message: "wildcard principal grants everyone to read objects"})
+			} else {
+				findings = append(findings, LintFinding{Severity: LintSeverityError, Sid: ps.Sid,
+					Message: "wildcard principal grants access to everyone"})
+			}
+			continue
+		}
+
+		if opts.PrincipalExists != nil && principal != AnonymousPrincipal && !opts.PrincipalExists(principal) {
+			findings = append(findings, LintFinding{Severity: LintSeverityWarning, Sid: ps.Sid,
+				Message: fmt.Sprintf("principal [%s] no longer exists", principal)})
+		}
+	}
+
+	for _, a := range ps.Action {
+		if (string(a) == allActions || strings.EqualFold(string(a), actionPrefix+allActions)) &&
+			ps.Effect == EffectAllow {
+			findings = append(findings, LintFinding{Severity: LintSeverityError, Sid: ps.Sid,
+				Message: fmt.Sprintf("action [%s] grants all actions", a)})
+		}
+	}
+
+	if opts.BucketName == "" {
+		return findings
+	}
+
+	bucketArn := fmt.Sprintf(arnResourceFormat, opts.BucketName)
+	for _, r := range ps.Resource {
+		if r != bucketArn && !strings.HasPrefix(r, bucketArn+"/") {
+			findings = append(findings, LintFinding{Severity: severity, Sid: ps.Sid,
+				Message: fmt.Sprintf("resource [%s] is out of bucket [%s]", r, opts.BucketName)})
+		}
+	}
+
+	return findings
+}
+
+// isReadObjectOnly checks whether the statement only allows getting objects
+func (ps *Statement) isReadObjectOnly() bool {
+	if len(ps.Action) == 0 {
+		return false
+	}
+
+	for _, a := range ps.Action {
+		if !strings.EqualFold(string(a), string(getObject)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,112 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BucketPolicy_Lint_Clean(t *testing.T) {
+	// arrange
+	bp := NewBucketPolicy(*buildUserStatement("user-1", AllowedReadActions),
+		*buildUserStatement("user-2", AllowedReadWriteActions))
+
+	// act
+	gotFindings := bp.Lint(LintOptions{BucketName: "bucket-name", PrincipalExists: func(string) bool {
+		return true
+	}})
+
+	// assert
+	assert.Empty(t, gotFindings)
+}
+
+func Test_BucketPolicy_Lint_SeriousFindings(t *testing.T) {
+	// arrange
+	wildcard := NewStatementBuilder().WithSID("wildcard").WithEffect(EffectAllow).
+		WithPrincipals(AnonymousPrincipal).WithActions([]action{"s3:*"}).WithResources("other-bucket").Build()
+	bp := NewBucketPolicy(*buildUserStatement("user-1", AllowedReadActions),
+		*buildUserStatement("user-1", AllowedReadActions), *wildcard)
+
+	// act
+	gotFindings := bp.Lint(LintOptions{BucketName: "bucket-name"})
+
+	// assert
+	assert.True(t, HasLintError(gotFindings))
+	assert.Equal(t, []LintFinding{
+		{Severity: LintSeverityError, Sid: "user-1", Message: "duplicate sid"},
+		{Severity: LintSeverityError, Sid: "wildcard", Message: "wildcard principal grants access to everyone"},
+		{Severity: LintSeverityError, Sid: "wildcard", Message: "action [s3:*] grants all actions"},
+		{Severity: LintSeverityError, Sid: "wildcard",
+			Message: "resource [arn:aws:s3:::other-bucket] is out of bucket [bucket-name]"},
+	}, gotFindings)
+}
+
+func Test_BucketPolicy_Lint_Warnings(t *testing.T) {
+	// arrange
+	deny := NewStatementBuilder().WithSID("deny").WithEffect(EffectDeny).
+		WithPrincipals(AnonymousPrincipal).WithActions([]action{"s3:*"}).WithResources("other-bucket").Build()
+	bp := NewBucketPolicy(*buildUserStatement("user-1", AllowedReadActions), *deny)
+
+	// act
+	gotFindings := bp.Lint(LintOptions{BucketName: "bucket-name", PrincipalExists: func(string) bool {
+		return false
+	}})
+
+	// assert
+	assert.False(t, HasLintError(gotFindings))
+	assert.Equal(t, []LintFinding{
+		{Severity: LintSeverityWarning, Sid: "user-1",
+			Message: "principal [arn:aws:iam::domain-id:user/user-1] no longer exists"},
+		{Severity: LintSeverityWarning, Sid: "deny",
+			Message: "resource [arn:aws:s3:::other-bucket] is out of bucket [bucket-name]"},
+	}, gotFindings)
+}
+
+func Test_BucketPolicy_Lint_AllowPublicRead(t *testing.T) {
+	// arrange
+	bp := NewBucketPolicy(*NewPublicReadStatement("bucket-name", "assets/"))
+
+	// act
+	gotDisallowed := bp.Lint(LintOptions{BucketName: "bucket-name"})
+	gotAllowed := bp.Lint(LintOptions{BucketName: "bucket-name", AllowPublicRead: true})
+
+	// assert
+	assert.True(t, HasLintError(gotDisallowed))
+	assert.Equal(t, []LintFinding{{Severity: LintSeverityWarning, Sid: PublicReadSid,
+		Message: "wildcard principal grants everyone to read objects"}}, gotAllowed)
+}
+
+func Test_BucketPolicy_Lint_ChangedSids(t *testing.T) {
+	// arrange
+	wildcard := NewStatementBuilder().WithSID("wildcard").WithEffect(EffectAllow).
+		WithPrincipals(AnonymousPrincipal).WithActions([]action{"s3:*"}).WithResources("bucket-name").Build()
+	origin := NewBucketPolicy(*wildcard)
+	bp := NewBucketPolicy(*wildcard, *buildUserStatement("user-1", AllowedReadActions))
+
+	// act
+	gotChanged := bp.ChangedSids(origin)
+	gotFindings := bp.Lint(LintOptions{BucketName: "bucket-name", ChangedSids: gotChanged})
+
+	// assert
+	assert.Equal(t, map[string]bool{"user-1": true}, gotChanged)
+	assert.False(t, HasLintError(gotFindings))
+	assert.Equal(t, []LintFinding{
+		{Severity: LintSeverityWarning, Sid: "wildcard", Message: "wildcard principal grants access to everyone"},
+		{Severity: LintSeverityWarning, Sid: "wildcard", Message: "action [s3:*] grants all actions"},
+	}, gotFindings)
+}
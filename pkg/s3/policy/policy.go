@@ -17,6 +17,9 @@ package policy
 
 import "encoding/json"
 
+// MaxPolicySize is the max size in bytes of a bucket policy document accepted by S3
+const MaxPolicySize = 20 * 1024
+
 // BucketPolicy represents set of policy statements for a single bucket.
 type BucketPolicy struct {
 	// Id identifies the bucket policy, optional
@@ -38,6 +41,41 @@ func NewBucketPolicy(ps ...Statement) *BucketPolicy {
 	}
 }
 
+// NewUserPolicy returns a new policy attached to a user with given Statement,
+// the principals are dropped because a user policy always applies to the user it is attached to
+func NewUserPolicy(ps ...Statement) *BucketPolicy {
+	up := NewBucketPolicy()
+	for _, statement := range ps {
+		statement.Principal = nil
+		up.Statement = append(up.Statement, statement)
+	}
+
+	return up
+}
+
+// ParsePolicy is used to unmarshal the json policy document
+func ParsePolicy(document string) (*BucketPolicy, error) {
+	bp := &BucketPolicy{}
+	err := json.Unmarshal([]byte(document), bp)
+	if err != nil {
+		return nil, err
+	}
+
+	return bp, nil
+}
+
+// AttachTo returns a copy of the user policy whose statements apply to the principal,
+// so that it can be evaluated as a bucket policy
+func (bp *BucketPolicy) AttachTo(principal string) *BucketPolicy {
+	newBp := NewBucketPolicy()
+	for _, statement := range bp.Statement {
+		statement.Principal = map[string][]string{awsPrinciple: {principal}}
+		newBp.Statement = append(newBp.Statement, statement)
+	}
+
+	return newBp
+}
+
 // ToJsonString is used to marshal bucket policy to json string format
 func (bp *BucketPolicy) ToJsonString() (string, error) {
 	b, err := json.Marshal(bp)
@@ -71,7 +109,7 @@ func (bp *BucketPolicy) ModifyStatement(newPs Statement) *BucketPolicy {
 // Sid is unique in statements.
 // Return a new bucket policy.
 func (bp *BucketPolicy) RemoveStatement(sid string) *BucketPolicy {
-	newBp := NewBucketPolicy()
+	newBp := bp.withoutStatements()
 	for _, statement := range bp.Statement {
 		if statement.Sid != sid {
 			newBp.Statement = append(newBp.Statement, statement)
@@ -80,3 +118,40 @@ func (bp *BucketPolicy) RemoveStatement(sid string) *BucketPolicy {
 
 	return newBp
 }
+
+// withoutStatements returns a new bucket policy with the same Id and Version but no statement
+func (bp *BucketPolicy) withoutStatements() *BucketPolicy {
+	newBp := NewBucketPolicy()
+	newBp.Id = bp.Id
+	if bp.Version != "" {
+		newBp.Version = bp.Version
+	}
+
+	return newBp
+}
+
+// Size returns the size in bytes of the bucket policy json document
+func (bp *BucketPolicy) Size() (int, error) {
+	policyString, err := bp.ToJsonString()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(policyString), nil
+}
+
+// Principals returns the deduplicated aws principals of all statements in order
+func (bp *BucketPolicy) Principals() []string {
+	var principals []string
+	exist := make(map[string]bool)
+	for _, ps := range bp.Statement {
+		for _, principal := range ps.Principal[awsPrinciple] {
+			if !exist[principal] {
+				exist[principal] = true
+				principals = append(principals, principal)
+			}
+		}
+	}
+
+	return principals
+}
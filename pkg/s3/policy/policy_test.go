@@ -109,3 +109,92 @@ func Test_BucketPolicy_RemoveStatement_TargetNotExist(t *testing.T) {
 		t.Errorf("Test_BucketPolicy_RemoveStatement_TargetNotExist failed, gotBp= [%v], wantBp= [%v]", gotBp, wantBp)
 	}
 }
+
+func Test_BucketPolicy_RemoveStatement_KeepIdAndVersion(t *testing.T) {
+	// arrange
+	bp := &BucketPolicy{Id: "policy-demo", Version: "2008-10-17",
+		Statement: []Statement{{Sid: "sid-test-1"}, {Sid: "sid-test-2"}}}
+	wantBp := &BucketPolicy{Id: "policy-demo", Version: "2008-10-17", Statement: []Statement{{Sid: "sid-test-2"}}}
+
+	// act
+	gotBp := bp.RemoveStatement("sid-test-1")
+
+	// assert
+	if !reflect.DeepEqual(gotBp, wantBp) {
+		t.Errorf("Test_BucketPolicy_RemoveStatement_KeepIdAndVersion failed, gotBp= [%v], wantBp= [%v]",
+			gotBp, wantBp)
+	}
+}
+
+func Test_BucketPolicy_Principals(t *testing.T) {
+	// arrange
+	bp := NewBucketPolicy(
+		*NewStatementBuilder().WithSID("sid-1").WithPrincipals("user-1", "user-2").Build(),
+		*NewStatementBuilder().WithSID("sid-2").WithPrincipals("user-2", "user-3").Build())
+	want := []string{"user-1", "user-2", "user-3"}
+
+	// act
+	got := bp.Principals()
+
+	// assert
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Test_BucketPolicy_Principals failed, got= [%v], want= [%v]", got, want)
+	}
+}
+
+func Test_NewUserPolicy_ParseAndAttach(t *testing.T) {
+	// arrange
+	statement := NewStatementBuilder().WithSID("sid-1").WithEffect(EffectAllow).WithPrincipals("user-1").
+		WithActions(AllowedReadActions).WithResources("bucket-name").Build()
+	wantDocument := `{"Version":"2012-10-17","Statement":[{"Sid":"sid-1","Effect":"Allow",` +
+		`"Action":["s3:GetObject","s3:GetObjectVersion","s3:ListMultipartUploadParts","s3:GetObjectAcl",` +
+		`"s3:GetObjectVersionAcl","s3:ListBucketVersions","s3:ListBucket","s3:ListBucketMultiPartUploads"],` +
+		`"Resource":["arn:aws:s3:::bucket-name"]}]}`
+
+	// act
+	gotDocument, err := NewUserPolicy(*statement).ToJsonString()
+	if err != nil {
+		t.Fatalf("Test_NewUserPolicy_ParseAndAttach failed, marshal error is [%v]", err)
+	}
+	parsed, err := ParsePolicy(gotDocument)
+	if err != nil {
+		t.Fatalf("Test_NewUserPolicy_ParseAndAttach failed, parse error is [%v]", err)
+	}
+	gotBp := parsed.AttachTo("user-2")
+
+	// assert
+	if gotDocument != wantDocument {
+		t.Errorf("Test_NewUserPolicy_ParseAndAttach failed, gotDocument= [%s], wantDocument= [%s]",
+			gotDocument, wantDocument)
+	}
+	if !reflect.DeepEqual(gotBp.Principals(), []string{"user-2"}) || len(statement.Principal[awsPrinciple]) != 1 {
+		t.Errorf("Test_NewUserPolicy_ParseAndAttach failed, gotBp= [%v]", gotBp)
+	}
+}
+
+func Test_ParsePolicy_KeepUnknownFields(t *testing.T) {
+	// arrange
+	document := `{"Version":"2012-10-17","Statement":[{"Sid":"deny-others","Effect":"Deny",` +
+		`"NotPrincipal":{"AWS":["arn:aws:iam::domain-id:user/user-1"]},"Action":["s3:GetObject"],` +
+		`"NotResource":["arn:aws:s3:::bucket-name/public/*"]}]}`
+
+	// act
+	bp, gotErr := ParsePolicy(document)
+	gotDocument, gotMarshalErr := bp.ToJsonString()
+	gotBp, gotReparseErr := ParsePolicy(gotDocument)
+
+	// assert
+	if gotErr != nil || gotMarshalErr != nil || gotReparseErr != nil {
+		t.Fatalf("Test_ParsePolicy_KeepUnknownFields failed, errors are [%v] [%v] [%v]",
+			gotErr, gotMarshalErr, gotReparseErr)
+	}
+	wantUnknown := []string{"NotPrincipal", "NotResource"}
+	for _, key := range wantUnknown {
+		if _, exist := gotBp.Statement[0].Unknown[key]; !exist {
+			t.Errorf("Test_ParsePolicy_KeepUnknownFields failed, field [%s] is dropped from [%s]", key, gotDocument)
+		}
+	}
+	if !reflect.DeepEqual(gotBp, bp) {
+		t.Errorf("Test_ParsePolicy_KeepUnknownFields failed, gotBp= [%v], wantBp= [%v]", gotBp, bp)
+	}
+}
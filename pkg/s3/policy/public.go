@@ -0,0 +1,96 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PublicReadSid is the sid of the statement granting everyone to read objects of the bucket
+const PublicReadSid = "public-read"
+
+// NewPublicReadStatement builds the statement granting everyone to get objects of the bucket.
+// Objects are limited to the prefixes if any, an empty prefix means all objects of the bucket.
+func NewPublicReadStatement(bucketName string, prefixes ...string) *Statement {
+	statement := NewStatementBuilder().
+		WithSID(PublicReadSid).
+		WithEffect(EffectAllow).
+		WithPrincipals(AnonymousPrincipal).
+		WithActions([]action{getObject}).
+		Build()
+
+	for _, prefix := range normalizePrefixes(prefixes) {
+		statement.Resource = append(statement.Resource, fmt.Sprintf(arnResourceFormat, bucketName+"/"+prefix+"*"))
+	}
+
+	return statement
+}
+
+// PublicReadPrefixes returns the object prefixes of the public read statement, nil if there is no such statement
+func (bp *BucketPolicy) PublicReadPrefixes(bucketName string) []string {
+	objectArnPrefix := fmt.Sprintf(arnResourceFormat, bucketName+"/")
+	for _, ps := range bp.Statement {
+		if ps.Sid != PublicReadSid {
+			continue
+		}
+
+		prefixes := make([]string, 0, len(ps.Resource))
+		for _, r := range ps.Resource {
+			if strings.HasPrefix(r, objectArnPrefix) {
+				prefixes = append(prefixes, strings.TrimSuffix(strings.TrimPrefix(r, objectArnPrefix), "*"))
+			}
+		}
+		return normalizePrefixes(prefixes)
+	}
+
+	return nil
+}
+
+// SetPublicRead replaces the public read statement with the one of given prefixes,
+// the statement is removed if there is no prefix.
+// Return a new bucket policy.
+func (bp *BucketPolicy) SetPublicRead(bucketName string, prefixes []string) *BucketPolicy {
+	newBp := bp.RemoveStatement(PublicReadSid)
+	newBp.Id = bp.Id
+	if len(prefixes) == 0 {
+		return newBp
+	}
+
+	newBp.Statement = append(newBp.Statement, *NewPublicReadStatement(bucketName, prefixes...))
+	return newBp
+}
+
+// normalizePrefixes sorts and deduplicates the prefixes,
+// only the empty prefix is kept if there is, because it covers all objects
+func normalizePrefixes(prefixes []string) []string {
+	exist := make(map[string]bool, len(prefixes))
+	var result []string
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			return []string{""}
+		}
+
+		if !exist[prefix] {
+			exist[prefix] = true
+			result = append(result, prefix)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
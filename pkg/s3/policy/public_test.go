@@ -0,0 +1,57 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewPublicReadStatement_Prefixes(t *testing.T) {
+	// act
+	got := NewPublicReadStatement("bucket-name", "b/", "a/", "b/")
+
+	// assert
+	assert.Equal(t, PublicReadSid, got.Sid)
+	assert.Equal(t, map[string][]string{awsPrinciple: {AnonymousPrincipal}}, got.Principal)
+	assert.Equal(t, []action{getObject}, got.Action)
+	assert.Equal(t, []string{"arn:aws:s3:::bucket-name/a/*", "arn:aws:s3:::bucket-name/b/*"}, got.Resource)
+}
+
+func Test_NewPublicReadStatement_WholeBucket(t *testing.T) {
+	// act
+	got := NewPublicReadStatement("bucket-name", "a/", "")
+
+	// assert
+	assert.Equal(t, []string{"arn:aws:s3:::bucket-name/*"}, got.Resource)
+}
+
+func Test_BucketPolicy_SetPublicRead(t *testing.T) {
+	// arrange
+	user := buildUserStatement("user-1", AllowedReadActions)
+	bp := NewBucketPolicy(*user)
+
+	// act
+	added := bp.SetPublicRead("bucket-name", []string{"a/", "b/"})
+	gotPrefixes := added.PublicReadPrefixes("bucket-name")
+	removed := added.SetPublicRead("bucket-name", nil)
+
+	// assert
+	assert.Equal(t, []string{"a/", "b/"}, gotPrefixes)
+	assert.Nil(t, bp.PublicReadPrefixes("bucket-name"))
+	assert.Equal(t, NewBucketPolicy(*user), removed)
+}
@@ -16,7 +16,9 @@
 package policy
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 type action string
@@ -88,6 +90,9 @@ const (
 
 	// arn resource format
 	arnResourceFormat = "arn:aws:s3:::%s"
+
+	// all s3 actions start with this prefix
+	actionPrefix = "s3:"
 )
 
 // Statement is the Go representation of a bucket policy statement json struct,
@@ -100,8 +105,9 @@ type Statement struct {
 	Effect effect `json:"Effect"`
 
 	// Principle is the user of arn format affected by this policy statement
-	// the format likes 'arn:aws:iam::{accountId}:{userName}'
-	Principal map[string][]string `json:"Principal"`
+	// the format likes 'arn:aws:iam::{accountId}:{userName}',
+	// it is absent in user policies, which always apply to the user they are attached to
+	Principal map[string][]string `json:"Principal,omitempty"`
 
 	// Action is a list of s3 actions
 	Action []action `json:"Action"`
@@ -109,6 +115,60 @@ type Statement struct {
 	// Resource is the ARN identifier for the S3 bucket
 	// the format likes 'arn:aws:s3:::{bucket-name}'
 	Resource []string `json:"Resource"`
+
+	// Condition is the optional condition block of the statement,
+	// the format likes '{"StringLike": {"s3:prefix": ["home/*"]}}'
+	Condition Condition `json:"Condition,omitempty"`
+
+	// Unknown keeps the fields not modeled above, likes 'NotPrincipal', 'NotAction' and 'NotResource',
+	// so that they are written back as they are when the policy is edited
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// statementFields is the modeled fields of Statement, it is marshaled without the methods of Statement
+type statementFields Statement
+
+// UnmarshalJSON parses the modeled fields of statement, and keeps the others in Unknown
+func (ps *Statement) UnmarshalJSON(data []byte) error {
+	var fields statementFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"Sid", "Effect", "Principal", "Action", "Resource", "Condition"} {
+		delete(all, key)
+	}
+	if len(all) > 0 {
+		fields.Unknown = all
+	}
+
+	*ps = Statement(fields)
+	return nil
+}
+
+// MarshalJSON writes the modeled fields of statement together with the unknown ones
+func (ps Statement) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(statementFields(ps))
+	if err != nil || len(ps.Unknown) == 0 {
+		return data, err
+	}
+
+	all := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for key, value := range ps.Unknown {
+		if _, modeled := all[key]; !modeled {
+			all[key] = value
+		}
+	}
+
+	return json.Marshal(all)
 }
 
 // NewStatementBuilder generates a new Policy statement builder.
@@ -172,7 +232,63 @@ func (ps *Statement) WithActions(actions []action) *Statement {
 	return ps
 }
 
+// WithCondition adds the values of condition key under the condition operator to the policy statement,
+// condition likes '{"DateLessThan": {"aws:CurrentTime": ["2026-01-01T00:00:00Z"]}}'
+func (ps *Statement) WithCondition(operator, key string, values ...string) *Statement {
+	if ps.Condition == nil {
+		ps.Condition = Condition{}
+	}
+	if ps.Condition[operator] == nil {
+		ps.Condition[operator] = map[string]ConditionValues{}
+	}
+
+	ps.Condition[operator][key] = append(ps.Condition[operator][key], values...)
+	return ps
+}
+
 // Build return assembled statement
 func (ps *Statement) Build() *Statement {
 	return ps
 }
+
+// Validate checks whether the statement is well-formed and only refers to the given bucket
+func (ps *Statement) Validate(bucketName string) error {
+	if ps.Effect != EffectAllow && ps.Effect != EffectDeny {
+		return fmt.Errorf("invalid effect [%s]", ps.Effect)
+	}
+
+	var principalCount int
+	for _, principals := range ps.Principal {
+		principalCount += len(principals)
+	}
+	if principalCount == 0 {
+		return fmt.Errorf("principal is empty")
+	}
+
+	if len(ps.Action) == 0 {
+		return fmt.Errorf("action is empty")
+	}
+	for _, a := range ps.Action {
+		if !strings.HasPrefix(string(a), actionPrefix) {
+			return fmt.Errorf("invalid action [%s]", a)
+		}
+	}
+
+	if len(ps.Resource) == 0 {
+		return fmt.Errorf("resource is empty")
+	}
+	bucketArn := fmt.Sprintf(arnResourceFormat, bucketName)
+	for _, r := range ps.Resource {
+		if r != bucketArn && !strings.HasPrefix(r, bucketArn+"/") {
+			return fmt.Errorf("resource [%s] is out of bucket [%s]", r, bucketName)
+		}
+	}
+
+	for operator, keys := range ps.Condition {
+		if operator == "" || len(keys) == 0 {
+			return fmt.Errorf("invalid condition operator [%s]", operator)
+		}
+	}
+
+	return nil
+}
@@ -18,6 +18,8 @@ package policy
 import (
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_Statement_Build_Case(t *testing.T) {
@@ -54,3 +56,19 @@ func Test_Statement_Build_Case(t *testing.T) {
 			"wantStatement= [%v]", gotStatement, wantStatement)
 	}
 }
+
+func Test_Statement_WithCondition(t *testing.T) {
+	// arrange
+	wantCondition := Condition{ConditionDateLessThan: {ConditionKeyCurrentTime: {"2026-01-01T00:00:00Z"}}}
+
+	// act
+	gotStatement := NewStatementBuilder().WithSID("sid-test").
+		WithCondition(ConditionDateLessThan, ConditionKeyCurrentTime, "2026-01-01T00:00:00Z").Build()
+
+	// assert
+	assert.Equal(t, wantCondition, gotStatement.Condition)
+	assert.True(t, gotStatement.Condition.matches(map[string]string{
+		ConditionKeyCurrentTime: "2025-12-31T23:59:59Z"}))
+	assert.False(t, gotStatement.Condition.matches(map[string]string{
+		ConditionKeyCurrentTime: "2026-01-01T00:00:01Z"}))
+}
@@ -0,0 +1,107 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateData contains the values which can be referenced by a statement template,
+// e.g. '{{.UserArn}}', '{{.Bucket}}' and '{{.Namespace}}'
+type TemplateData struct {
+	// UserArn is the arn of the user which is granted
+	UserArn string
+	// UserName is the name of the user which is granted
+	UserName string
+	// Bucket is the name of the bucket which is granted
+	Bucket string
+	// Namespace is the namespace of the BucketAccess
+	Namespace string
+}
+
+// RenderStatementTemplate renders the json statement template with data,
+// then parses and validates the rendered statement.
+// The sid of the statement is always set to sid, so that it is owned by the caller.
+func RenderStatementTemplate(text, sid string, data TemplateData) (*Statement, error) {
+	tmpl, err := template.New(sid).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse statement template failed, error is [%v]", err)
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data.escaped()); err != nil {
+		return nil, fmt.Errorf("render statement template failed, error is [%v]", err)
+	}
+
+	statement := NewStatementBuilder()
+	decoder := json.NewDecoder(&buf)
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(statement); err != nil {
+		return nil, fmt.Errorf("unmarshal rendered statement failed, error is [%v]", err)
+	}
+
+	if err = statement.Validate(data.Bucket); err != nil {
+		return nil, fmt.Errorf("validate rendered statement failed, error is [%v]", err)
+	}
+
+	if err = checkTemplatePrincipal(statement, data.UserArn); err != nil {
+		return nil, fmt.Errorf("validate rendered statement failed, error is [%v]", err)
+	}
+
+	return statement.WithSID(sid).Build(), nil
+}
+
+// escaped returns the data whose values are escaped as the content of json strings,
+// so the values can not change the structure of the rendered statement
+func (data TemplateData) escaped() TemplateData {
+	return TemplateData{
+		UserArn:   escapeJSONString(data.UserArn),
+		UserName:  escapeJSONString(data.UserName),
+		Bucket:    escapeJSONString(data.Bucket),
+		Namespace: escapeJSONString(data.Namespace),
+	}
+}
+
+func escapeJSONString(value string) string {
+	quoted, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(string(quoted), `"`), `"`)
+}
+
+// checkTemplatePrincipal requires the principal of the rendered statement to be the granted user only,
+// so the template can not grant other principals
+func checkTemplatePrincipal(statement *Statement, userArn string) error {
+	for key, principals := range statement.Principal {
+		if key != awsPrinciple {
+			return fmt.Errorf("principal [%s] is not allowed, it must be %s", key, awsPrinciple)
+		}
+		for _, principal := range principals {
+			if principal != userArn {
+				return fmt.Errorf("principal [%s] is not the granted user [%s], it must be {{.UserArn}}",
+					principal, userArn)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package policy helps to process the data structure of bucket policy
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const statementTemplate = `{
+  "Effect": "Allow",
+  "Principal": {"AWS": ["{{.UserArn}}"]},
+  "Action": ["s3:ListBucket"],
+  "Resource": ["arn:aws:s3:::{{.Bucket}}"],
+  "Condition": {"StringLike": {"s3:prefix": "{{.Namespace}}/*"}}
+}`
+
+func Test_RenderStatementTemplate_Success(t *testing.T) {
+	// arrange
+	data := TemplateData{UserArn: "arn:aws:iam::domain-id:user/user-name", Bucket: "bucket-name", Namespace: "ns"}
+	wantStatement := &Statement{
+		Sid:       "sid-test",
+		Effect:    EffectAllow,
+		Principal: map[string][]string{awsPrinciple: {"arn:aws:iam::domain-id:user/user-name"}},
+		Action:    []action{listBucket},
+		Resource:  []string{"arn:aws:s3:::bucket-name"},
+		Condition: Condition{"StringLike": {"s3:prefix": {"ns/*"}}},
+	}
+
+	// act
+	gotStatement, gotErr := RenderStatementTemplate(statementTemplate, "sid-test", data)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, wantStatement, gotStatement)
+}
+
+func Test_RenderStatementTemplate_ResourceOutOfBucket(t *testing.T) {
+	// arrange
+	text := `{"Effect": "Allow", "Principal": {"AWS": ["{{.UserArn}}"]},
+"Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::other-bucket/*"]}`
+	data := TemplateData{UserArn: "arn:aws:iam::domain-id:user/user-name", Bucket: "bucket-name"}
+
+	// act
+	_, gotErr := RenderStatementTemplate(text, "sid-test", data)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "resource [arn:aws:s3:::other-bucket/*] is out of bucket [bucket-name]")
+}
+
+func Test_RenderStatementTemplate_UnknownPlaceholder(t *testing.T) {
+	// arrange
+	text := `{"Effect": "Allow", "Principal": {"AWS": ["{{.Unknown}}"]}}`
+
+	// act
+	_, gotErr := RenderStatementTemplate(text, "sid-test", TemplateData{})
+
+	// assert
+	assert.ErrorContains(t, gotErr, "render statement template failed")
+}
+
+func Test_RenderStatementTemplate_EscapeValues(t *testing.T) {
+	// arrange
+	data := TemplateData{UserArn: "arn:aws:iam::domain-id:user/user-name", Bucket: "bucket-name",
+		Namespace: `ns/*"]}, "StringEquals": {"aws:username": ["x`}
+
+	// act
+	gotStatement, gotErr := RenderStatementTemplate(statementTemplate, "sid-test", data)
+
+	// assert
+	assert.NoError(t, gotErr)
+	assert.Equal(t, Condition{"StringLike": {"s3:prefix": {data.Namespace + "/*"}}}, gotStatement.Condition)
+}
+
+func Test_RenderStatementTemplate_OtherPrincipal(t *testing.T) {
+	// arrange
+	text := `{"Effect": "Allow", "Principal": {"AWS": ["{{.UserArn}}", "arn:aws:iam::domain-id:user/other"]},
+"Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::{{.Bucket}}/*"]}`
+	data := TemplateData{UserArn: "arn:aws:iam::domain-id:user/user-name", Bucket: "bucket-name"}
+
+	// act
+	_, gotErr := RenderStatementTemplate(text, "sid-test", data)
+
+	// assert
+	assert.ErrorContains(t, gotErr, "principal [arn:aws:iam::domain-id:user/other] is not the granted user")
+}
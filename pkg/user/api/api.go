@@ -24,10 +24,25 @@ type UserAPI interface {
 	CreateUser(context.Context, *CreateUserInput) (*CreateUserOutput, error)
 	GetUser(context.Context, *GetUserInput) (*GetUserOutput, error)
 	DeleteUser(context.Context, *DeleteUserInput) (*DeleteUserOutput, error)
+	ListUsers(context.Context, *ListUsersInput) (*ListUsersOutput, error)
 	CreateUserAccess(context.Context, *CreateUserAccessInput) (*CreateUserAccessOutput, error)
 	DeleteUserAccess(context.Context, *DeleteUserAccessInput) (*DeleteUserAccessOutput, error)
 	ListUserAccessKeys(context.Context, *ListUserAccessKeysInput) (*ListUserAccessKeysOutput, error)
+	UpdateAccessKeyStatus(context.Context, *UpdateAccessKeyStatusInput) (*UpdateAccessKeyStatusOutput, error)
+	PutUserPolicy(context.Context, *PutUserPolicyInput) (*PutUserPolicyOutput, error)
+	GetUserPolicy(context.Context, *GetUserPolicyInput) (*GetUserPolicyOutput, error)
+	DeleteUserPolicy(context.Context, *DeleteUserPolicyInput) (*DeleteUserPolicyOutput, error)
+	CreateGroup(context.Context, *CreateGroupInput) (*CreateGroupOutput, error)
+	GetGroup(context.Context, *GetGroupInput) (*GetGroupOutput, error)
+	AddUserToGroup(context.Context, *AddUserToGroupInput) (*AddUserToGroupOutput, error)
+	RemoveUserFromGroup(context.Context, *RemoveUserFromGroupInput) (*RemoveUserFromGroupOutput, error)
+	ListGroupsForUser(context.Context, *ListGroupsForUserInput) (*ListGroupsForUserOutput, error)
 
 	// Close performs logout and cleans up session resources.
 	Close(ctx context.Context) error
 }
+
+// CapacityAPI providers the capacity of the storage backend, it is implemented by the centralized storage only
+type CapacityAPI interface {
+	GetCapacity(context.Context, *GetCapacityInput) (*GetCapacityOutput, error)
+}
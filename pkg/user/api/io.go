@@ -15,9 +15,24 @@
 // Package api defines the user related interface
 package api
 
+// UserOwnership is the metadata recorded in the users created by a cosi driver
+type UserOwnership struct {
+	// ClusterID is the id of the kubernetes cluster which the driver runs in
+	ClusterID string
+
+	// DriverName is the name of the driver which creates the user
+	DriverName string
+
+	// BucketAccess is the '{namespace}/{name}' of the BucketAccess which the user is created for
+	BucketAccess string
+}
+
 // CreateUserInput define CreateUser interface input
 type CreateUserInput struct {
 	UserName string
+
+	// Ownership is recorded in the user if it is not nil
+	Ownership *UserOwnership
 }
 
 // CreateUserOutput define CreateUser interface output
@@ -37,6 +52,34 @@ type GetUserOutput struct {
 	UserName string
 	UserID   string
 	Arn      string
+
+	// Ownership is nil if the user is not created by a cosi driver
+	Ownership *UserOwnership
+}
+
+// ListUsersInput define ListUsers interface input
+type ListUsersInput struct {
+	// PathPrefix filters the users by path, optional
+	PathPrefix string
+
+	// NamePrefix filters the users by name, optional
+	NamePrefix string
+}
+
+// ListUsersOutput define ListUsers interface output
+type ListUsersOutput struct {
+	Users []User
+}
+
+// User define the user listed by ListUsers interface
+type User struct {
+	UserName string
+	UserID   string
+	Arn      string
+	Path     string
+
+	// Ownership is nil if the user is not created by a cosi driver
+	Ownership *UserOwnership
 }
 
 // DeleteUserInput define DeleteUser interface input
@@ -80,3 +123,129 @@ type ListUserAccessKeysInput struct {
 type ListUserAccessKeysOutput struct {
 	AccessKeys []string
 }
+
+// AccessKeyStatus is the status of access key, only active access keys are able to sign requests
+type AccessKeyStatus string
+
+const (
+	// AccessKeyStatusActive means the access key works
+	AccessKeyStatusActive AccessKeyStatus = "Active"
+
+	// AccessKeyStatusInactive means the access key is suspended
+	AccessKeyStatusInactive AccessKeyStatus = "Inactive"
+)
+
+// UpdateAccessKeyStatusInput define UpdateAccessKeyStatus interface input
+type UpdateAccessKeyStatusInput struct {
+	UserName    string
+	AccessKeyId string
+	Status      AccessKeyStatus
+}
+
+// UpdateAccessKeyStatusOutput define UpdateAccessKeyStatus interface output
+type UpdateAccessKeyStatusOutput struct {
+	_ struct{}
+}
+
+// PutUserPolicyInput define PutUserPolicy interface input
+type PutUserPolicyInput struct {
+	UserName       string
+	PolicyName     string
+	PolicyDocument string
+}
+
+// PutUserPolicyOutput define PutUserPolicy interface output
+type PutUserPolicyOutput struct {
+	_ struct{}
+}
+
+// GetUserPolicyInput define GetUserPolicy interface input
+type GetUserPolicyInput struct {
+	UserName   string
+	PolicyName string
+}
+
+// GetUserPolicyOutput define GetUserPolicy interface output
+type GetUserPolicyOutput struct {
+	UserName       string
+	PolicyName     string
+	PolicyDocument string
+}
+
+// DeleteUserPolicyInput define DeleteUserPolicy interface input
+type DeleteUserPolicyInput struct {
+	UserName   string
+	PolicyName string
+}
+
+// DeleteUserPolicyOutput define DeleteUserPolicy interface output
+type DeleteUserPolicyOutput struct {
+	_ struct{}
+}
+
+// CreateGroupInput define CreateGroup interface input
+type CreateGroupInput struct {
+	GroupName string
+}
+
+// CreateGroupOutput define CreateGroup interface output
+type CreateGroupOutput struct {
+	GroupName string
+	GroupID   string
+	Arn       string
+}
+
+// GetGroupInput define GetGroup interface input
+type GetGroupInput struct {
+	GroupName string
+}
+
+// GetGroupOutput define GetGroup interface output
+type GetGroupOutput struct {
+	GroupName string
+	GroupID   string
+	Arn       string
+}
+
+// AddUserToGroupInput define AddUserToGroup interface input
+type AddUserToGroupInput struct {
+	GroupName string
+	UserName  string
+}
+
+// AddUserToGroupOutput define AddUserToGroup interface output
+type AddUserToGroupOutput struct {
+	_ struct{}
+}
+
+// RemoveUserFromGroupInput define RemoveUserFromGroup interface input
+type RemoveUserFromGroupInput struct {
+	GroupName string
+	UserName  string
+}
+
+// RemoveUserFromGroupOutput define RemoveUserFromGroup interface output
+type RemoveUserFromGroupOutput struct {
+	_ struct{}
+}
+
+// ListGroupsForUserInput define ListGroupsForUser interface input
+type ListGroupsForUserInput struct {
+	UserName string
+}
+
+// ListGroupsForUserOutput define ListGroupsForUser interface output
+type ListGroupsForUserOutput struct {
+	GroupNames []string
+}
+
+// GetCapacityInput define GetCapacity interface input
+type GetCapacityInput struct {
+	_ struct{}
+}
+
+// GetCapacityOutput define GetCapacity interface output, the capacities are in bytes
+type GetCapacityOutput struct {
+	TotalCapacity int64
+	FreeCapacity  int64
+}
@@ -0,0 +1,63 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+const (
+	// the capacities of storage pools are in sectors
+	sectorSize = 512
+)
+
+var _ api.CapacityAPI = &Client{}
+
+// GetCapacity sums up the capacity of all storage pools
+func (c *Client) GetCapacity(ctx context.Context, _ *api.GetCapacityInput) (*api.GetCapacityOutput, error) {
+	httpFn := func(ret interface{}) error {
+		return c.httpClient.GET(ctx, c.GetUrl("/storagepool"), nil, ret)
+	}
+
+	resp, err := doRequest[ListStoragePoolsResponse](ctx, c, httpFn)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &api.GetCapacityOutput{}
+	for _, pool := range resp.Data {
+		total, err := strconv.ParseInt(pool.UserTotalCapacity, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid total capacity [%s] of storage pool [%s]", pool.UserTotalCapacity,
+				pool.Name)
+		}
+
+		free, err := strconv.ParseInt(pool.UserFreeCapacity, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid free capacity [%s] of storage pool [%s]", pool.UserFreeCapacity,
+				pool.Name)
+		}
+
+		output.TotalCapacity += total * sectorSize
+		output.FreeCapacity += free * sectorSize
+	}
+
+	return output, nil
+}
@@ -0,0 +1,84 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestGetCapacity(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":[{"ID":"0","NAME":"pool-a","USERTOTALCAPACITY":"2048","USERFREECAPACITY":"1024"},` +
+				`{"ID":"1","NAME":"pool-b","USERTOTALCAPACITY":"4096","USERFREECAPACITY":"2048"}],` +
+				`"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	// Act
+	output, err := client.GetCapacity(context.Background(), &api.GetCapacityInput{})
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.Equal(t, int64(6144*sectorSize), output.TotalCapacity, "total capacity should be summed up")
+	assert.Equal(t, int64(3072*sectorSize), output.FreeCapacity, "free capacity should be summed up")
+}
+
+func TestGetCapacityWhenCapacityInvalid(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":[{"ID":"0","NAME":"pool-a","USERTOTALCAPACITY":"","USERFREECAPACITY":"1024"}],` +
+				`"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	// Act
+	output, err := client.GetCapacity(context.Background(), &api.GetCapacityInput{})
+
+	// Assert
+	assert.Error(t, err, "should error when capacity is invalid")
+	assert.Nil(t, output, "output should be nil on error")
+}
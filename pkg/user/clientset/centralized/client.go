@@ -199,3 +199,37 @@ func doRequest[T any](ctx context.Context, client *Client, httpFn HTTPCallFunc)
 	// 5. Return complete response
 	return httpResponse, nil
 }
+
+// rangePageSize is the max count of objects in one page of list requests
+const rangePageSize = 100
+
+// doListRequest pages through a list API by the range query parameter, likes 'range=[0-100]',
+// until a page has less objects than the page size.
+//
+// Returns:
+//   - S: objects of all pages
+//   - HttpResponse[S]: the response of the last page, including the error information
+//   - error: HTTP or business error
+func doListRequest[S ~[]E, E any](ctx context.Context, client *Client, path string,
+	query map[string]string) (S, HttpResponse[S], error) {
+	var items S
+	for start := 0; ; start += rangePageSize {
+		pageQuery := make(map[string]string, len(query)+1)
+		for k, v := range query {
+			pageQuery[k] = v
+		}
+		pageQuery["range"] = fmt.Sprintf("[%d-%d]", start, start+rangePageSize)
+
+		resp, err := doRequest[S](ctx, client, func(ret interface{}) error {
+			return client.httpClient.GET(ctx, client.GetUrl(path), pageQuery, ret)
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+
+		items = append(items, resp.Data...)
+		if len(resp.Data) < rangePageSize {
+			return items, resp, nil
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+const (
+	// groupARNFormat is the ARN format for IAM groups(arn:aws:iam::{accountId}:group/{groupName})
+	groupARNFormat = "arn:aws:iam::%s:group/%s"
+)
+
+// CreateGroup creates an object user group
+func (c *Client) CreateGroup(ctx context.Context, input *api.CreateGroupInput) (*api.CreateGroupOutput, error) {
+	httpFn := func(ret interface{}) error {
+		body := CreateGroupRequest{
+			Name:     input.GroupName,
+			VstoreId: c.getVStoreID(),
+		}
+		return c.httpClient.POST(ctx, c.GetUrl("/OBJECT_USER_GROUP"), body, ret)
+	}
+
+	resp, err := doRequest[CreateGroupResponse](ctx, c, httpFn)
+	if err != nil {
+		return nil, err
+	}
+
+	data := resp.Data
+	return &api.CreateGroupOutput{
+		GroupName: data.Name,
+		GroupID:   data.Id,
+		Arn:       fmt.Sprintf(groupARNFormat, c.getVStoreID(), data.Name),
+	}, nil
+}
+
+// GetGroup queries object user group information
+// Returns empty result if group does not exist (not an error)
+func (c *Client) GetGroup(ctx context.Context, input *api.GetGroupInput) (*api.GetGroupOutput, error) {
+	httpFn := func(ret interface{}) error {
+		query := map[string]string{
+			"name":     input.GroupName,
+			"vstoreId": c.getVStoreID(),
+		}
+		return c.httpClient.GET(ctx, c.GetUrl("/OBJECT_USER_GROUP"), query, ret)
+	}
+
+	resp, err := doRequest[GetGroupResponse](ctx, c, httpFn)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Data.Id == "" {
+		return nil, nil
+	}
+
+	return &api.GetGroupOutput{
+		GroupName: resp.Data.Name,
+		GroupID:   resp.Data.Id,
+		Arn:       fmt.Sprintf(groupARNFormat, c.getVStoreID(), resp.Data.Name),
+	}, nil
+}
+
+// AddUserToGroup adds an object user to a user group
+func (c *Client) AddUserToGroup(ctx context.Context,
+	input *api.AddUserToGroupInput) (*api.AddUserToGroupOutput, error) {
+	httpFn := func(ret interface{}) error {
+		body := GroupMemberRequest{
+			GroupName: input.GroupName,
+			UserName:  input.UserName,
+			VstoreId:  c.getVStoreID(),
+		}
+		return c.httpClient.POST(ctx, c.GetUrl("/OBJECT_USER_GROUP_MEMBER"), body, ret)
+	}
+
+	_, err := doRequest[GroupMemberResponse](ctx, c, httpFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AddUserToGroupOutput{}, nil
+}
+
+// RemoveUserFromGroup removes an object user from a user group
+// Supports idempotent operation (no error if user does not exist)
+func (c *Client) RemoveUserFromGroup(ctx context.Context,
+	input *api.RemoveUserFromGroupInput) (*api.RemoveUserFromGroupOutput, error) {
+	httpFn := func(ret interface{}) error {
+		queryParams := map[string]string{
+			"groupName": input.GroupName,
+			"userName":  input.UserName,
+			"vstoreId":  c.getVStoreID(),
+		}
+		return c.httpClient.DELETE(ctx, c.GetUrl("/OBJECT_USER_GROUP_MEMBER"), queryParams, ret)
+	}
+
+	resp, err := doRequest[GroupMemberResponse](ctx, c, httpFn)
+	if err != nil {
+		if resp.Error.Code == userNotExist {
+			return &api.RemoveUserFromGroupOutput{}, nil
+		}
+		return nil, err
+	}
+
+	return &api.RemoveUserFromGroupOutput{}, nil
+}
+
+// ListGroupsForUser lists the names of all user groups an object user belongs to
+func (c *Client) ListGroupsForUser(ctx context.Context,
+	input *api.ListGroupsForUserInput) (*api.ListGroupsForUserOutput, error) {
+	httpFn := func(ret interface{}) error {
+		query := map[string]string{
+			"userName": input.UserName,
+			"vstoreId": c.getVStoreID(),
+		}
+		return c.httpClient.GET(ctx, c.GetUrl("/OBJECT_USER_GROUP_MEMBER"), query, ret)
+	}
+
+	resp, err := doRequest[ListGroupsForUserResponse](ctx, c, httpFn)
+	if err != nil {
+		if resp.Error.Code == userNotExist {
+			return &api.ListGroupsForUserOutput{}, nil
+		}
+		return nil, err
+	}
+
+	groupNames := make([]string, 0, len(resp.Data))
+	for _, g := range resp.Data {
+		groupNames = append(groupNames, g.GroupName)
+	}
+
+	return &api.ListGroupsForUserOutput{GroupNames: groupNames}, nil
+}
@@ -0,0 +1,156 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestGetGroup(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{"id":"group-123","name":"test-group"},"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.GetGroupInput{
+		GroupName: "test-group",
+	}
+
+	// Act
+	output, err := client.GetGroup(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.NotNil(t, output, "output should not be nil")
+	assert.Equal(t, "test-group", output.GroupName, "group name should match")
+	assert.Equal(t, "group-123", output.GroupID, "group ID should match")
+}
+
+func TestGetGroupWhenNotFound(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{},"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.GetGroupInput{
+		GroupName: "non-existent-group",
+	}
+
+	// Act
+	output, err := client.GetGroup(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when group not found")
+	assert.Nil(t, output, "output should be nil")
+}
+
+func TestRemoveUserFromGroupWhenNotFound(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{},"error":{"code":1092615946,"description":"User does not exist"}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.RemoveUserFromGroupInput{
+		GroupName: "test-group",
+		UserName:  "non-existent-user",
+	}
+
+	// Act
+	output, err := client.RemoveUserFromGroup(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when user not found (idempotent)")
+	assert.NotNil(t, output, "output should not be nil")
+}
+
+func TestListGroupsForUser(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":[{"groupId":"1","groupName":"group-1"},{"groupId":"2","groupName":"group-2"}],` +
+				`"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.ListGroupsForUserInput{
+		UserName: "test-user",
+	}
+
+	// Act
+	output, err := client.ListGroupsForUser(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.Equal(t, []string{"group-1", "group-2"}, output.GroupNames, "group names should match")
+}
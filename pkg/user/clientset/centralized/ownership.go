@@ -0,0 +1,73 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+const (
+	// ownershipDescriptionPrefix is the description prefix of users created by cosi drivers,
+	// the description likes 'cosi:cluster={clusterId};driver={driverName};bucketAccess={namespace}/{name}'
+	ownershipDescriptionPrefix = "cosi:"
+
+	ownershipSeparator       = ";"
+	ownershipKVSeparator     = "="
+	ownershipClusterKey      = "cluster"
+	ownershipDriverKey       = "driver"
+	ownershipBucketAccessKey = "bucketAccess"
+)
+
+// formatOwnershipDescription records the ownership of user in the description of user
+func formatOwnershipDescription(ownership *api.UserOwnership) string {
+	return fmt.Sprintf("%s%s=%s;%s=%s;%s=%s", ownershipDescriptionPrefix,
+		ownershipClusterKey, ownership.ClusterID, ownershipDriverKey, ownership.DriverName,
+		ownershipBucketAccessKey, ownership.BucketAccess)
+}
+
+// parseOwnershipDescription returns the ownership recorded in the description of user,
+// returns nil if the description has no ownership
+func parseOwnershipDescription(description string) *api.UserOwnership {
+	if !strings.HasPrefix(description, ownershipDescriptionPrefix) {
+		return nil
+	}
+
+	ownership := &api.UserOwnership{}
+	for _, kv := range strings.Split(strings.TrimPrefix(description, ownershipDescriptionPrefix),
+		ownershipSeparator) {
+		key, value, found := strings.Cut(kv, ownershipKVSeparator)
+		if !found {
+			return nil
+		}
+
+		switch key {
+		case ownershipClusterKey:
+			ownership.ClusterID = value
+		case ownershipDriverKey:
+			ownership.DriverName = value
+		case ownershipBucketAccessKey:
+			ownership.BucketAccess = value
+		}
+	}
+
+	if ownership.DriverName == "" {
+		return nil
+	}
+	return ownership
+}
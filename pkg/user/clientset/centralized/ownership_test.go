@@ -0,0 +1,52 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestOwnershipDescriptionRoundTrip(t *testing.T) {
+	// Arrange
+	ownership := &api.UserOwnership{ClusterID: "cluster-a", DriverName: "cosi.huawei.com",
+		BucketAccess: "app/ba-demo"}
+
+	// Act
+	description := formatOwnershipDescription(ownership)
+	parsed := parseOwnershipDescription(description)
+
+	// Assert
+	assert.Equal(t, "cosi:cluster=cluster-a;driver=cosi.huawei.com;bucketAccess=app/ba-demo", description,
+		"description should record the ownership")
+	assert.Equal(t, ownership, parsed, "ownership should be parsed from description")
+}
+
+func TestParseOwnershipDescriptionWhenNotOwned(t *testing.T) {
+	// Arrange
+	descriptions := []string{"", "created by admin", "cosi:invalid", "cosi:cluster=cluster-a"}
+
+	for _, description := range descriptions {
+		// Act
+		parsed := parseOwnershipDescription(description)
+
+		// Assert
+		assert.Nil(t, parsed, "description [%s] should have no ownership", description)
+	}
+}
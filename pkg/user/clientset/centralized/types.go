@@ -108,6 +108,9 @@ type GetUserResponse struct {
 	CreateTime      string `json:"createTime"`
 }
 
+// ListUsersResponse represents a response to list users
+type ListUsersResponse []User
+
 // DeleteUserRequest represents a request to delete a user
 type DeleteUserRequest struct {
 	Id       string `json:"id,omitempty"`
@@ -146,6 +149,17 @@ type DeleteAccessKeyRequest struct {
 // DeleteAccessKeyResponse represents a response to delete an access key
 type DeleteAccessKeyResponse struct{}
 
+// ModifyAccessKeyRequest represents a request to activate or deactivate an access key
+type ModifyAccessKeyRequest struct {
+	Id        string `json:"id"`
+	OwnerType string `json:"ownerType"`
+	Status    bool   `json:"status"`
+	VstoreId  string `json:"vstoreId,omitempty"`
+}
+
+// ModifyAccessKeyResponse represents a response to activate or deactivate an access key
+type ModifyAccessKeyResponse struct{}
+
 // ListAccessKeysRequest represents a request to list access keys
 type ListAccessKeysRequest struct {
 	OwnerName string `json:"ownerName,omitempty"`
@@ -165,6 +179,76 @@ type AccessKeyInfo struct {
 // ListAccessKeysResponse represents a response to list access keys
 type ListAccessKeysResponse []AccessKeyInfo
 
+// PutUserPolicyRequest represents a request to put an inline policy of a user
+type PutUserPolicyRequest struct {
+	UserName       string `json:"userName"`
+	PolicyName     string `json:"policyName"`
+	PolicyDocument string `json:"policyDocument"`
+	VstoreId       string `json:"vstoreId,omitempty"`
+}
+
+// PutUserPolicyResponse represents a response to put an inline policy of a user
+type PutUserPolicyResponse struct{}
+
+// GetUserPolicyResponse represents a response to get an inline policy of a user
+type GetUserPolicyResponse struct {
+	UserName       string `json:"userName"`
+	PolicyName     string `json:"policyName"`
+	PolicyDocument string `json:"policyDocument"`
+}
+
+// DeleteUserPolicyResponse represents a response to delete an inline policy of a user
+type DeleteUserPolicyResponse struct{}
+
+// CreateGroupRequest represents a request to create a user group
+type CreateGroupRequest struct {
+	Name     string `json:"name"`
+	VstoreId string `json:"vstoreId,omitempty"`
+}
+
+// CreateGroupResponse represents a response to create a user group
+type CreateGroupResponse struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetGroupResponse represents a response to get user group information
+type GetGroupResponse struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	CreateTime string `json:"createTime"`
+}
+
+// GroupMemberRequest represents a request to add a user to a user group
+type GroupMemberRequest struct {
+	GroupName string `json:"groupName"`
+	UserName  string `json:"userName"`
+	VstoreId  string `json:"vstoreId,omitempty"`
+}
+
+// GroupMemberResponse represents a response to add or remove a user of a user group
+type GroupMemberResponse struct{}
+
+// GroupMemberInfo represents a user group which a user belongs to
+type GroupMemberInfo struct {
+	GroupId   string `json:"groupId"`
+	GroupName string `json:"groupName"`
+}
+
+// ListGroupsForUserResponse represents a response to list the user groups of a user
+type ListGroupsForUserResponse []GroupMemberInfo
+
+// StoragePoolInfo represents the capacity of a storage pool, the capacities are in sectors
+type StoragePoolInfo struct {
+	Id                string `json:"ID"`
+	Name              string `json:"NAME"`
+	UserTotalCapacity string `json:"USERTOTALCAPACITY"`
+	UserFreeCapacity  string `json:"USERFREECAPACITY"`
+}
+
+// ListStoragePoolsResponse represents a response to list the storage pools
+type ListStoragePoolsResponse []StoragePoolInfo
+
 // LogString returns the string for logging, sensitive fields are omitted
 func (r ListAccessKeysResponse) LogString() string {
 	return fmt.Sprintf(`{"count":%d}`, len(r))
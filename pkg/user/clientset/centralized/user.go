@@ -18,6 +18,7 @@ package centralized
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/huawei/cosi-driver/pkg/user/api"
 )
@@ -36,6 +37,9 @@ func (c *Client) CreateUser(ctx context.Context, input *api.CreateUserInput) (*a
 			UserType: localUserType,
 			VstoreId: c.getVStoreID(),
 		}
+		if input.Ownership != nil {
+			body.UserDescription = formatOwnershipDescription(input.Ownership)
+		}
 		return c.httpClient.POST(ctx, c.GetUrl("/OBJECT_USER"), body, ret)
 	}
 
@@ -75,9 +79,10 @@ func (c *Client) GetUser(ctx context.Context, input *api.GetUserInput) (*api.Get
 
 	arn := fmt.Sprintf(userARNFormat, c.getVStoreID(), resp.Data.Name)
 	return &api.GetUserOutput{
-		UserName: resp.Data.Name,
-		UserID:   resp.Data.Id,
-		Arn:      arn,
+		UserName:  resp.Data.Name,
+		UserID:    resp.Data.Id,
+		Arn:       arn,
+		Ownership: parseOwnershipDescription(resp.Data.UserDescription),
 	}, nil
 }
 
@@ -102,3 +107,36 @@ func (c *Client) DeleteUser(ctx context.Context, input *api.DeleteUserInput) (*a
 
 	return &api.DeleteUserOutput{}, nil
 }
+
+// ListUsers lists the object users whose path and name have the prefixes,
+// all pages are listed by the range parameter
+func (c *Client) ListUsers(ctx context.Context, input *api.ListUsersInput) (*api.ListUsersOutput, error) {
+	query := map[string]string{
+		"vstoreId": c.getVStoreID(),
+	}
+	if input.PathPrefix != "" {
+		query["path"] = input.PathPrefix
+	}
+
+	data, _, err := doListRequest[ListUsersResponse](ctx, c, "/OBJECT_USER", query)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &api.ListUsersOutput{}
+	for _, u := range data {
+		if !strings.HasPrefix(u.Path, input.PathPrefix) || !strings.HasPrefix(u.Name, input.NamePrefix) {
+			continue
+		}
+
+		out.Users = append(out.Users, api.User{
+			UserName:  u.Name,
+			UserID:    u.Id,
+			Arn:       fmt.Sprintf(userARNFormat, c.getVStoreID(), u.Name),
+			Path:      u.Path,
+			Ownership: parseOwnershipDescription(u.UserDescription),
+		})
+	}
+
+	return out, nil
+}
@@ -73,19 +73,17 @@ func (c *Client) DeleteUserAccess(ctx context.Context,
 	return &api.DeleteUserAccessOutput{}, nil
 }
 
-// ListUserAccessKeys lists all access key IDs for a user (does not return secret keys)
+// ListUserAccessKeys lists all access key IDs for a user (does not return secret keys),
+// all pages are listed by the range parameter
 func (c *Client) ListUserAccessKeys(ctx context.Context,
 	input *api.ListUserAccessKeysInput) (*api.ListUserAccessKeysOutput, error) {
-	httpFn := func(ret interface{}) error {
-		query := map[string]string{
-			"ownerName": input.UserName,
-			"ownerType": userOwnType,
-			"vstoreId":  c.getVStoreID(),
-		}
-		return c.httpClient.GET(ctx, c.GetUrl("/OBJECT_AKSK"), query, ret)
+	query := map[string]string{
+		"ownerName": input.UserName,
+		"ownerType": userOwnType,
+		"vstoreId":  c.getVStoreID(),
 	}
 
-	resp, err := doRequest[ListAccessKeysResponse](ctx, c, httpFn)
+	data, resp, err := doListRequest[ListAccessKeysResponse](ctx, c, "/OBJECT_AKSK", query)
 	if err != nil {
 		if resp.Error.Code == userNotExist {
 			return &api.ListUserAccessKeysOutput{}, nil
@@ -93,7 +91,6 @@ func (c *Client) ListUserAccessKeys(ctx context.Context,
 		return nil, err
 	}
 
-	data := resp.Data
 	accessKeys := make([]string, 0, len(data))
 	for _, ak := range data {
 		accessKeys = append(accessKeys, ak.Id)
@@ -101,3 +98,24 @@ func (c *Client) ListUserAccessKeys(ctx context.Context,
 
 	return &api.ListUserAccessKeysOutput{AccessKeys: accessKeys}, nil
 }
+
+// UpdateAccessKeyStatus activates or deactivates object storage access credentials
+func (c *Client) UpdateAccessKeyStatus(ctx context.Context,
+	input *api.UpdateAccessKeyStatusInput) (*api.UpdateAccessKeyStatusOutput, error) {
+	httpFn := func(ret interface{}) error {
+		body := ModifyAccessKeyRequest{
+			Id:        input.AccessKeyId,
+			OwnerType: userOwnType,
+			Status:    input.Status == api.AccessKeyStatusActive,
+			VstoreId:  c.getVStoreID(),
+		}
+		return c.httpClient.PUT(ctx, c.GetUrl("/OBJECT_AKSK"), body, ret)
+	}
+
+	_, err := doRequest[ModifyAccessKeyResponse](ctx, c, httpFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.UpdateAccessKeyStatusOutput{}, nil
+}
@@ -279,3 +279,36 @@ func TestListUserAccessKeysWhenEmpty(t *testing.T) {
 	assert.NotNil(t, output, "output should not be nil")
 	assert.Empty(t, output.AccessKeys, "should return empty access keys list")
 }
+
+func TestUpdateAccessKeyStatus(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{},"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.UpdateAccessKeyStatusInput{
+		UserName:    "test-user",
+		AccessKeyId: "ak-1",
+		Status:      api.AccessKeyStatusInactive,
+	}
+
+	// Act
+	output, err := client.UpdateAccessKeyStatus(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.NotNil(t, output, "output should not be nil")
+}
@@ -0,0 +1,99 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"context"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+// PutUserPolicy attaches an inline policy to an object user, an existing policy with the same name is replaced
+func (c *Client) PutUserPolicy(ctx context.Context,
+	input *api.PutUserPolicyInput) (*api.PutUserPolicyOutput, error) {
+	httpFn := func(ret interface{}) error {
+		body := PutUserPolicyRequest{
+			UserName:       input.UserName,
+			PolicyName:     input.PolicyName,
+			PolicyDocument: input.PolicyDocument,
+			VstoreId:       c.getVStoreID(),
+		}
+		return c.httpClient.POST(ctx, c.GetUrl("/OBJECT_USER_POLICY"), body, ret)
+	}
+
+	_, err := doRequest[PutUserPolicyResponse](ctx, c, httpFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PutUserPolicyOutput{}, nil
+}
+
+// GetUserPolicy queries an inline policy of an object user
+// Returns empty result if user or policy does not exist (not an error)
+func (c *Client) GetUserPolicy(ctx context.Context,
+	input *api.GetUserPolicyInput) (*api.GetUserPolicyOutput, error) {
+	httpFn := func(ret interface{}) error {
+		query := map[string]string{
+			"userName":   input.UserName,
+			"policyName": input.PolicyName,
+			"vstoreId":   c.getVStoreID(),
+		}
+		return c.httpClient.GET(ctx, c.GetUrl("/OBJECT_USER_POLICY"), query, ret)
+	}
+
+	resp, err := doRequest[GetUserPolicyResponse](ctx, c, httpFn)
+	if err != nil {
+		if resp.Error.Code == userNotExist {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if resp.Data.PolicyDocument == "" {
+		return nil, nil
+	}
+
+	return &api.GetUserPolicyOutput{
+		UserName:       resp.Data.UserName,
+		PolicyName:     resp.Data.PolicyName,
+		PolicyDocument: resp.Data.PolicyDocument,
+	}, nil
+}
+
+// DeleteUserPolicy deletes an inline policy of an object user
+// Supports idempotent operation (no error if user does not exist)
+func (c *Client) DeleteUserPolicy(ctx context.Context,
+	input *api.DeleteUserPolicyInput) (*api.DeleteUserPolicyOutput, error) {
+	httpFn := func(ret interface{}) error {
+		queryParams := map[string]string{
+			"userName":   input.UserName,
+			"policyName": input.PolicyName,
+			"vstoreId":   c.getVStoreID(),
+		}
+		return c.httpClient.DELETE(ctx, c.GetUrl("/OBJECT_USER_POLICY"), queryParams, ret)
+	}
+
+	resp, err := doRequest[DeleteUserPolicyResponse](ctx, c, httpFn)
+	if err != nil {
+		if resp.Error.Code == userNotExist {
+			return &api.DeleteUserPolicyOutput{}, nil
+		}
+		return nil, err
+	}
+
+	return &api.DeleteUserPolicyOutput{}, nil
+}
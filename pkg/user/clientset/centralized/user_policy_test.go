@@ -0,0 +1,160 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package centralized implements a centralized client for Huawei OceanStor object storage.
+package centralized
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestPutUserPolicy(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{},"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.PutUserPolicyInput{
+		UserName:       "test-user",
+		PolicyName:     "test-policy",
+		PolicyDocument: `{"Version":"2012-10-17"}`,
+	}
+
+	// Act
+	output, err := client.PutUserPolicy(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.NotNil(t, output, "output should not be nil")
+}
+
+func TestGetUserPolicy(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{"userName":"test-user","policyName":"test-policy",` +
+				`"policyDocument":"{\"Version\":\"2012-10-17\"}"},"error":{"code":0,"description":""}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.GetUserPolicyInput{
+		UserName:   "test-user",
+		PolicyName: "test-policy",
+	}
+
+	// Act
+	output, err := client.GetUserPolicy(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.NotNil(t, output, "output should not be nil")
+	assert.Equal(t, "test-policy", output.PolicyName, "policy name should match")
+	assert.Equal(t, `{"Version":"2012-10-17"}`, output.PolicyDocument, "policy document should match")
+}
+
+func TestGetUserPolicyWhenUserNotFound(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{},"error":{"code":1092615946,"description":"User does not exist"}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.GetUserPolicyInput{
+		UserName:   "non-existent-user",
+		PolicyName: "test-policy",
+	}
+
+	// Act
+	output, err := client.GetUserPolicy(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when user not found")
+	assert.Nil(t, output, "output should be nil")
+}
+
+func TestDeleteUserPolicyWhenNotFound(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			responseBody := `{"data":{},"error":{"code":1092615946,"description":"User does not exist"}}`
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.DeleteUserPolicyInput{
+		UserName:   "non-existent-user",
+		PolicyName: "test-policy",
+	}
+
+	// Act
+	output, err := client.DeleteUserPolicy(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when user not found (idempotent)")
+	assert.NotNil(t, output, "output should not be nil")
+}
@@ -406,3 +406,49 @@ func TestDoRequestWhenSemaphoreAcquireFails(t *testing.T) {
 	assert.Error(t, err, "should error when cannot acquire semaphore")
 	assert.Contains(t, err.Error(), "context canceled", "error should indicate context cancellation")
 }
+
+func TestListUsersWithRangePages(t *testing.T) {
+	// Arrange
+	mockSession := &mockAuthenticator{
+		isAuthenticated: true,
+		deviceID:        "device-123",
+	}
+
+	var firstPage, lastPage ListUsersResponse
+	for i := 0; i < rangePageSize; i++ {
+		firstPage = append(firstPage, User{Id: "id", Name: "other-user", Path: "/"})
+	}
+	firstPage[0] = User{Id: "id-1", Name: "ba-uid-1", Path: "/"}
+	lastPage = append(lastPage, User{Id: "id-2", Name: "ba-uid-2", Path: "/"})
+
+	calls := 0
+	mockHTTPClient := &mockHTTPClient{
+		responseFunc: func() *http.Response {
+			page := firstPage
+			if calls > 0 {
+				page = lastPage
+			}
+			calls++
+			body, _ := json.Marshal(HttpResponse[ListUsersResponse]{Data: page})
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(body)),
+			}
+		},
+	}
+	client := NewMockClient(t, mockSession, mockHTTPClient)
+
+	ctx := context.Background()
+	input := &api.ListUsersInput{NamePrefix: "ba-"}
+
+	// Act
+	output, err := client.ListUsers(ctx, input)
+
+	// Assert
+	assert.NoError(t, err, "should not error when request succeeds")
+	assert.Equal(t, 2, calls, "should request until the last page")
+	assert.Equal(t, []api.User{
+		{UserName: "ba-uid-1", UserID: "id-1", Arn: "arn:aws:iam::" + client.getVStoreID() + ":user/ba-uid-1", Path: "/"},
+		{UserName: "ba-uid-2", UserID: "id-2", Arn: "arn:aws:iam::" + client.getVStoreID() + ":user/ba-uid-2", Path: "/"},
+	}, output.Users, "users should be filtered by name prefix")
+}
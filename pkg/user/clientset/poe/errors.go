@@ -25,6 +25,10 @@ const (
 	errNoSuchUser errorReason = "NoSuchEntity"
 	// errNoSuchUserAccess means user access not exist
 	errNoSuchUserAccess errorReason = "NoSuchEntity"
+	// errNoSuchUserPolicy means user or user policy not exist
+	errNoSuchUserPolicy errorReason = "NoSuchEntity"
+	// errNoSuchGroup means user or group not exist
+	errNoSuchGroup errorReason = "NoSuchEntity"
 )
 
 // errorReason is the reason of the error
@@ -0,0 +1,196 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poe provides poe client and poe apis
+package poe
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	createGroupAction         = "CreateGroup"
+	getGroupAction            = "GetGroup"
+	addUserToGroupAction      = "AddUserToGroup"
+	removeUserFromGroupAction = "RemoveUserFromGroup"
+	listGroupsForUserAction   = "ListGroupsForUser"
+
+	groupNameKey = "GroupName"
+	markerKey    = "Marker"
+)
+
+// CreateGroup is used to create group on backend
+func (pec *Client) CreateGroup(ctx context.Context, in *api.CreateGroupInput) (*api.CreateGroupOutput, error) {
+	log.AddContext(ctx).Infof("start to create group, input is [%+v]", in)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = createGroupAction
+	paramMap[groupNameKey] = in.GroupName
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &createGroupResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.AddContext(ctx).Infof("create group success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
+	return &api.CreateGroupOutput{
+		GroupName: resp.CreateGroupResult.Group.GroupName,
+		GroupID:   resp.CreateGroupResult.Group.GroupID,
+		Arn:       resp.CreateGroupResult.Group.Arn,
+	}, nil
+}
+
+// GetGroup is used to get group on backend, returns nil if group not exist
+func (pec *Client) GetGroup(ctx context.Context, in *api.GetGroupInput) (*api.GetGroupOutput, error) {
+	log.AddContext(ctx).Infof("start to get group, input is [%+v]", in)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = getGroupAction
+	paramMap[groupNameKey] = in.GroupName
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		if errors.Is(err, errNoSuchGroup) {
+			msg := fmt.Sprintf("group [%s] not exist", in.GroupName)
+			log.AddContext(ctx).Infof(msg)
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	resp := &getGroupResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.AddContext(ctx).Infof("get group success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
+	return &api.GetGroupOutput{
+		GroupName: resp.GetGroupResult.Group.GroupName,
+		GroupID:   resp.GetGroupResult.Group.GroupID,
+		Arn:       resp.GetGroupResult.Group.Arn,
+	}, nil
+}
+
+// AddUserToGroup is used to add user to group on backend
+func (pec *Client) AddUserToGroup(ctx context.Context,
+	in *api.AddUserToGroupInput) (*api.AddUserToGroupOutput, error) {
+	log.AddContext(ctx).Infof("start to add user to group, input is [%+v]", in)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = addUserToGroupAction
+	paramMap[groupNameKey] = in.GroupName
+	paramMap[userNameKey] = in.UserName
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &addUserToGroupResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.AddContext(ctx).Infof("add user to group success, storage request id is [%s]",
+		resp.ResponseMetadata.RequestId)
+	return &api.AddUserToGroupOutput{}, nil
+}
+
+// RemoveUserFromGroup is used to remove user from group on backend
+func (pec *Client) RemoveUserFromGroup(ctx context.Context,
+	in *api.RemoveUserFromGroupInput) (*api.RemoveUserFromGroupOutput, error) {
+	log.AddContext(ctx).Infof("start to remove user from group, input is [%+v]", in)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = removeUserFromGroupAction
+	paramMap[groupNameKey] = in.GroupName
+	paramMap[userNameKey] = in.UserName
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		if errors.Is(err, errNoSuchGroup) {
+			msg := fmt.Sprintf("user [%s] or group [%s] is not exist", in.UserName, in.GroupName)
+			log.AddContext(ctx).Infof(msg)
+			return &api.RemoveUserFromGroupOutput{}, nil
+		}
+
+		return nil, err
+	}
+
+	resp := &removeUserFromGroupResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.AddContext(ctx).Infof("remove user from group success, storage request id is [%s]",
+		resp.ResponseMetadata.RequestId)
+	return &api.RemoveUserFromGroupOutput{}, nil
+}
+
+// ListGroupsForUser is used to list all groups the user belongs to on backend
+func (pec *Client) ListGroupsForUser(ctx context.Context,
+	in *api.ListGroupsForUserInput) (*api.ListGroupsForUserOutput, error) {
+	log.AddContext(ctx).Infof("start to list groups for user, input is [%+v]", in)
+
+	out := &api.ListGroupsForUserOutput{}
+	var marker string
+	for {
+		paramMap := make(map[string]string, 0)
+		paramMap[actionKey] = listGroupsForUserAction
+		paramMap[userNameKey] = in.UserName
+		if marker != "" {
+			paramMap[markerKey] = marker
+		}
+		body, err := pec.Call(ctx, paramMap)
+		if err != nil {
+			if errors.Is(err, errNoSuchUser) {
+				msg := fmt.Sprintf("user [%s] is not exist", in.UserName)
+				log.AddContext(ctx).Infof(msg)
+				return &api.ListGroupsForUserOutput{}, nil
+			}
+
+			return nil, err
+		}
+
+		resp := &listGroupsForUserResponse{}
+		err = xml.Unmarshal(body, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range resp.ListGroupsForUserResult.Groups.Members {
+			out.GroupNames = append(out.GroupNames, g.GroupName)
+		}
+
+		result := resp.ListGroupsForUserResult
+		if !result.IsTruncated || result.Marker == "" {
+			log.AddContext(ctx).Infof("list groups for user success, storage request id is [%s]",
+				resp.ResponseMetadata.RequestId)
+			return out, nil
+		}
+		marker = result.Marker
+	}
+}
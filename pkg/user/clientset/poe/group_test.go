@@ -0,0 +1,171 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poe provides poe client and poe apis
+package poe
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestClient_CreateGroup_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.CreateGroupInput{GroupName: "group-demo"}
+	body := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<CreateGroupResponse>\n" +
+			"<CreateGroupResult>\n" +
+			"<Group>\n" +
+			"<Path>/</Path>\n" +
+			"<GroupName>group-demo</GroupName>\n" +
+			"<GroupId>00000191224B7D1F3A893E889C135BBB</GroupId>\n" +
+			"<Arn>arn:aws:iam::3059394579:group/group-demo</Arn>\n" +
+			"<CreateDate>2024-08-05T11:27:38.271Z</CreateDate>\n" +
+			"</Group>\n" +
+			"</CreateGroupResult>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>86a0f4ff-fc13-4263-b1a4-8c80124f57e9</RequestId>\n" +
+			"</ResponseMetadata>\n</CreateGroupResponse>")
+
+	want := &api.CreateGroupOutput{
+		GroupName: "group-demo",
+		GroupID:   "00000191224B7D1F3A893E889C135BBB",
+		Arn:       "arn:aws:iam::3059394579:group/group-demo",
+	}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			return body, nil
+		})
+
+	// act
+	got, gotErr := c.CreateGroup(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_CreateGroup_Success failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestClient_GetGroup_NotExist(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.GetGroupInput{GroupName: "group-demo"}
+	errBody := []byte(
+		"<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"yes\"?>\n" +
+			"<ErrorResponse>\n" +
+			"<Error>\n" +
+			"<Code>NoSuchEntity</Code>\n" +
+			"<Message>The request was rejected because it referenced a group that does not exist.</Message>\n" +
+			"</Error>\n" +
+			"<RequestId>5e8141b8-601d-460b-af2d-dea105442f26</RequestId>\n" +
+			"</ErrorResponse>\n")
+
+	mockError := handleErrorResponse(errBody)
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			return nil, mockError
+		})
+
+	// act
+	got, gotErr := c.GetGroup(ctx, in)
+
+	// assert
+	if got != nil || gotErr != nil {
+		t.Errorf("TestClient_GetGroup_NotExist failed, got= [%v], want= nil, "+
+			"gotErr= [%v], wantErr= nil", got, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestClient_ListGroupsForUser_Paginated(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.ListGroupsForUserInput{UserName: "user-demo"}
+	firstBody := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<ListGroupsForUserResponse>\n" +
+			"<ListGroupsForUserResult>\n" +
+			"<Groups>\n" +
+			"<member><GroupName>group-1</GroupName></member>\n" +
+			"</Groups>\n" +
+			"<IsTruncated>true</IsTruncated>\n" +
+			"<Marker>next-marker</Marker>\n" +
+			"</ListGroupsForUserResult>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>841af781-6da9-4cb1-acb3-dcaef2e3902f</RequestId>\n" +
+			"</ResponseMetadata>\n" +
+			"</ListGroupsForUserResponse>")
+	secondBody := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<ListGroupsForUserResponse>\n" +
+			"<ListGroupsForUserResult>\n" +
+			"<Groups>\n" +
+			"<member><GroupName>group-2</GroupName></member>\n" +
+			"</Groups>\n" +
+			"<IsTruncated>false</IsTruncated>\n" +
+			"</ListGroupsForUserResult>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>5fc443be-d9e8-4a0e-86b4-2131a4d45402</RequestId>\n" +
+			"</ResponseMetadata>\n" +
+			"</ListGroupsForUserResponse>")
+
+	want := &api.ListGroupsForUserOutput{GroupNames: []string{"group-1", "group-2"}}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			if param[markerKey] == "next-marker" {
+				return secondBody, nil
+			}
+			return firstBody, nil
+		})
+
+	// act
+	got, gotErr := c.ListGroupsForUser(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_ListGroupsForUser_Paginated failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
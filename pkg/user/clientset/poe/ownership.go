@@ -0,0 +1,62 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poe provides poe client and poe apis
+package poe
+
+import (
+	"strings"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+const (
+	// OwnershipPathPrefix is the path prefix of users created by cosi drivers,
+	// the path likes '/cosi/{clusterId}/{driverName}/{namespace}/{name}/'
+	OwnershipPathPrefix = "/cosi/"
+
+	// ownershipPathSegments is the count of segments after the prefix
+	ownershipPathSegments = 4
+
+	pathKey = "Path"
+)
+
+// formatOwnershipPath records the ownership of user in the IAM path of user
+func formatOwnershipPath(ownership *api.UserOwnership) string {
+	return OwnershipPathPrefix + ownership.ClusterID + "/" + ownership.DriverName + "/" +
+		strings.Trim(ownership.BucketAccess, "/") + "/"
+}
+
+// parseOwnershipPath returns the ownership recorded in the IAM path of user, returns nil if the path has no ownership
+func parseOwnershipPath(path string) *api.UserOwnership {
+	if !strings.HasPrefix(path, OwnershipPathPrefix) || !strings.HasSuffix(path, "/") {
+		return nil
+	}
+
+	segments := strings.Split(strings.TrimSuffix(strings.TrimPrefix(path, OwnershipPathPrefix), "/"), "/")
+	if len(segments) != ownershipPathSegments {
+		return nil
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return nil
+		}
+	}
+
+	return &api.UserOwnership{
+		ClusterID:    segments[0],
+		DriverName:   segments[1],
+		BucketAccess: segments[2] + "/" + segments[3],
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poe provides poe client and poe apis
+package poe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestOwnershipPath_RoundTrip(t *testing.T) {
+	// arrange
+	ownership := &api.UserOwnership{ClusterID: "cluster-a", DriverName: "cosi.huawei.com",
+		BucketAccess: "app/ba-demo"}
+
+	// act
+	gotPath := formatOwnershipPath(ownership)
+	gotOwnership := parseOwnershipPath(gotPath)
+
+	// assert
+	assert.Equal(t, "/cosi/cluster-a/cosi.huawei.com/app/ba-demo/", gotPath)
+	assert.Equal(t, ownership, gotOwnership)
+}
+
+func TestOwnershipPath_UnknownBucketAccess(t *testing.T) {
+	// arrange
+	ownership := &api.UserOwnership{ClusterID: "cluster-a", DriverName: "cosi.huawei.com", BucketAccess: "-/-"}
+
+	// act
+	gotOwnership := parseOwnershipPath(formatOwnershipPath(ownership))
+
+	// assert
+	assert.Equal(t, ownership, gotOwnership)
+}
+
+func TestParseOwnershipPath_NotOwned(t *testing.T) {
+	// arrange
+	paths := []string{"", "/", "/team/", "/cosi/cluster-a/cosi.huawei.com/", "/cosi/cluster-a//app/ba-demo/"}
+
+	for _, path := range paths {
+		// act
+		gotOwnership := parseOwnershipPath(path)
+
+		// assert
+		assert.Nil(t, gotOwnership, path)
+	}
+}
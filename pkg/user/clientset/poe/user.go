@@ -20,6 +20,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/huawei/cosi-driver/pkg/user/api"
 	"github.com/huawei/cosi-driver/pkg/utils/log"
@@ -29,6 +30,9 @@ const (
 	createUserAction = "CreateUser"
 	getUserAction    = "GetUser"
 	deleteUserAction = "DeleteUser"
+	listUsersAction  = "ListUsers"
+
+	pathPrefixKey = "PathPrefix"
 )
 
 // CreateUser is used to create user on backend
@@ -38,6 +42,9 @@ func (pec *Client) CreateUser(ctx context.Context, in *api.CreateUserInput) (*ap
 	paramMap := make(map[string]string, 0)
 	paramMap[actionKey] = createUserAction
 	paramMap[userNameKey] = in.UserName
+	if in.Ownership != nil {
+		paramMap[pathKey] = formatOwnershipPath(in.Ownership)
+	}
 	body, err := pec.Call(ctx, paramMap)
 	if err != nil {
 		return nil, err
@@ -83,9 +90,10 @@ func (pec *Client) GetUser(ctx context.Context, in *api.GetUserInput) (*api.GetU
 
 	log.AddContext(ctx).Infof("get user success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
 	return &api.GetUserOutput{
-		UserName: resp.GetUserResult.User.UserName,
-		UserID:   resp.GetUserResult.User.UserID,
-		Arn:      resp.GetUserResult.User.Arn,
+		UserName:  resp.GetUserResult.User.UserName,
+		UserID:    resp.GetUserResult.User.UserID,
+		Arn:       resp.GetUserResult.User.Arn,
+		Ownership: parseOwnershipPath(resp.GetUserResult.User.Path),
 	}, nil
 }
 
@@ -116,3 +124,47 @@ func (pec *Client) DeleteUser(ctx context.Context, in *api.DeleteUserInput) (*ap
 	log.AddContext(ctx).Infof("delete user success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
 	return &api.DeleteUserOutput{}, nil
 }
+
+// ListUsers is used to list users on backend, all pages are listed by the marker
+func (pec *Client) ListUsers(ctx context.Context, in *api.ListUsersInput) (*api.ListUsersOutput, error) {
+	log.AddContext(ctx).Infof("start to list users, input is [%+v]", in)
+
+	out := &api.ListUsersOutput{}
+	var marker string
+	for {
+		paramMap := make(map[string]string, 0)
+		paramMap[actionKey] = listUsersAction
+		if in.PathPrefix != "" {
+			paramMap[pathPrefixKey] = in.PathPrefix
+		}
+		if marker != "" {
+			paramMap[markerKey] = marker
+		}
+		body, err := pec.Call(ctx, paramMap)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &listUsersResponse{}
+		err = xml.Unmarshal(body, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range resp.ListUsersResult.Users.Members {
+			if !strings.HasPrefix(u.UserName, in.NamePrefix) {
+				continue
+			}
+			out.Users = append(out.Users, api.User{UserName: u.UserName, UserID: u.UserID, Arn: u.Arn, Path: u.Path,
+				Ownership: parseOwnershipPath(u.Path)})
+		}
+
+		result := resp.ListUsersResult
+		if !result.IsTruncated || result.Marker == "" {
+			log.AddContext(ctx).Infof("list users success, storage request id is [%s]",
+				resp.ResponseMetadata.RequestId)
+			return out, nil
+		}
+		marker = result.Marker
+	}
+}
@@ -29,6 +29,9 @@ const (
 	createUserAccess   = "CreateAccessKey"
 	deleteUserAccess   = "DeleteAccessKey"
 	listUserAccessKeys = "ListAccessKeys"
+	updateAccessKey    = "UpdateAccessKey"
+
+	statusKey = "Status"
 )
 
 // CreateUserAccess is used to create user access on backend
@@ -87,38 +90,73 @@ func (pec *Client) DeleteUserAccess(ctx context.Context,
 	return &api.DeleteUserAccessOutput{}, nil
 }
 
-// ListUserAccessKeys is used to list user access keys on backend
+// ListUserAccessKeys is used to list user access keys on backend, all pages are listed by the marker
 func (pec *Client) ListUserAccessKeys(ctx context.Context,
 	in *api.ListUserAccessKeysInput) (*api.ListUserAccessKeysOutput, error) {
 	log.AddContext(ctx).Infof("start to list user access keys, input is [%+v]", in)
 
+	out := &api.ListUserAccessKeysOutput{}
+	var marker string
+	for {
+		paramMap := make(map[string]string, 0)
+		paramMap[actionKey] = listUserAccessKeys
+		paramMap[userNameKey] = in.UserName
+		if marker != "" {
+			paramMap[markerKey] = marker
+		}
+		body, err := pec.Call(ctx, paramMap)
+		if err != nil {
+			if errors.Is(err, errNoSuchUser) {
+				msg := fmt.Sprintf("user [%s] is not exist", in.UserName)
+				log.AddContext(ctx).Infof(msg)
+				return &api.ListUserAccessKeysOutput{}, nil
+			}
+
+			return nil, err
+		}
+
+		resp := &listAccessKeysResponse{}
+		err = xml.Unmarshal(body, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range resp.ListAccessKeysResult.AccessKeyMetadata.Members {
+			out.AccessKeys = append(out.AccessKeys, m.AccessKeyId)
+		}
+
+		result := resp.ListAccessKeysResult
+		if !result.IsTruncated || result.Marker == "" {
+			log.AddContext(ctx).Infof("list user access keys success, storage request id is [%s]",
+				resp.ResponseMetadata.RequestId)
+			return out, nil
+		}
+		marker = result.Marker
+	}
+}
+
+// UpdateAccessKeyStatus is used to activate or deactivate user access key on backend
+func (pec *Client) UpdateAccessKeyStatus(ctx context.Context,
+	in *api.UpdateAccessKeyStatusInput) (*api.UpdateAccessKeyStatusOutput, error) {
+	log.AddContext(ctx).Infof("start to update access key status, input is [%+v]", in)
+
 	paramMap := make(map[string]string, 0)
-	paramMap[actionKey] = listUserAccessKeys
+	paramMap[actionKey] = updateAccessKey
 	paramMap[userNameKey] = in.UserName
+	paramMap[accessKeyIdKey] = in.AccessKeyId
+	paramMap[statusKey] = string(in.Status)
 	body, err := pec.Call(ctx, paramMap)
 	if err != nil {
-		if errors.Is(err, errNoSuchUser) {
-			msg := fmt.Sprintf("user [%s] is not exist", in.UserName)
-			log.AddContext(ctx).Infof(msg)
-			return &api.ListUserAccessKeysOutput{}, nil
-		}
-
 		return nil, err
 	}
 
-	resp := &listAccessKeysResponse{}
+	resp := &updateAccessKeyResponse{}
 	err = xml.Unmarshal(body, resp)
 	if err != nil {
 		return nil, err
 	}
 
-	var accessKeys []string
-	for _, m := range resp.ListAccessKeysResult.AccessKeyMetadata.Members {
-		accessKeys = append(accessKeys, m.AccessKeyId)
-	}
-
-	log.AddContext(ctx).Infof("list user access keys success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
-	return &api.ListUserAccessKeysOutput{
-		AccessKeys: accessKeys,
-	}, nil
+	log.AddContext(ctx).Infof("update access key status success, storage request id is [%s]",
+		resp.ResponseMetadata.RequestId)
+	return &api.UpdateAccessKeyStatusOutput{}, nil
 }
@@ -323,3 +323,95 @@ func TestClient_ListUserAccessKeys_NoUser(t *testing.T) {
 		mock.Reset()
 	})
 }
+
+func TestClient_UpdateAccessKeyStatus_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.UpdateAccessKeyStatusInput{UserName: "user-demo", AccessKeyId: "ak-demo",
+		Status: api.AccessKeyStatusInactive}
+	body := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<UpdateAccessKeyResponse>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>7a62c49f-347e-4fc4-9331-6e8eEXAMPLE</RequestId>\n" +
+			"</ResponseMetadata>\n" +
+			"</UpdateAccessKeyResponse>")
+
+	want := &api.UpdateAccessKeyStatusOutput{}
+	var gotStatus string
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			gotStatus = param[statusKey]
+			return body, nil
+		})
+
+	// act
+	got, gotErr := c.UpdateAccessKeyStatus(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil || gotStatus != "Inactive" {
+		t.Errorf("TestClient_UpdateAccessKeyStatus_Success failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil, gotStatus= [%s]", got, want, gotErr, gotStatus)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestClient_ListUserAccessKeys_Truncated(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.ListUserAccessKeysInput{UserName: "user-demo"}
+	firstPage := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<ListAccessKeysResponse>\n" +
+			"<ListAccessKeysResult>\n" +
+			"<AccessKeyMetadata>\n" +
+			"<member><AccessKeyId>ak-1</AccessKeyId></member>\n" +
+			"</AccessKeyMetadata>\n" +
+			"<IsTruncated>true</IsTruncated>\n" +
+			"<Marker>marker-1</Marker>\n" +
+			"</ListAccessKeysResult>\n" +
+			"</ListAccessKeysResponse>")
+	lastPage := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<ListAccessKeysResponse>\n" +
+			"<ListAccessKeysResult>\n" +
+			"<AccessKeyMetadata>\n" +
+			"<member><AccessKeyId>ak-2</AccessKeyId></member>\n" +
+			"</AccessKeyMetadata>\n" +
+			"<IsTruncated>false</IsTruncated>\n" +
+			"</ListAccessKeysResult>\n" +
+			"</ListAccessKeysResponse>")
+
+	want := &api.ListUserAccessKeysOutput{AccessKeys: []string{"ak-1", "ak-2"}}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			if param[markerKey] == "marker-1" {
+				return lastPage, nil
+			}
+			return firstPage, nil
+		})
+
+	// act
+	got, gotErr := c.ListUserAccessKeys(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_ListUserAccessKeys_Truncated failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
@@ -0,0 +1,129 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poe provides poe client and poe apis
+package poe
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+	"github.com/huawei/cosi-driver/pkg/utils/log"
+)
+
+const (
+	putUserPolicyAction    = "PutUserPolicy"
+	getUserPolicyAction    = "GetUserPolicy"
+	deleteUserPolicyAction = "DeleteUserPolicy"
+
+	policyNameKey     = "PolicyName"
+	policyDocumentKey = "PolicyDocument"
+)
+
+// PutUserPolicy is used to put user inline policy on backend
+func (pec *Client) PutUserPolicy(ctx context.Context, in *api.PutUserPolicyInput) (*api.PutUserPolicyOutput, error) {
+	log.AddContext(ctx).Infof("start to put user policy, user is [%s], policy is [%s]", in.UserName, in.PolicyName)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = putUserPolicyAction
+	paramMap[userNameKey] = in.UserName
+	paramMap[policyNameKey] = in.PolicyName
+	paramMap[policyDocumentKey] = in.PolicyDocument
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &putUserPolicyResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.AddContext(ctx).Infof("put user policy success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
+	return &api.PutUserPolicyOutput{}, nil
+}
+
+// GetUserPolicy is used to get user inline policy on backend, returns nil if user or policy not exist
+func (pec *Client) GetUserPolicy(ctx context.Context, in *api.GetUserPolicyInput) (*api.GetUserPolicyOutput, error) {
+	log.AddContext(ctx).Infof("start to get user policy, input is [%+v]", in)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = getUserPolicyAction
+	paramMap[userNameKey] = in.UserName
+	paramMap[policyNameKey] = in.PolicyName
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		if errors.Is(err, errNoSuchUserPolicy) {
+			msg := fmt.Sprintf("user policy [%s/%s] not exist", in.UserName, in.PolicyName)
+			log.AddContext(ctx).Infof(msg)
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	resp := &getUserPolicyResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// the policy document is url encoded in response
+	document, err := url.QueryUnescape(resp.GetUserPolicyResult.PolicyDocument)
+	if err != nil {
+		document = resp.GetUserPolicyResult.PolicyDocument
+	}
+
+	log.AddContext(ctx).Infof("get user policy success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
+	return &api.GetUserPolicyOutput{
+		UserName:       resp.GetUserPolicyResult.UserName,
+		PolicyName:     resp.GetUserPolicyResult.PolicyName,
+		PolicyDocument: document,
+	}, nil
+}
+
+// DeleteUserPolicy is used to delete user inline policy on backend
+func (pec *Client) DeleteUserPolicy(ctx context.Context,
+	in *api.DeleteUserPolicyInput) (*api.DeleteUserPolicyOutput, error) {
+	log.AddContext(ctx).Infof("start to delete user policy, input is [%+v]", in)
+
+	paramMap := make(map[string]string, 0)
+	paramMap[actionKey] = deleteUserPolicyAction
+	paramMap[userNameKey] = in.UserName
+	paramMap[policyNameKey] = in.PolicyName
+	body, err := pec.Call(ctx, paramMap)
+	if err != nil {
+		if errors.Is(err, errNoSuchUserPolicy) {
+			msg := fmt.Sprintf("user policy [%s/%s] is not exist", in.UserName, in.PolicyName)
+			log.AddContext(ctx).Infof(msg)
+			return &api.DeleteUserPolicyOutput{}, nil
+		}
+
+		return nil, err
+	}
+
+	resp := &deleteUserPolicyResponse{}
+	err = xml.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	log.AddContext(ctx).Infof("delete user policy success, storage request id is [%s]", resp.ResponseMetadata.RequestId)
+	return &api.DeleteUserPolicyOutput{}, nil
+}
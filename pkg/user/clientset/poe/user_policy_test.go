@@ -0,0 +1,179 @@
+/*
+ Copyright (c) Huawei Technologies Co., Ltd. 2026. All rights reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poe provides poe client and poe apis
+package poe
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+
+	"github.com/huawei/cosi-driver/pkg/user/api"
+)
+
+func TestClient_PutUserPolicy_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.PutUserPolicyInput{UserName: "user-demo", PolicyName: "policy-demo", PolicyDocument: "{}"}
+	body := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<PutUserPolicyResponse>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>38284051-12e5-4a0d-bba3-c2fdca506405</RequestId>\n" +
+			"</ResponseMetadata>\n" +
+			"</PutUserPolicyResponse>")
+	want := &api.PutUserPolicyOutput{}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			return body, nil
+		})
+
+	// act
+	got, gotErr := c.PutUserPolicy(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_PutUserPolicy_Success failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestClient_GetUserPolicy_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.GetUserPolicyInput{UserName: "user-demo", PolicyName: "policy-demo"}
+	body := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<GetUserPolicyResponse>\n" +
+			"<GetUserPolicyResult>\n" +
+			"<UserName>user-demo</UserName>\n" +
+			"<PolicyName>policy-demo</PolicyName>\n" +
+			"<PolicyDocument>%7B%22Version%22%3A%222012-10-17%22%7D</PolicyDocument>\n" +
+			"</GetUserPolicyResult>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>38284051-12e5-4a0d-bba3-c2fdca506405</RequestId>\n" +
+			"</ResponseMetadata>\n" +
+			"</GetUserPolicyResponse>")
+	want := &api.GetUserPolicyOutput{UserName: "user-demo", PolicyName: "policy-demo",
+		PolicyDocument: "{\"Version\":\"2012-10-17\"}"}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			return body, nil
+		})
+
+	// act
+	got, gotErr := c.GetUserPolicy(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_GetUserPolicy_Success failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestClient_GetUserPolicy_NotExist(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.GetUserPolicyInput{UserName: "user-demo", PolicyName: "policy-demo"}
+	errBody := []byte(
+		"<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"yes\"?>\n" +
+			"<ErrorResponse>\n" +
+			"<Error>\n" +
+			"<Code>NoSuchEntity</Code>\n" +
+			"<Message>The request was rejected because it referenced an entity that does not exist.</Message>\n" +
+			"</Error>\n" +
+			"<RequestId>5e8141b8-601d-460b-af2d-dea105442f26</RequestId>\n" +
+			"</ErrorResponse>\n")
+
+	mockError := handleErrorResponse(errBody)
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			return nil, mockError
+		})
+
+	// act
+	got, gotErr := c.GetUserPolicy(ctx, in)
+
+	// assert
+	if got != nil || gotErr != nil {
+		t.Errorf("TestClient_GetUserPolicy_NotExist failed, got= [%v], want= nil, "+
+			"gotErr= [%v], wantErr= nil", got, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
+
+func TestClient_DeleteUserPolicy_NotExist(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.DeleteUserPolicyInput{UserName: "user-demo", PolicyName: "policy-demo"}
+	errBody := []byte(
+		"<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"yes\"?>\n" +
+			"<ErrorResponse>\n" +
+			"<Error>\n" +
+			"<Code>NoSuchEntity</Code>\n" +
+			"<Message>The request was rejected because it referenced an entity that does not exist.</Message>\n" +
+			"</Error>\n" +
+			"<RequestId>5e8141b8-601d-460b-af2d-dea105442f26</RequestId>\n" +
+			"</ErrorResponse>\n")
+	want := &api.DeleteUserPolicyOutput{}
+
+	mockError := handleErrorResponse(errBody)
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			return nil, mockError
+		})
+
+	// act
+	got, gotErr := c.DeleteUserPolicy(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_DeleteUserPolicy_NotExist failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
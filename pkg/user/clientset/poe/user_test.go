@@ -269,3 +269,76 @@ func TestClient_DeleteUser_InternalFailed(t *testing.T) {
 		mock.Reset()
 	})
 }
+
+func TestClient_ListUsers_Success(t *testing.T) {
+	// arrange
+	ctx := context.TODO()
+	c := &Client{}
+	in := &api.ListUsersInput{PathPrefix: "/cosi/", NamePrefix: "ba-"}
+	firstPage := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<ListUsersResponse>\n" +
+			"<ListUsersResult>\n" +
+			"<Users>\n" +
+			"<member>\n" +
+			"<UserName>ba-uid-1</UserName>\n" +
+			"<Path>/cosi/</Path>\n" +
+			"<UserId>user-id-1</UserId>\n" +
+			"<Arn>arn:aws:iam::account:user/cosi/ba-uid-1</Arn>\n" +
+			"</member>\n" +
+			"<member>\n" +
+			"<UserName>admin</UserName>\n" +
+			"<Path>/cosi/</Path>\n" +
+			"</member>\n" +
+			"</Users>\n" +
+			"<IsTruncated>true</IsTruncated>\n" +
+			"<Marker>marker-1</Marker>\n" +
+			"</ListUsersResult>\n" +
+			"</ListUsersResponse>")
+	lastPage := []byte(
+		"<?xml version=\"1.0\"?>\n" +
+			"<ListUsersResponse>\n" +
+			"<ListUsersResult>\n" +
+			"<Users>\n" +
+			"<member>\n" +
+			"<UserName>ba-uid-2</UserName>\n" +
+			"<Path>/cosi/</Path>\n" +
+			"<UserId>user-id-2</UserId>\n" +
+			"<Arn>arn:aws:iam::account:user/cosi/ba-uid-2</Arn>\n" +
+			"</member>\n" +
+			"</Users>\n" +
+			"<IsTruncated>false</IsTruncated>\n" +
+			"</ListUsersResult>\n" +
+			"<ResponseMetadata>\n" +
+			"<RequestId>7a62c49f-347e-4fc4-9331-6e8eEXAMPLE</RequestId>\n" +
+			"</ResponseMetadata>\n" +
+			"</ListUsersResponse>")
+
+	want := &api.ListUsersOutput{Users: []api.User{
+		{UserName: "ba-uid-1", UserID: "user-id-1", Arn: "arn:aws:iam::account:user/cosi/ba-uid-1", Path: "/cosi/"},
+		{UserName: "ba-uid-2", UserID: "user-id-2", Arn: "arn:aws:iam::account:user/cosi/ba-uid-2", Path: "/cosi/"},
+	}}
+
+	// mock
+	mock := gomonkey.ApplyMethod(reflect.TypeOf(c), "Call",
+		func(_ *Client, ctx context.Context, param map[string]string) ([]byte, error) {
+			if param[markerKey] == "marker-1" {
+				return lastPage, nil
+			}
+			return firstPage, nil
+		})
+
+	// act
+	got, gotErr := c.ListUsers(ctx, in)
+
+	// assert
+	if !reflect.DeepEqual(want, got) || gotErr != nil {
+		t.Errorf("TestClient_ListUsers_Success failed, got= [%v], want= [%v], "+
+			"gotErr= [%v], wantErr= nil", got, want, gotErr)
+	}
+
+	// cleanup
+	t.Cleanup(func() {
+		mock.Reset()
+	})
+}
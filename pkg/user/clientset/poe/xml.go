@@ -53,6 +53,33 @@ type getUserResult struct {
 	User    user     `xml:"User"`
 }
 
+type listUsersResponse struct {
+	XMLName          xml.Name         `xml:"ListUsersResponse"`
+	ListUsersResult  listUsersResult  `xml:"ListUsersResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+type listUsersResult struct {
+	XMLName     xml.Name    `xml:"ListUsersResult"`
+	Users       userMembers `xml:"Users"`
+	IsTruncated bool        `xml:"IsTruncated"`
+	Marker      string      `xml:"Marker"`
+}
+
+type userMembers struct {
+	XMLName xml.Name     `xml:"Users"`
+	Members []userMember `xml:"member"`
+}
+
+type userMember struct {
+	XMLName    xml.Name `xml:"member"`
+	UserName   string   `xml:"UserName"`
+	Path       string   `xml:"Path"`
+	UserID     string   `xml:"UserId"`
+	Arn        string   `xml:"Arn"`
+	CreateDate string   `xml:"CreateDate"`
+}
+
 type deleteUserResponse struct {
 	XMLName          xml.Name         `xml:"DeleteUserResponse"`
 	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
@@ -84,6 +111,11 @@ type deleteUserAccessResponse struct {
 	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
 }
 
+type updateAccessKeyResponse struct {
+	XMLName          xml.Name         `xml:"UpdateAccessKeyResponse"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
 type codeError struct {
 	Code    string `xml:"Code"`
 	Message string `xml:"Message"`
@@ -104,6 +136,8 @@ type listAccessKeysResponse struct {
 type listAccessKeysResult struct {
 	XMLName           xml.Name          `xml:"ListAccessKeysResult"`
 	AccessKeyMetadata accessKeyMetadata `xml:"AccessKeyMetadata"`
+	IsTruncated       bool              `xml:"IsTruncated"`
+	Marker            string            `xml:"Marker"`
 }
 
 type accessKeyMetadata struct {
@@ -119,3 +153,92 @@ type member struct {
 	CreateDate  string   `xml:"CreateDate"`
 	UserName    string   `xml:"UserName"`
 }
+
+type putUserPolicyResponse struct {
+	XMLName          xml.Name         `xml:"PutUserPolicyResponse"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+type getUserPolicyResponse struct {
+	XMLName             xml.Name            `xml:"GetUserPolicyResponse"`
+	GetUserPolicyResult getUserPolicyResult `xml:"GetUserPolicyResult"`
+	ResponseMetadata    responseMetadata    `xml:"ResponseMetadata"`
+}
+
+type getUserPolicyResult struct {
+	XMLName        xml.Name `xml:"GetUserPolicyResult"`
+	UserName       string   `xml:"UserName"`
+	PolicyName     string   `xml:"PolicyName"`
+	PolicyDocument string   `xml:"PolicyDocument"`
+}
+
+type deleteUserPolicyResponse struct {
+	XMLName          xml.Name         `xml:"DeleteUserPolicyResponse"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+type group struct {
+	XMLName    xml.Name `xml:"Group"`
+	GroupName  string   `xml:"GroupName"`
+	Path       string   `xml:"Path"`
+	GroupID    string   `xml:"GroupId"`
+	Arn        string   `xml:"Arn"`
+	CreateDate string   `xml:"CreateDate"`
+}
+
+type createGroupResponse struct {
+	XMLName           xml.Name          `xml:"CreateGroupResponse"`
+	CreateGroupResult createGroupResult `xml:"CreateGroupResult"`
+	ResponseMetadata  responseMetadata  `xml:"ResponseMetadata"`
+}
+
+type createGroupResult struct {
+	XMLName xml.Name `xml:"CreateGroupResult"`
+	Group   group    `xml:"Group"`
+}
+
+type getGroupResponse struct {
+	XMLName          xml.Name         `xml:"GetGroupResponse"`
+	GetGroupResult   getGroupResult   `xml:"GetGroupResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+type getGroupResult struct {
+	XMLName xml.Name `xml:"GetGroupResult"`
+	Group   group    `xml:"Group"`
+}
+
+type addUserToGroupResponse struct {
+	XMLName          xml.Name         `xml:"AddUserToGroupResponse"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+type removeUserFromGroupResponse struct {
+	XMLName          xml.Name         `xml:"RemoveUserFromGroupResponse"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+type listGroupsForUserResponse struct {
+	XMLName                 xml.Name                `xml:"ListGroupsForUserResponse"`
+	ListGroupsForUserResult listGroupsForUserResult `xml:"ListGroupsForUserResult"`
+	ResponseMetadata        responseMetadata        `xml:"ResponseMetadata"`
+}
+
+type listGroupsForUserResult struct {
+	XMLName     xml.Name     `xml:"ListGroupsForUserResult"`
+	Groups      groupMembers `xml:"Groups"`
+	IsTruncated bool         `xml:"IsTruncated"`
+	Marker      string       `xml:"Marker"`
+}
+
+type groupMembers struct {
+	XMLName xml.Name      `xml:"Groups"`
+	Members []groupMember `xml:"member"`
+}
+
+type groupMember struct {
+	XMLName   xml.Name `xml:"member"`
+	GroupName string   `xml:"GroupName"`
+	GroupID   string   `xml:"GroupId"`
+	Arn       string   `xml:"Arn"`
+}
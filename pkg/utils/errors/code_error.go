@@ -23,6 +23,10 @@ type codeType int
 
 const (
 	notExistCode codeType = iota
+	resourceExhaustedCode
+	invalidArgumentCode
+	alreadyExistsCode
+	unavailableCode
 )
 
 // CodeError defines error with code
@@ -50,3 +54,63 @@ func IsResourceNotExistErr(err error) bool {
 
 	return codeErr.code == notExistCode
 }
+
+// NewResourceExhaustedErr return a resource exhausted type err
+func NewResourceExhaustedErr(msg string) *CodeError {
+	return &CodeError{code: resourceExhaustedCode, message: msg}
+}
+
+// IsResourceExhaustedErr judge whether this error is resource exhausted type
+func IsResourceExhaustedErr(err error) bool {
+	codeErr := &CodeError{}
+	if !errors.As(err, &codeErr) {
+		return false
+	}
+
+	return codeErr.code == resourceExhaustedCode
+}
+
+// NewInvalidArgumentErr return an invalid argument type err
+func NewInvalidArgumentErr(msg string) *CodeError {
+	return &CodeError{code: invalidArgumentCode, message: msg}
+}
+
+// IsInvalidArgumentErr judge whether this error is invalid argument type
+func IsInvalidArgumentErr(err error) bool {
+	codeErr := &CodeError{}
+	if !errors.As(err, &codeErr) {
+		return false
+	}
+
+	return codeErr.code == invalidArgumentCode
+}
+
+// NewAlreadyExistsErr return an already exists type err
+func NewAlreadyExistsErr(msg string) *CodeError {
+	return &CodeError{code: alreadyExistsCode, message: msg}
+}
+
+// IsAlreadyExistsErr judge whether this error is already exists type
+func IsAlreadyExistsErr(err error) bool {
+	codeErr := &CodeError{}
+	if !errors.As(err, &codeErr) {
+		return false
+	}
+
+	return codeErr.code == alreadyExistsCode
+}
+
+// NewUnavailableErr return an unavailable type err, which means the operation should be retried later
+func NewUnavailableErr(msg string) *CodeError {
+	return &CodeError{code: unavailableCode, message: msg}
+}
+
+// IsUnavailableErr judge whether this error is unavailable type
+func IsUnavailableErr(err error) bool {
+	codeErr := &CodeError{}
+	if !errors.As(err, &codeErr) {
+		return false
+	}
+
+	return codeErr.code == unavailableCode
+}
@@ -45,3 +45,81 @@ func TestIsResourceNotExistErr_False(t *testing.T) {
 		t.Errorf("TestIsResourceNotExistErr_False failed, got= [%v], want= false", got)
 	}
 }
+
+func TestIsResourceExhaustedErr_True(t *testing.T) {
+	// arrange
+	err := fmt.Errorf("wrapped: %w", NewResourceExhaustedErr("mock-err"))
+
+	// act
+	got := IsResourceExhaustedErr(err)
+
+	// assert
+	if got != true {
+		t.Errorf("TestIsResourceExhaustedErr_True failed, got= [%v], want= true", got)
+	}
+}
+
+func TestIsResourceExhaustedErr_NotExistErr(t *testing.T) {
+	// arrange
+	err := NewResourceNotExistErr("mock-err")
+
+	// act
+	got := IsResourceExhaustedErr(err)
+
+	// assert
+	if got != false {
+		t.Errorf("TestIsResourceExhaustedErr_NotExistErr failed, got= [%v], want= false", got)
+	}
+}
+
+func TestIsInvalidArgumentErr_True(t *testing.T) {
+	// arrange
+	err := fmt.Errorf("wrapped: %w", NewInvalidArgumentErr("mock-err"))
+
+	// act
+	got := IsInvalidArgumentErr(err)
+
+	// assert
+	if got != true {
+		t.Errorf("TestIsInvalidArgumentErr_True failed, got= [%v], want= true", got)
+	}
+}
+
+func TestIsAlreadyExistsErr_True(t *testing.T) {
+	// arrange
+	err := fmt.Errorf("wrapped: %w", NewAlreadyExistsErr("mock-err"))
+
+	// act
+	got := IsAlreadyExistsErr(err)
+
+	// assert
+	if got != true {
+		t.Errorf("TestIsAlreadyExistsErr_True failed, got= [%v], want= true", got)
+	}
+}
+
+func TestIsAlreadyExistsErr_InvalidArgumentErr(t *testing.T) {
+	// arrange
+	err := NewInvalidArgumentErr("mock-err")
+
+	// act
+	got := IsAlreadyExistsErr(err)
+
+	// assert
+	if got != false {
+		t.Errorf("TestIsAlreadyExistsErr_InvalidArgumentErr failed, got= [%v], want= false", got)
+	}
+}
+
+func TestIsUnavailableErr_True(t *testing.T) {
+	// arrange
+	err := fmt.Errorf("wrapped: %w", NewUnavailableErr("mock-err"))
+
+	// act
+	got := IsUnavailableErr(err)
+
+	// assert
+	if got != true {
+		t.Errorf("TestIsUnavailableErr_True failed, got= [%v], want= true", got)
+	}
+}
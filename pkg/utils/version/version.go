@@ -35,15 +35,18 @@ import (
 var mutex sync.Mutex
 
 const (
-	defaultNamespace = "huawei-cosi"
-	envNameSpace     = "env-namepsace"
+	// DefaultNamespace is the namespace of the driver if it is not set by the environment
+	DefaultNamespace = "huawei-cosi"
+
+	// EnvNamespace is the environment variable of the namespace which the driver is deployed in
+	EnvNamespace = "env-namepsace"
 )
 
 // RegisterVersion used for register container version to configmap
 func RegisterVersion(containerName, version, kubeConfigPath string) error {
-	namespace := os.Getenv(envNameSpace)
+	namespace := os.Getenv(EnvNamespace)
 	if namespace == "" {
-		namespace = defaultNamespace
+		namespace = DefaultNamespace
 	}
 
 	kubeConfig, err := utils.GetKubeConfig(kubeConfigPath)